@@ -23,17 +23,36 @@ type DueOnDate struct {
 	At time.Time
 }
 
+// DueOnWeekday is the next given weekday after today, so "fri" on a Friday
+// means a week later.
+type DueOnWeekday struct {
+	Weekday time.Weekday
+}
+
 type AddCommand struct {
-	Title    string
-	Priority tasks.Priority
-	Due      IntoDueDate
-	Category string
+	Title       string
+	Priority    tasks.Priority
+	Due         IntoDueDate
+	Category    string
+	Description string
+	Notes       []string
+	Tags        []string
 }
 
 func (a *AddCommand) Execute(m tasks.Manager) (string, error) {
+	opts := make([]tasks.TaskOption, 0)
+	if a.Description != "" {
+		opts = append(opts, tasks.WithDescription(a.Description))
+	}
+	if len(a.Notes) > 0 {
+		opts = append(opts, tasks.WithNotes(a.Notes...))
+	}
+	if len(a.Tags) > 0 {
+		opts = append(opts, tasks.WithTags(a.Tags...))
+	}
 	added, err := m.AddTask(a.Title, a.Priority, func(t time.Time) tasks.DueDate {
 		return a.Due.IntoDueDate(t)
-	}, a.Category)
+	}, a.Category, opts...)
 
 	if err != nil {
 		return "", err
@@ -56,3 +75,8 @@ func (d *DueInDays) IntoDueDate(t time.Time) tasks.DueDate {
 func (d *DueOnDate) IntoDueDate(t time.Time) tasks.DueDate {
 	return tasks.DueDate(d.At)
 }
+
+func (d *DueOnWeekday) IntoDueDate(t time.Time) tasks.DueDate {
+	days := (int(d.Weekday)-int(t.Weekday())+6)%7 + 1
+	return tasks.DueDate(t.AddDate(0, 0, days))
+}
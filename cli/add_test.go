@@ -127,6 +127,36 @@ func TestAddExecuteTableDriven(t *testing.T) {
 			},
 			want: "Added task 'Call dentist' successfully",
 		},
+		{
+			name: "add with description, notes and tags",
+			addCmd: AddCommand{
+				Title:       "Call dentist",
+				Priority:    tasks.Medium,
+				Due:         &DueToday{},
+				Description: "Book a cleaning",
+				Notes:       []string{"Ask about x-rays", "Morning only"},
+				Tags:        []string{"health", "phone"},
+			},
+			mockReturn: &tasks.Task{
+				Id:          1,
+				Title:       "Call dentist",
+				Priority:    tasks.Medium,
+				DueDate:     tasks.DueDate(testTime),
+				Status:      tasks.Pending,
+				Description: "Book a cleaning",
+				Notes:       []string{"Ask about x-rays", "Morning only"},
+				Tags:        []string{"health", "phone"},
+			},
+			wantCall: addCall{
+				title:       "Call dentist",
+				priority:    tasks.Medium,
+				dueDate:     tasks.DueDate(testTime),
+				description: "Book a cleaning",
+				notes:       "Ask about x-rays|Morning only",
+				tags:        "health,phone",
+			},
+			want: "Added task 'Call dentist' successfully",
+		},
 	}
 	var manager tasks.Manager = &m
 
@@ -189,3 +219,24 @@ func TestAddExecuteErrorsTableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestDueOnWeekdayTableDriven(t *testing.T) {
+	friday := time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		weekday time.Weekday
+		want    time.Time
+	}{
+		{time.Saturday, time.Date(2024, 4, 13, 0, 0, 0, 0, time.UTC)},
+		{time.Monday, time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Thursday, time.Date(2024, 4, 18, 0, 0, 0, 0, time.UTC)},
+		{time.Friday, time.Date(2024, 4, 19, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.weekday.String(), func(t *testing.T) {
+			due := (&DueOnWeekday{Weekday: tt.weekday}).IntoDueDate(friday)
+			if !time.Time(due).Equal(tt.want) {
+				t.Fatalf("wanted=%v, got=%v", tt.want, time.Time(due))
+			}
+		})
+	}
+}
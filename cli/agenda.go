@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type AgendaCommand struct {
+	PriorityFilter *tasks.Priority
+	CategoryFilter string
+	Table          tasks.TableOptions
+}
+
+type agendaFlags struct {
+	priority *string
+	category *string
+	table    *tableFlags
+}
+
+func newAgendaFlagSet() (*flag.FlagSet, *agendaFlags) {
+	fs := newFlagSet("agenda")
+	f := &agendaFlags{
+		priority: fs.String("priority", "", "Priority filter: low, medium, high"),
+		category: fs.String("category", "", "Category filter"),
+		table:    addTableFlags(fs),
+	}
+	alias(fs, "p", "priority")
+	alias(fs, "c", "category")
+	return fs, f
+}
+
+func parseAgendaCmd(a []string) (Command, error) {
+	agendaFlagSet, f := newAgendaFlagSet()
+	args, err := parseArgs(agendaFlagSet, a)
+	if err != nil {
+		return flagError("agenda", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("agenda error: unexpected argument '%s'", args[0])
+	}
+	priorityFilter, err := parsePriorityFilter(*f.priority)
+	if err != nil {
+		return nil, err
+	}
+	table, err := f.table.options()
+	if err != nil {
+		return nil, err
+	}
+	return &AgendaCommand{PriorityFilter: priorityFilter, CategoryFilter: *f.category, Table: table}, nil
+}
+
+func (a *AgendaCommand) Execute(m tasks.Manager) (string, error) {
+	pending := tasks.Pending
+	t, err := m.ListTasks(&pending, a.PriorityFilter, a.CategoryFilter, false)
+	if err != nil {
+		return "", err
+	}
+	opts := tableOptionsFor(m, a.Table)
+	sections := []string{}
+	for _, bucket := range tasks.Agenda(t, m.Now(), weekStart()) {
+		if len(bucket.Tasks) == 0 {
+			continue
+		}
+		heading := fmt.Sprintf("%s (%d)", bucket.Name, len(bucket.Tasks))
+		sections = append(sections, heading+"\n"+tasks.RenderTableWith(bucket.Tasks, opts))
+	}
+	if len(sections) == 0 {
+		return "No pending tasks", nil
+	}
+	return strings.TrimRight(strings.Join(sections, "\n"), "\n"), nil
+}
+
+// weekStart returns the first day of the week from the week-start setting.
+func weekStart() time.Weekday {
+	if userConfig.WeekStart == "sunday" {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+type CalendarCommand struct {
+	Month          string
+	CategoryFilter string
+	Counts         bool
+	All            bool
+	Width          int
+}
+
+type calendarFlags struct {
+	category *string
+	counts   *bool
+	all      *bool
+	width    *int
+}
+
+func newCalendarFlagSet() (*flag.FlagSet, *calendarFlags) {
+	fs := newFlagSet("calendar")
+	f := &calendarFlags{
+		category: fs.String("category", "", "Category filter"),
+		counts:   fs.Bool("counts", false, "Show the number of tasks on each day instead of their titles"),
+		all:      fs.Bool("all", false, "Include completed tasks"),
+		width:    fs.Int("width", 0, "Maximum calendar width (default: terminal width)"),
+	}
+	alias(fs, "c", "category")
+	alias(fs, "a", "all")
+	return fs, f
+}
+
+func parseCalendarCmd(a []string) (Command, error) {
+	calendarFlagSet, f := newCalendarFlagSet()
+	args, err := parseArgs(calendarFlagSet, a)
+	if err != nil {
+		return flagError("calendar", err)
+	}
+	if len(args) > 1 {
+		return nil, fmt.Errorf("calendar error: unexpected argument '%s'", args[1])
+	}
+	month := ""
+	if len(args) == 1 {
+		month = args[0]
+		if _, err := parseMonth(month); err != nil {
+			return nil, err
+		}
+	}
+	if *f.width < 0 {
+		return nil, fmt.Errorf("invalid width: cannot be negative")
+	}
+	return &CalendarCommand{Month: month, CategoryFilter: *f.category, Counts: *f.counts, All: *f.all, Width: *f.width}, nil
+}
+
+var monthNames = []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+
+// parseMonth accepts yyyy-MM, a month name in the current year, or 'next'
+// and 'last' relative to now. The empty string is the current month. The
+// syntax is checked here, and the returned function resolves the month
+// against a clock.
+func parseMonth(s string) (func(now time.Time) time.Time, error) {
+	current := func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	switch s = strings.ToLower(s); s {
+	case "", "this":
+		return current, nil
+	case "next":
+		return func(now time.Time) time.Time { return current(now).AddDate(0, 1, 0) }, nil
+	case "last", "prev":
+		return func(now time.Time) time.Time { return current(now).AddDate(0, -1, 0) }, nil
+	}
+	for i, name := range monthNames {
+		if s == name || s == name[:3] {
+			return func(now time.Time) time.Time {
+				return time.Date(now.Year(), time.Month(i+1), 1, 0, 0, 0, 0, time.UTC)
+			}, nil
+		}
+	}
+	at, err := time.Parse("2006-01", s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid month format: must be yyyy-MM, a month name, 'next' or 'last'")
+	}
+	return func(time.Time) time.Time { return at }, nil
+}
+
+// Execute resolves the month against the manager's clock, so 'next' and
+// 'last' match the month being shown.
+func (c *CalendarCommand) Execute(m tasks.Manager) (string, error) {
+	resolve, err := parseMonth(c.Month)
+	if err != nil {
+		return "", err
+	}
+	month := resolve(m.Now())
+	var status *tasks.Status
+	if !c.All {
+		pending := tasks.Pending
+		status = &pending
+	}
+	t, err := m.ListTasks(status, nil, c.CategoryFilter, false)
+	if err != nil {
+		return "", err
+	}
+	width := c.Width
+	if width == 0 {
+		width = terminalWidth(os.Stdout)
+	}
+	opts := tasks.CalendarOptions{WeekStart: weekStart(), Width: width, Counts: c.Counts, Now: m.Now()}
+	return strings.TrimRight(tasks.RenderCalendar(t, month, opts), "\n"), nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestParseAgendaCalendarTableDriven(t *testing.T) {
+	tests := []struct {
+		args   []string
+		want   Command
+		errMsg string
+	}{
+		{args: []string{"agenda"}, want: &AgendaCommand{}},
+		{args: []string{"agenda", "-c", "work"}, want: &AgendaCommand{CategoryFilter: "work"}},
+		{args: []string{"agenda", "today"}, errMsg: "unexpected argument 'today'"},
+		{args: []string{"agenda", "-p", "urgent"}, errMsg: "invalid priority format"},
+		{args: []string{"calendar"}, want: &CalendarCommand{}},
+		{args: []string{"calendar", "2024-05", "--counts", "-a"}, want: &CalendarCommand{Month: "2024-05", Counts: true, All: true}},
+		{args: []string{"calendar", "-c", "home", "March"}, want: &CalendarCommand{Month: "March", CategoryFilter: "home"}},
+		{args: []string{"calendar", "someday"}, errMsg: "invalid month format"},
+		{args: []string{"calendar", "may", "june"}, errMsg: "unexpected argument 'june'"},
+		{args: []string{"calendar", "--width", "-1"}, errMsg: "invalid width"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			cmd, err := Parse(&tt.args)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if agenda, ok := cmd.(*AgendaCommand); ok {
+				agenda.Table = tasks.TableOptions{}
+			}
+			if !reflect.DeepEqual(cmd, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, cmd)
+			}
+		})
+	}
+}
+
+func TestParseMonthTableDriven(t *testing.T) {
+	tests := []struct {
+		month string
+		want  time.Time
+	}{
+		{"", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"next", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"last", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Dec", time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{"february", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"2025-01", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.month, func(t *testing.T) {
+			resolve, err := parseMonth(tt.month)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if got := resolve(testTime); !got.Equal(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAgendaExecute(t *testing.T) {
+	m := newMockManager(testTime)
+	m.listNextOk = []tasks.Task{
+		{Id: 1, Title: "Late", DueDate: tasks.DueDate(testTime.AddDate(0, 0, -1))},
+		{Id: 2, Title: "Soon", DueDate: tasks.DueDate(testTime.AddDate(0, 0, 1))},
+		{Id: 3, Title: "Sunday", DueDate: tasks.DueDate(testTime.AddDate(0, 0, 4))},
+	}
+
+	got, err := (&AgendaCommand{Table: tasks.TableOptions{Columns: []string{"title"}, Style: tasks.StyleCompact}}).Execute(&m)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := "Overdue (1)\nTitle\nLate\n\nTomorrow (1)\nTitle\nSoon\n\nThis week (1)\nTitle\nSunday"
+	if got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	if len(m.listCalls) != 1 || m.listCalls[0].status == nil || *m.listCalls[0].status != tasks.Pending {
+		t.Fatalf("expected a pending list call, got %v", m.listCalls)
+	}
+
+	userConfig.WeekStart = "sunday"
+	defer func() { userConfig = Config{} }()
+	got, _ = (&AgendaCommand{Table: tasks.TableOptions{Columns: []string{"title"}, Style: tasks.StyleCompact}}).Execute(&m)
+	if !strings.Contains(got, "Later (1)\nTitle\nSunday") {
+		t.Fatalf("expected Sunday to be later when weeks start on Sunday, got:\n%s", got)
+	}
+
+	m.listNextOk = nil
+	if got, _ := (&AgendaCommand{}).Execute(&m); got != "No pending tasks" {
+		t.Fatalf("expected no pending tasks, got %q", got)
+	}
+}
+
+func TestCalendarExecute(t *testing.T) {
+	m := newMockManager(testTime)
+	m.listNextOk = []tasks.Task{{Id: 1, Title: "Pay rent", DueDate: tasks.DueDate(testTime.AddDate(0, 1, 0))}}
+
+	got, err := (&CalendarCommand{Month: "next", All: true, Width: 120}).Execute(&m)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(got, "May 2024") || !strings.Contains(got, "Pay rent") {
+		t.Fatalf("expected May with the task, got:\n%s", got)
+	}
+	if len(m.listCalls) != 1 || m.listCalls[0].status != nil {
+		t.Fatalf("expected --all to list every status, got %v", m.listCalls)
+	}
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type BatchCommand struct {
+	File     string
+	Continue bool
+	Atomic   bool
+}
+
+type batchFlags struct {
+	file   *string
+	cont   *bool
+	atomic *bool
+}
+
+func newBatchFlagSet() (*flag.FlagSet, *batchFlags) {
+	fs := newFlagSet("batch")
+	f := &batchFlags{
+		file:   fs.String("file", "", "Read commands from this file instead of stdin ('-' for stdin)"),
+		cont:   fs.Bool("continue", false, "Keep running after a command fails instead of stopping"),
+		atomic: fs.Bool("atomic", false, "Save nothing unless every command succeeds"),
+	}
+	alias(fs, "f", "file")
+	alias(fs, "k", "continue")
+	return fs, f
+}
+
+func parseBatchCmd(a []string) (Command, error) {
+	batchFlagSet, f := newBatchFlagSet()
+	args, err := parseArgs(batchFlagSet, a)
+	if err != nil {
+		return flagError("batch", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("batch error: unexpected argument '%s' (use -f to read from a file)", args[0])
+	}
+	return &BatchCommand{File: *f.file, Continue: *f.cont, Atomic: *f.atomic}, nil
+}
+
+func (b *BatchCommand) Execute(m tasks.Manager) (string, error) {
+	in := io.Reader(os.Stdin)
+	if b.File != "" && b.File != "-" {
+		file, err := os.Open(b.File)
+		if err != nil {
+			return "", fmt.Errorf("batch error: %v", err)
+		}
+		defer file.Close()
+		in = file
+	}
+	return b.run(m, in, os.Stdout, os.Stderr)
+}
+
+type batchLine struct {
+	number int
+	args   []string
+	err    error
+}
+
+// readBatchLines returns the command lines of a batch script, skipping blank
+// lines and '#' comments. Lines that cannot be split keep their error so they
+// fail in order with the rest of the script.
+func readBatchLines(r io.Reader) ([]batchLine, error) {
+	var lines []batchLine
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args, err := splitCommandLine(line)
+		if err != nil {
+			err = &UsageError{err}
+		}
+		lines = append(lines, batchLine{number: n, args: args, err: err})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("batch error: %v", err)
+	}
+	return lines, nil
+}
+
+func (b *BatchCommand) run(m tasks.Manager, in io.Reader, stdout io.Writer, stderr io.Writer) (string, error) {
+	lines, err := readBatchLines(in)
+	if err != nil {
+		return "", err
+	}
+	batcher, ok := m.(tasks.Batcher)
+	if b.Atomic && !ok {
+		return "", errors.New("batch error: --atomic is not supported by this task store")
+	}
+	if ok {
+		batcher.Begin()
+	}
+
+	succeeded, failed := 0, 0
+	var firstErr error
+	var stoppedAt int
+	for _, line := range lines {
+		output, err := OutputText, line.err
+		if err == nil {
+			output, err = execArgs(m, line.args, stdout)
+		}
+		if err == nil {
+			succeeded++
+			continue
+		}
+		failed++
+		err = fmt.Errorf("line %d: %w", line.number, err)
+		fmt.Fprintln(stderr, FormatError(err, output))
+		if firstErr == nil {
+			firstErr = err
+		}
+		if !b.Continue {
+			stoppedAt = line.number
+			break
+		}
+	}
+	skipped := len(lines) - succeeded - failed
+
+	summary := fmt.Sprintf("%d succeeded, %d failed, %d skipped", succeeded, failed, skipped)
+	if stoppedAt > 0 {
+		summary = fmt.Sprintf("stopped at line %d: %s", stoppedAt, summary)
+	}
+	if ok {
+		if b.Atomic && failed > 0 {
+			batcher.Rollback()
+			summary += "; no changes were saved"
+		} else if err := batcher.Commit(); err != nil {
+			return "", err
+		}
+	}
+	if firstErr != nil {
+		return "", &batchError{summary: summary, err: firstErr}
+	}
+	return "Batch complete: " + summary, nil
+}
+
+// batchError reports the summary of a failed batch and unwraps to the first
+// failure, so the exit code reflects what went wrong.
+type batchError struct {
+	summary string
+	err     error
+}
+
+func (e *batchError) Error() string {
+	return "batch failed, " + e.summary
+}
+
+func (e *batchError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestParseBatchTableDriven(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		want   BatchCommand
+		errMsg string
+	}{
+		{name: "stdin", args: []string{"batch"}, want: BatchCommand{}},
+		{name: "file", args: []string{"batch", "-f", "commands.txt"}, want: BatchCommand{File: "commands.txt"}},
+		{name: "all flags", args: []string{"batch", "--file=-", "-k", "--atomic"}, want: BatchCommand{File: "-", Continue: true, Atomic: true}},
+		{name: "positional", args: []string{"batch", "commands.txt"}, errMsg: "unexpected argument 'commands.txt'"},
+		{name: "unknown flag", args: []string{"batch", "--dry-run"}, errMsg: "batch error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := Parse(&tt.args)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected err containing %q, got cmd=%v err=%v", tt.errMsg, cmd, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if *cmd.(*BatchCommand) != tt.want {
+				t.Fatalf("wanted=%v, got=%v", tt.want, cmd)
+			}
+		})
+	}
+}
+
+const batchScript = `# generated tasks
+add 'Call dentist' -p high
+
+add "Deploy release" -c work #release
+complete nine
+delete "unterminated
+complete 1
+`
+
+func TestBatchRun(t *testing.T) {
+	mock := newMockManager(testTime)
+	tests := []struct {
+		name          string
+		cmd           BatchCommand
+		batching      bool
+		wantAdds      int
+		wantCompletes int
+		wantCommits   int
+		wantRollbacks int
+		wantResult    string
+		wantErr       string
+		wantStderr    []string
+	}{
+		{
+			name:          "stop on error",
+			wantAdds:      2,
+			wantCompletes: 0,
+			wantErr:       "batch failed, stopped at line 5: 2 succeeded, 1 failed, 2 skipped",
+			wantStderr:    []string{"Error: line 5: complete error: invalid ID format"},
+		},
+		{
+			name:          "continue",
+			cmd:           BatchCommand{Continue: true},
+			wantAdds:      2,
+			wantCompletes: 1,
+			wantErr:       "batch failed, 3 succeeded, 2 failed, 0 skipped",
+			wantStderr:    []string{"line 5: complete error: invalid ID format", "line 6: unterminated \" quote"},
+		},
+		{
+			name:          "stop on error commits what succeeded",
+			batching:      true,
+			wantAdds:      2,
+			wantCompletes: 0,
+			wantCommits:   1,
+			wantErr:       "stopped at line 5",
+		},
+		{
+			name:          "atomic",
+			cmd:           BatchCommand{Continue: true, Atomic: true},
+			batching:      true,
+			wantAdds:      2,
+			wantCompletes: 1,
+			wantRollbacks: 1,
+			wantErr:       "batch failed, 3 succeeded, 2 failed, 0 skipped; no changes were saved",
+		},
+		{
+			name:    "atomic needs batching",
+			cmd:     BatchCommand{Atomic: true},
+			wantErr: "--atomic is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.reset()
+			mock.addNextOk = &tasks.Task{Id: 1, Title: "Call dentist"}
+			batcher := &batchingMock{mockManager: &mock}
+			var m tasks.Manager = &mock
+			if tt.batching {
+				m = batcher
+			}
+			stdout, stderr := &strings.Builder{}, &strings.Builder{}
+
+			res, err := tt.cmd.run(m, strings.NewReader(batchScript), stdout, stderr)
+
+			if len(mock.addCalls) != tt.wantAdds || len(mock.completeCalls) != tt.wantCompletes {
+				t.Fatalf("expected %d adds and %d completes, got %v and %v", tt.wantAdds, tt.wantCompletes, mock.addCalls, mock.completeCalls)
+			}
+			if batcher.commits != tt.wantCommits || batcher.rollbacks != tt.wantRollbacks {
+				t.Fatalf("expected %d commits and %d rollbacks, got %d and %d", tt.wantCommits, tt.wantRollbacks, batcher.commits, batcher.rollbacks)
+			}
+			if tt.wantErr == "" {
+				if err != nil || res != tt.wantResult {
+					t.Fatalf("expected result %q, got %q (%v)", tt.wantResult, res, err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected err containing %q, got %v", tt.wantErr, err)
+			}
+			for _, want := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Fatalf("expected stderr to contain %q, got:\n%v", want, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestBatchRunSuccess(t *testing.T) {
+	mock := newMockManager(testTime)
+	mock.addNextOk = &tasks.Task{Id: 1, Title: "Call dentist"}
+	m := &batchingMock{mockManager: &mock}
+	stdout := &strings.Builder{}
+
+	res, err := (&BatchCommand{Atomic: true}).run(m, strings.NewReader("add 'Call dentist'\n# done\n"), stdout, &strings.Builder{})
+
+	if err != nil || res != "Batch complete: 1 succeeded, 0 failed, 0 skipped" {
+		t.Fatalf("unexpected result %q (%v)", res, err)
+	}
+	if m.commits != 1 || !strings.Contains(stdout.String(), "Added task 'Call dentist'") {
+		t.Fatalf("expected output to be committed and printed, got %d commits, stdout %q", m.commits, stdout.String())
+	}
+}
+
+func TestBatchErrorExitCode(t *testing.T) {
+	err := error(&batchError{summary: "1 failed", err: fmt.Errorf("line 3: task 9 %w", tasks.ErrNotFound)})
+	if ExitCode(err) != ExitNotFound {
+		t.Fatalf("expected exit code of the first failure, got %d", ExitCode(err))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -16,10 +17,23 @@ type Command interface {
 }
 
 func Parse(a *[]string) (Command, error) {
+	cmd, err := parseCommand(*a)
+	if err != nil {
+		return nil, &UsageError{err}
+	}
+	return cmd, nil
+}
+
+func parseCommand(args []string) (Command, error) {
 	// handle simple args case
-	args := *a
 	if len(args) == 0 {
-		return nil, fmt.Errorf("invalid command: must specify one of 'add', 'list', 'search', 'complete', or 'delete'")
+		return nil, invalidCommandError()
+	}
+	if isHelpFlag(args[0]) {
+		return &HelpCommand{}, nil
+	}
+	if _, ok := findCommandSpec(args[0]); ok && len(args) > 1 && isHelpFlag(args[1]) {
+		return &HelpCommand{Topic: args[0]}, nil
 	}
 
 	switch args[0] {
@@ -33,167 +47,661 @@ func Parse(a *[]string) (Command, error) {
 		return parseCompleteCmd(args[1:])
 	case "delete":
 		return parseDeleteCmd(args[1:])
+	case "edit":
+		return parseEditCmd(args[1:])
+	case "agenda":
+		return parseAgendaCmd(args[1:])
+	case "calendar":
+		return parseCalendarCmd(args[1:])
+	case "stats":
+		return parseStatsCmd(args[1:])
+	case "export":
+		return parseExportCmd(args[1:])
+	case "import":
+		return parseImportCmd(args[1:])
+	case "serve":
+		return parseServeCmd(args[1:])
+	case "rpc":
+		return parseRPCCmd(args[1:])
+	case "ui":
+		return parseUICmd(args[1:])
+	case "shell":
+		return parseShellCmd(args[1:])
+	case "batch":
+		return parseBatchCmd(args[1:])
+	case "config":
+		return parseConfigCmd(args[1:])
+	case "view":
+		return parseViewCmd(args[1:])
+	case "help":
+		return parseHelpCmd(args[1:])
+	case "completion":
+		return parseCompletionCmd(args[1:])
+	case completeCommandName:
+		return &CompleteWordsCommand{Words: args[1:]}, nil
 	}
-	return nil, fmt.Errorf("invalid command: must specify one of 'add', 'list', 'search', 'complete', or 'delete'")
+	return nil, invalidCommandError()
+}
+
+func invalidCommandError() error {
+	names := slices.DeleteFunc(commandNames(), func(name string) bool { return name == "help" || name == "completion" })
+	return fmt.Errorf("invalid command: must specify one of %s (run '%s help' for usage)", quoteList(names), programName)
 }
 
-func parseAddCmd(a []string) (*AddCommand, error) {
+func parseHelpCmd(a []string) (Command, error) {
 	if len(a) == 0 {
-		return nil, fmt.Errorf("add error: title cannot be empty")
+		return &HelpCommand{}, nil
+	}
+	if len(a) > 1 {
+		return nil, fmt.Errorf("help error: expected at most one command name")
+	}
+	if _, ok := findCommandSpec(a[0]); !ok {
+		return nil, fmt.Errorf("help error: unknown command '%s'", a[0])
+	}
+	return &HelpCommand{Topic: a[0]}, nil
+}
+
+type addFlags struct {
+	priority    *string
+	due         *string
+	category    *string
+	description *string
+	tags        *string
+	notes       *stringList
+	noParse     *bool
+}
+
+// newAddFlagSet takes its priority, due and category defaults from defaults.
+func newAddFlagSet(defaults Config) (*flag.FlagSet, *addFlags) {
+	fs := newFlagSet("add")
+	f := &addFlags{
+		priority:    fs.String("priority", configDefault(defaults.Priority, "medium"), "Priority: low, medium, high"),
+		due:         fs.String("due", configDefault(defaults.Due, "today"), "Due date: today, tomorrow, +Xd (days), mon..sun, or yyyy-MM-dd"),
+		category:    fs.String("category", defaults.Category, "Category: optional descriptive category"),
+		description: fs.String("description", "", "Description: optional longer description"),
+		tags:        fs.String("tags", "", "Tags: optional comma-separated list of tags"),
+		notes:       new(stringList),
+		noParse:     fs.Bool("no-parse", false, "Keep !priority, @category, #tag and due:date words in the title as written"),
+	}
+	fs.Var(f.notes, "note", "Note: optional note, may be repeated")
+	alias(fs, "p", "priority")
+	alias(fs, "d", "due")
+	alias(fs, "c", "category")
+	alias(fs, "t", "tags")
+	alias(fs, "n", "note")
+	return fs, f
+}
+
+func parseAddCmd(a []string) (Command, error) {
+	return parseAdd(a, userConfig)
+}
+
+func parseAdd(a []string, defaults Config) (Command, error) {
+	addFlagSet, f := newAddFlagSet(defaults)
+	args, err := parseArgs(addFlagSet, a)
+	if err != nil {
+		return flagError("add", err)
+	}
+	title := strings.Join(args, " ")
+	tags := parseTags(*f.tags)
+	if !*f.noParse {
+		q, err := parseQuickAdd(title)
+		if err != nil {
+			return nil, err
+		}
+		set := map[string]bool{}
+		addFlagSet.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+		for _, inline := range []struct{ value, flag, short string }{
+			{q.priority, "priority", "p"},
+			{q.due, "due", "d"},
+			{q.category, "category", "c"},
+		} {
+			if inline.value != "" && (set[inline.flag] || set[inline.short]) {
+				return nil, fmt.Errorf("add error: %s given both in the title and as a flag (use -no-parse to keep the title as written)", inline.flag)
+			}
+		}
+		title = q.title
+		if q.priority != "" {
+			*f.priority = q.priority
+		}
+		if q.due != "" {
+			*f.due = q.due
+		}
+		if q.category != "" {
+			*f.category = q.category
+		}
+		tags = parseTags(strings.Join(append(tags, q.tags...), ","))
 	}
-	title := a[0]
 	if strings.TrimSpace(title) == "" {
 		return nil, fmt.Errorf("add error: title cannot be empty")
 	}
 
-	addFlagSet := flag.NewFlagSet("add", flag.ExitOnError)
-	addPriority := addFlagSet.String("priority", "medium", "Priority: low, medium (default), high")
-	addDue := addFlagSet.String("due", "today", "Due date: today (default), tomorrow, +Xd (days), or yyyy-MM-dd")
-	addCategory := addFlagSet.String("category", "", "Category: optional descriptive category")
-	if err := addFlagSet.Parse(a[1:]); err != nil {
+	priority, err := parsePriority(*f.priority)
+	if err != nil {
+		return nil, err
+	}
+	due, err := parseDue(*f.due)
+	if err != nil {
 		return nil, err
 	}
 
-	var priority tasks.Priority
-	switch strings.ToLower(*addPriority) {
+	category := strings.ToLower(*f.category)
+	addCmd := &AddCommand{
+		Title:       title,
+		Priority:    priority,
+		Due:         due,
+		Category:    category,
+		Description: strings.TrimSpace(*f.description),
+		Notes:       *f.notes,
+		Tags:        tags,
+	}
+
+	return addCmd, nil
+}
+
+func parsePriority(s string) (tasks.Priority, error) {
+	switch strings.ToLower(s) {
 	case "low":
-		priority = tasks.Low
+		return tasks.Low, nil
 	case "medium":
-		priority = tasks.Medium
+		return tasks.Medium, nil
 	case "high":
-		priority = tasks.High
-	default:
-		return nil, fmt.Errorf("invalid priority format: must be 'low', 'medium', or 'high'")
+		return tasks.High, nil
 	}
+	return tasks.Medium, fmt.Errorf("invalid priority format: must be 'low', 'medium', or 'high'")
+}
+
+var plusDaysRegex = regexp.MustCompile(`^\+(\d+)d$`)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
 
-	plusDaysRegex := regexp.MustCompile(`^\+(\d+)d$`)
-	var due IntoDueDate
+func parseDue(s string) (IntoDueDate, error) {
+	if weekday, ok := weekdays[strings.ToLower(s)]; ok {
+		return &DueOnWeekday{Weekday: weekday}, nil
+	}
 	switch {
-	case *addDue == "today":
-		due = &DueToday{}
-	case *addDue == "tomorrow":
-		due = &DueTomorrow{}
-	case plusDaysRegex.MatchString(*addDue):
-		match := plusDaysRegex.FindStringSubmatch(*addDue)
+	case s == "today":
+		return &DueToday{}, nil
+	case s == "tomorrow":
+		return &DueTomorrow{}, nil
+	case plusDaysRegex.MatchString(s):
+		match := plusDaysRegex.FindStringSubmatch(s)
 		days, err := strconv.Atoi(match[1])
 		if err != nil {
 			return nil, fmt.Errorf("invalid date format +Xd: %v", err)
 		}
-		due = &DueInDays{Days: days}
-	default:
-		at, err := time.Parse("2006-01-02", *addDue)
-		if err != nil {
-			return nil, fmt.Errorf("invalid date format yyyy-MM-dd: %v", err)
-		}
-		due = &DueOnDate{At: at}
+		return &DueInDays{Days: days}, nil
 	}
-
-	category := strings.ToLower(*addCategory)
-	addCmd := &AddCommand{
-		Title:    title,
-		Priority: priority,
-		Due:      due,
-		Category: category,
+	at, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format yyyy-MM-dd: %v", err)
 	}
-
-	return addCmd, nil
+	return &DueOnDate{At: at}, nil
 }
 
-func parseListCmd(a []string) (*ListCommand, error) {
-	listFlagSet := flag.NewFlagSet("list", flag.ExitOnError)
-	listPriority := listFlagSet.String("priority", "", "Priority filter: low, medium, high")
-	listStatus := listFlagSet.String("status", "", "Status filter: pending, completed")
-	listCategory := listFlagSet.String("category", "", "Category filter")
-	listOverdue := listFlagSet.Bool("overdue", false, "Show only overdue tasks")
+const outputFormatUsage = "Output format: table, json (default: the 'output' setting, or table)"
 
-	if err := listFlagSet.Parse(a); err != nil {
-		return nil, err
+type listFlags struct {
+	priority *string
+	status   *string
+	category *string
+	overdue  *bool
+	sort     *string
+	format   *string
+	table    *tableFlags
+}
+
+func newListFlagSet() (*flag.FlagSet, *listFlags) {
+	fs := newFlagSet("list")
+	f := &listFlags{
+		priority: fs.String("priority", "", "Priority filter: low, medium, high"),
+		status:   fs.String("status", "", "Status filter: pending, completed"),
+		category: fs.String("category", "", "Category filter"),
+		overdue:  fs.Bool("overdue", false, "Show only overdue tasks: pending, with the due time passed"),
+		sort:     fs.String("sort", userConfig.Sort, "Sort key: id, title, priority, due, category, status (prefix with '-' to reverse)"),
+		format:   fs.String("format", "", outputFormatUsage),
+		table:    addTableFlags(fs),
 	}
+	alias(fs, "p", "priority")
+	alias(fs, "s", "status")
+	alias(fs, "c", "category")
+	alias(fs, "o", "overdue")
+	return fs, f
+}
 
-	var priorityFilter *tasks.Priority
-	if *listPriority != "" {
-		switch strings.ToLower(*listPriority) {
-		case "low":
-			p := tasks.Low
-			priorityFilter = &p
-		case "medium":
-			p := tasks.Medium
-			priorityFilter = &p
-		case "high":
-			p := tasks.High
-			priorityFilter = &p
-		default:
-			return nil, fmt.Errorf("invalid priority format: must be 'low', 'medium', or 'high'")
-		}
+func parseListCmd(a []string) (Command, error) {
+	listFlagSet, f := newListFlagSet()
+	args, err := parseArgs(listFlagSet, a)
+	if err != nil {
+		return flagError("list", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("list error: unexpected argument '%s'", args[0])
 	}
 
-	var statusFilter *tasks.Status
-	if *listStatus != "" {
-		switch strings.ToLower(*listStatus) {
-		case "pending":
-			s := tasks.Pending
-			statusFilter = &s
-		case "completed":
-			s := tasks.Completed
-			statusFilter = &s
-		default:
-			return nil, fmt.Errorf("invalid status format: must be 'pending' or 'completed'")
-		}
+	priorityFilter, err := parsePriorityFilter(*f.priority)
+	if err != nil {
+		return nil, err
+	}
+	statusFilter, err := parseStatusFilter(*f.status)
+	if err != nil {
+		return nil, err
+	}
+	sortKey, err := tasks.ParseSortKey(*f.sort)
+	if err != nil {
+		return nil, fmt.Errorf("list error: %v", err)
+	}
+	format, err := parseOutputFormat(*f.format)
+	if err != nil {
+		return nil, err
+	}
+	table, err := f.table.options()
+	if err != nil {
+		return nil, err
 	}
 
 	listCmd := &ListCommand{
 		StatusFilter:   statusFilter,
 		PriorityFilter: priorityFilter,
-		CategoryFilter: *listCategory,
-		OverdueFilter:  *listOverdue,
+		CategoryFilter: *f.category,
+		OverdueFilter:  *f.overdue,
+		Sort:           sortKey,
+		Format:         format,
+		Table:          table,
 	}
 
 	return listCmd, nil
 }
 
-func parseSearchCmd(a []string) (*SearchCommand, error) {
-	if len(a) == 0 {
-		return nil, fmt.Errorf("search error: query cannot be empty")
+type searchFlags struct {
+	regex    *bool
+	exact    *bool
+	priority *string
+	status   *string
+	category *string
+	overdue  *bool
+	limit    *int
+	format   *string
+	table    *tableFlags
+}
+
+func newSearchFlagSet() (*flag.FlagSet, *searchFlags) {
+	fs := newFlagSet("search")
+	f := &searchFlags{
+		regex:    fs.Bool("regex", false, "Treat the query as a regular expression"),
+		exact:    fs.Bool("exact", false, "Match whole words only, without fuzzy or partial matching"),
+		priority: fs.String("priority", "", "Priority filter: low, medium, high"),
+		status:   fs.String("status", "", "Status filter: pending, completed"),
+		category: fs.String("category", "", "Category filter"),
+		overdue:  fs.Bool("overdue", false, "Show only overdue tasks: pending, with the due time passed"),
+		limit:    fs.Int("limit", 0, "Maximum number of results (0 for no limit)"),
+		format:   fs.String("format", "", outputFormatUsage),
+		table:    addTableFlags(fs),
 	}
-	query := a[0]
+	alias(fs, "r", "regex")
+	alias(fs, "e", "exact")
+	alias(fs, "p", "priority")
+	alias(fs, "s", "status")
+	alias(fs, "c", "category")
+	alias(fs, "o", "overdue")
+	alias(fs, "l", "limit")
+	return fs, f
+}
+
+func parseSearchCmd(a []string) (Command, error) {
+	searchFlagSet, f := newSearchFlagSet()
+	args, err := parseArgs(searchFlagSet, a)
+	if err != nil {
+		return flagError("search", err)
+	}
+	query := strings.Join(args, " ")
 	if strings.TrimSpace(query) == "" {
 		return nil, fmt.Errorf("search error: query cannot be empty")
 	}
-	return &SearchCommand{Query: query}, nil
+
+	priorityFilter, err := parsePriorityFilter(*f.priority)
+	if err != nil {
+		return nil, err
+	}
+	statusFilter, err := parseStatusFilter(*f.status)
+	if err != nil {
+		return nil, err
+	}
+	if *f.limit < 0 {
+		return nil, fmt.Errorf("search error: limit cannot be negative")
+	}
+	format, err := parseOutputFormat(*f.format)
+	if err != nil {
+		return nil, err
+	}
+	table, err := f.table.options()
+	if err != nil {
+		return nil, err
+	}
+
+	mode := tasks.SearchFuzzy
+	switch {
+	case *f.regex && *f.exact:
+		return nil, fmt.Errorf("search error: -regex and -exact cannot be combined")
+	case *f.regex:
+		if _, _, err := tasks.ParseSearchRegex(query); err != nil {
+			return nil, fmt.Errorf("search error: %v", err)
+		}
+		mode = tasks.SearchRegex
+	case *f.exact:
+		mode = tasks.SearchExact
+	}
+
+	searchCmd := &SearchCommand{
+		Query:          query,
+		Mode:           mode,
+		StatusFilter:   statusFilter,
+		PriorityFilter: priorityFilter,
+		CategoryFilter: *f.category,
+		OverdueFilter:  *f.overdue,
+		Limit:          *f.limit,
+		Format:         format,
+		Table:          table,
+	}
+
+	return searchCmd, nil
 }
 
-func parseCompleteCmd(a []string) (*CompleteCommand, error) {
-	if len(a) == 0 {
-		return nil, fmt.Errorf("complete error: ID cannot be empty")
+func parsePriorityFilter(s string) (*tasks.Priority, error) {
+	if s == "" {
+		return nil, nil
+	}
+	p, err := parsePriority(s)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func parseStatusFilter(s string) (*tasks.Status, error) {
+	if s == "" {
+		return nil, nil
 	}
-	idStr := a[0]
-	if strings.TrimSpace(idStr) == "" {
-		return nil, fmt.Errorf("complete error: ID cannot be empty")
+	var st tasks.Status
+	switch strings.ToLower(s) {
+	case "pending":
+		st = tasks.Pending
+	case "completed":
+		st = tasks.Completed
+	default:
+		return nil, fmt.Errorf("invalid status format: must be 'pending' or 'completed'")
 	}
-	id, err := strconv.Atoi(idStr)
+	return &st, nil
+}
+
+func parseCompleteCmd(a []string) (Command, error) {
+	args, err := parseArgs(newFlagSet("complete"), a)
 	if err != nil {
-		return nil, fmt.Errorf("complete error: invalid ID format %v", err)
+		return flagError("complete", err)
 	}
-	if id <= 0 {
-		return nil, fmt.Errorf("complete error: invalid ID format - ID cannot be zero or negative")
+	id, err := parseId("complete", args)
+	if err != nil {
+		return nil, err
 	}
 	return &CompleteCommand{Id: id}, nil
 }
 
-func parseDeleteCmd(a []string) (*DeleteCommand, error) {
-	if len(a) == 0 {
-		return nil, fmt.Errorf("delete error: ID cannot be empty")
+func parseDeleteCmd(a []string) (Command, error) {
+	args, err := parseArgs(newFlagSet("delete"), a)
+	if err != nil {
+		return flagError("delete", err)
 	}
-	idStr := a[0]
-	if strings.TrimSpace(idStr) == "" {
-		return nil, fmt.Errorf("delete error: ID cannot be empty")
+	id, err := parseId("delete", args)
+	if err != nil {
+		return nil, err
 	}
-	id, err := strconv.Atoi(idStr)
+	return &DeleteCommand{Id: id}, nil
+}
+
+type editFlags struct {
+	title       *string
+	priority    *string
+	due         *string
+	category    *string
+	description *string
+	tags        *string
+	notes       *stringList
+	status      *string
+}
+
+func newEditFlagSet() (*flag.FlagSet, *editFlags) {
+	fs := newFlagSet("edit")
+	f := &editFlags{
+		title:       fs.String("title", "", "New title"),
+		priority:    fs.String("priority", "", "New priority: low, medium, high"),
+		due:         fs.String("due", "", "New due date: today, tomorrow, +Xd (days), mon..sun, or yyyy-MM-dd"),
+		category:    fs.String("category", "", "New category (empty to clear)"),
+		description: fs.String("description", "", "New description (empty to clear)"),
+		tags:        fs.String("tags", "", "Replace tags with a comma-separated list (empty to clear)"),
+		notes:       new(stringList),
+		status:      fs.String("status", "", "New status: pending, completed"),
+	}
+	fs.Var(f.notes, "note", "Append a note, may be repeated")
+	alias(fs, "p", "priority")
+	alias(fs, "d", "due")
+	alias(fs, "c", "category")
+	alias(fs, "t", "tags")
+	alias(fs, "n", "note")
+	alias(fs, "s", "status")
+	return fs, f
+}
+
+func parseEditCmd(a []string) (Command, error) {
+	editFlagSet, f := newEditFlagSet()
+	args, err := parseArgs(editFlagSet, a)
 	if err != nil {
-		return nil, fmt.Errorf("delete error: invalid ID format %v", err)
+		return flagError("edit", err)
+	}
+	id, err := parseId("edit", args)
+	if err != nil {
+		return nil, err
+	}
+
+	editCmd := &EditCommand{Id: id, Notes: *f.notes}
+	set := map[string]bool{}
+	editFlagSet.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+	if set["title"] {
+		if strings.TrimSpace(*f.title) == "" {
+			return nil, fmt.Errorf("edit error: title cannot be empty")
+		}
+		editCmd.Title = f.title
+	}
+	if set["priority"] || set["p"] {
+		priority, err := parsePriority(*f.priority)
+		if err != nil {
+			return nil, err
+		}
+		editCmd.Priority = &priority
+	}
+	if set["due"] || set["d"] {
+		if editCmd.Due, err = parseDue(*f.due); err != nil {
+			return nil, err
+		}
+	}
+	if set["category"] || set["c"] {
+		category := strings.ToLower(*f.category)
+		editCmd.Category = &category
+	}
+	if set["description"] {
+		description := strings.TrimSpace(*f.description)
+		editCmd.Description = &description
+	}
+	if set["tags"] || set["t"] {
+		tags := parseTags(*f.tags)
+		if tags == nil {
+			tags = []string{}
+		}
+		editCmd.Tags = &tags
+	}
+	if set["status"] || set["s"] {
+		if editCmd.Status, err = parseStatusFilter(*f.status); err != nil {
+			return nil, err
+		}
+		if editCmd.Status == nil {
+			return nil, fmt.Errorf("invalid status format: must be 'pending' or 'completed'")
+		}
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("edit error: nothing to change, specify at least one flag")
+	}
+	return editCmd, nil
+}
+
+func parseId(name string, args []string) (int, error) {
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		return 0, fmt.Errorf("%s error: ID cannot be empty", name)
+	}
+	if len(args) > 1 {
+		return 0, fmt.Errorf("%s error: unexpected argument '%s'", name, args[1])
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("%s error: invalid ID format %v", name, err)
 	}
 	if id <= 0 {
-		return nil, fmt.Errorf("delete error: invalid ID format - ID cannot be zero or negative")
+		return 0, fmt.Errorf("%s error: invalid ID format - ID cannot be zero or negative", name)
 	}
-	return &DeleteCommand{Id: id}, nil
+	return id, nil
+}
+
+func parseViewCmd(a []string) (Command, error) {
+	if len(a) == 0 {
+		return nil, fmt.Errorf("view error: must specify 'save', 'list', 'delete', or a view name")
+	}
+	switch a[0] {
+	case "list":
+		return &ViewListCommand{}, nil
+	case "save":
+		return parseViewSaveCmd(a[1:])
+	case "delete":
+		if len(a) < 2 || strings.TrimSpace(a[1]) == "" {
+			return nil, fmt.Errorf("view error: name cannot be empty")
+		}
+		return &ViewDeleteCommand{Name: a[1]}, nil
+	}
+
+	viewFlagSet, viewTable := newViewRunFlagSet()
+	args, err := parseArgs(viewFlagSet, a)
+	if err != nil {
+		return flagError("view", err)
+	}
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		return nil, fmt.Errorf("view error: name cannot be empty")
+	}
+	if len(args) > 1 {
+		return nil, fmt.Errorf("view error: unexpected argument '%s'", args[1])
+	}
+	table, err := viewTable.options()
+	if err != nil {
+		return nil, err
+	}
+	return &ViewRunCommand{Name: args[0], Table: table}, nil
+}
+
+func newViewRunFlagSet() (*flag.FlagSet, *tableFlags) {
+	fs := newFlagSet("view <name>")
+	return fs, addTableFlags(fs)
+}
+
+type viewSaveFlags struct {
+	sort    *string
+	format  *string
+	columns *string
+}
+
+func newViewSaveFlagSet() (*flag.FlagSet, *viewSaveFlags) {
+	fs := newFlagSet("view save")
+	f := &viewSaveFlags{
+		sort:    fs.String("sort", "", "Sort key: id, title, priority, due, category, status (prefix with '-' to reverse)"),
+		format:  fs.String("format", "", outputFormatUsage),
+		columns: fs.String("columns", "", "Comma-separated table columns: id, title, priority, due, category, status"),
+	}
+	alias(fs, "s", "sort")
+	alias(fs, "f", "format")
+	return fs, f
+}
+
+func parseViewSaveCmd(a []string) (Command, error) {
+	viewFlagSet, f := newViewSaveFlagSet()
+	args, err := parseArgs(viewFlagSet, a)
+	if err != nil {
+		return flagError("view", err)
+	}
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		return nil, fmt.Errorf("view error: name cannot be empty")
+	}
+	name := args[0]
+	if name == "save" || name == "list" || name == "delete" {
+		return nil, fmt.Errorf("view error: '%s' is a reserved name", name)
+	}
+	query := strings.Join(args[1:], " ")
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("view error: query cannot be empty")
+	}
+	if _, err := tasks.ParseFilter(query); err != nil {
+		return nil, fmt.Errorf("view error: %v", err)
+	}
+
+	sortKey, err := tasks.ParseSortKey(*f.sort)
+	if err != nil {
+		return nil, fmt.Errorf("view error: %v", err)
+	}
+	format, err := parseOutputFormat(*f.format)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := tasks.ParseColumns(*f.columns)
+	if err != nil {
+		return nil, fmt.Errorf("view error: %v", err)
+	}
+
+	viewCmd := &ViewSaveCommand{
+		View: tasks.View{
+			Name:    name,
+			Query:   query,
+			Sort:    sortKey.String(),
+			Format:  format,
+			Columns: columns,
+		},
+	}
+
+	return viewCmd, nil
+}
+
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringList) Set(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return fmt.Errorf("value cannot be empty")
+	}
+	*s = append(*s, v)
+	return nil
+}
+
+func parseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
@@ -63,6 +63,36 @@ func TestParseAddTableDriven(t *testing.T) {
 			args:   []string{"add", "Call dentist", "--due", "foobar"},
 			errMsg: "invalid date format",
 		},
+		{
+			name:   "add flags only",
+			args:   []string{"add", "-p", "high"},
+			errMsg: "title cannot be empty",
+		},
+		{
+			name:   "add unknown flag",
+			args:   []string{"add", "Call dentist", "--urgent"},
+			errMsg: "flag provided but not defined: -urgent",
+		},
+		{
+			name:   "add inline tokens only",
+			args:   []string{"add", "!high @work"},
+			errMsg: "title cannot be empty",
+		},
+		{
+			name:   "add inline invalid due",
+			args:   []string{"add", "Call dentist due:someday"},
+			errMsg: "invalid due date 'due:someday'",
+		},
+		{
+			name:   "add inline priority twice",
+			args:   []string{"add", "Call dentist !high !low"},
+			errMsg: "more than one priority",
+		},
+		{
+			name:   "add inline priority and flag",
+			args:   []string{"add", "Call dentist !high", "-p", "low"},
+			errMsg: "priority given both in the title and as a flag",
+		},
 	}
 	tests := []struct {
 		name   string
@@ -78,6 +108,45 @@ func TestParseAddTableDriven(t *testing.T) {
 				Due:      &DueToday{},
 			},
 		},
+		{
+			name: "add flags before title",
+			args: []string{"add", "-priority", "high", "Call dentist"},
+			addCmd: AddCommand{
+				Title:    "Call dentist",
+				Priority: tasks.High,
+				Due:      &DueToday{},
+			},
+		},
+		{
+			name: "add short flags around title words",
+			args: []string{"add", "-p", "high", "Call", "-c", "Health", "dentist", "-t", "phone", "-n", "Morning only"},
+			addCmd: AddCommand{
+				Title:    "Call dentist",
+				Priority: tasks.High,
+				Due:      &DueToday{},
+				Category: "health",
+				Notes:    []string{"Morning only"},
+				Tags:     []string{"phone"},
+			},
+		},
+		{
+			name: "add long flags with equals",
+			args: []string{"add", "--priority=low", "--due=tomorrow", "Call dentist"},
+			addCmd: AddCommand{
+				Title:    "Call dentist",
+				Priority: tasks.Low,
+				Due:      &DueTomorrow{},
+			},
+		},
+		{
+			name: "add double dash ends flags",
+			args: []string{"add", "-p", "high", "--", "-v is broken", "--due"},
+			addCmd: AddCommand{
+				Title:    "-v is broken --due",
+				Priority: tasks.High,
+				Due:      &DueToday{},
+			},
+		},
 		{
 			name: "add with priority",
 			args: []string{"add", "Call dentist", "--priority", "high"},
@@ -123,6 +192,75 @@ func TestParseAddTableDriven(t *testing.T) {
 				Due:      &DueOnDate{At: time.Date(2020, 4, 10, 0, 0, 0, 0, time.UTC)},
 			},
 		},
+		{
+			name: "add with description, notes and tags",
+			args: []string{"add", "Call dentist", "--description", "Book a cleaning", "--note", "Ask about x-rays", "--note", "Morning only", "--tags", "Health, phone,health"},
+			addCmd: AddCommand{
+				Title:       "Call dentist",
+				Priority:    tasks.Medium,
+				Due:         &DueToday{},
+				Description: "Book a cleaning",
+				Notes:       []string{"Ask about x-rays", "Morning only"},
+				Tags:        []string{"health", "phone"},
+			},
+		},
+		{
+			name: "add inline syntax",
+			args: []string{"add", "Ship release notes !high @Work #docs due:fri"},
+			addCmd: AddCommand{
+				Title:    "Ship release notes",
+				Priority: tasks.High,
+				Due:      &DueOnWeekday{Weekday: time.Friday},
+				Category: "work",
+				Tags:     []string{"docs"},
+			},
+		},
+		{
+			name: "add inline syntax across words and flags",
+			args: []string{"add", "#Docs", "Ship", "notes", "-t", "release,docs", "due:+3d"},
+			addCmd: AddCommand{
+				Title:    "Ship notes",
+				Priority: tasks.Medium,
+				Due:      &DueInDays{Days: 3},
+				Tags:     []string{"release", "docs"},
+			},
+		},
+		{
+			name: "add inline lookalikes stay in title",
+			args: []string{"add", "Fix #12 for me@example.com, urgent!"},
+			addCmd: AddCommand{
+				Title:    "Fix #12 for me@example.com, urgent!",
+				Priority: tasks.Medium,
+				Due:      &DueToday{},
+			},
+		},
+		{
+			name: "add inline escapes",
+			args: []string{"add", `Post in \#general \@here !low`},
+			addCmd: AddCommand{
+				Title:    "Post in #general @here",
+				Priority: tasks.Low,
+				Due:      &DueToday{},
+			},
+		},
+		{
+			name: "add no-parse",
+			args: []string{"add", "-no-parse", "Reply to @bob about #docs !high", "-p", "high"},
+			addCmd: AddCommand{
+				Title:    "Reply to @bob about #docs !high",
+				Priority: tasks.High,
+				Due:      &DueToday{},
+			},
+		},
+		{
+			name: "add weekday due flag",
+			args: []string{"add", "Call dentist", "-d", "Monday"},
+			addCmd: AddCommand{
+				Title:    "Call dentist",
+				Priority: tasks.Medium,
+				Due:      &DueOnWeekday{Weekday: time.Monday},
+			},
+		},
 	}
 
 	for _, it := range invalid {
@@ -172,6 +310,31 @@ func TestParseListTableDriven(t *testing.T) {
 			args:   []string{"list", "--status", "maybe"},
 			errMsg: "invalid status format",
 		},
+		{
+			name:   "list invalid columns",
+			args:   []string{"list", "--columns", "id,colour"},
+			errMsg: "invalid column",
+		},
+		{
+			name:   "list invalid style",
+			args:   []string{"list", "--style", "fancy"},
+			errMsg: "invalid table style",
+		},
+		{
+			name:   "list invalid color",
+			args:   []string{"list", "--color", "sometimes"},
+			errMsg: "invalid color mode",
+		},
+		{
+			name:   "list negative width",
+			args:   []string{"list", "--width", "-5"},
+			errMsg: "invalid width",
+		},
+		{
+			name:   "list invalid sort",
+			args:   []string{"list", "--sort", "colour"},
+			errMsg: "invalid sort key",
+		},
 	}
 	tests := []struct {
 		name    string
@@ -188,6 +351,13 @@ func TestParseListTableDriven(t *testing.T) {
 				OverdueFilter:  false,
 			},
 		},
+		{
+			name: "list sorted",
+			args: []string{"list", "--sort", "-Due"},
+			listCmd: ListCommand{
+				Sort: func() tasks.SortKey { k, _ := tasks.ParseSortKey("-due"); return k }(),
+			},
+		},
 		{
 			name: "list with status filter",
 			args: []string{"list", "--status", "pending"},
@@ -248,6 +418,20 @@ func TestParseListTableDriven(t *testing.T) {
 				OverdueFilter:  true,
 			},
 		},
+		{
+			name: "list with table options",
+			args: []string{"list", "--columns", "id,title,due,tags", "--style", "borderless", "--width", "80", "--wrap", "--relative", "--color", "never"},
+			listCmd: ListCommand{
+				Table: tasks.TableOptions{
+					Columns:     []string{"id", "title", "due", "tags"},
+					Style:       tasks.StyleBorderless,
+					Width:       80,
+					Wrap:        true,
+					RelativeDue: true,
+					Color:       tasks.ColorNever,
+				},
+			},
+		},
 	}
 
 	for _, it := range invalid {
@@ -292,6 +476,36 @@ func TestParseSearchListTableDriven(t *testing.T) {
 			args:   []string{"search", ""},
 			errMsg: "query cannot be empty",
 		},
+		{
+			name:   "search invalid priority",
+			args:   []string{"search", "dentist", "--priority", "mega-high"},
+			errMsg: "invalid priority format",
+		},
+		{
+			name:   "search invalid status",
+			args:   []string{"search", "dentist", "--status", "maybe"},
+			errMsg: "invalid status format",
+		},
+		{
+			name:   "search negative limit",
+			args:   []string{"search", "dentist", "--limit", "-1"},
+			errMsg: "limit cannot be negative",
+		},
+		{
+			name:   "search regex and exact",
+			args:   []string{"search", "dentist", "--regex", "--exact"},
+			errMsg: "cannot be combined",
+		},
+		{
+			name:   "search invalid regex",
+			args:   []string{"search", "dentist(", "--regex"},
+			errMsg: "invalid search regex",
+		},
+		{
+			name:   "search invalid regex after a field prefix",
+			args:   []string{"search", "title:*dentist", "--regex"},
+			errMsg: "invalid search regex",
+		},
 	}
 	tests := []struct {
 		name      string
@@ -305,6 +519,52 @@ func TestParseSearchListTableDriven(t *testing.T) {
 				Query: "Call dentist",
 			},
 		},
+		{
+			name: "search regex",
+			args: []string{"search", "^call", "--regex"},
+			searchCmd: SearchCommand{
+				Query: "^call",
+				Mode:  tasks.SearchRegex,
+			},
+		},
+		{
+			name: "search with filters",
+			args: []string{"search", "dentist", "--status", "pending", "--priority", "high", "--category", "health", "--overdue", "--limit", "3"},
+			searchCmd: SearchCommand{
+				Query:          "dentist",
+				StatusFilter:   &[]tasks.Status{tasks.Pending}[0],
+				PriorityFilter: &[]tasks.Priority{tasks.High}[0],
+				CategoryFilter: "health",
+				OverdueFilter:  true,
+				Limit:          3,
+			},
+		},
+		{
+			name: "search exact",
+			args: []string{"search", "dentist", "--exact"},
+			searchCmd: SearchCommand{
+				Query: "dentist",
+				Mode:  tasks.SearchExact,
+			},
+		},
+		{
+			name: "search flags before query",
+			args: []string{"search", "-e", "-s", "pending", "-l", "2", "dentist"},
+			searchCmd: SearchCommand{
+				Query:        "dentist",
+				Mode:         tasks.SearchExact,
+				StatusFilter: &[]tasks.Status{tasks.Pending}[0],
+				Limit:        2,
+			},
+		},
+		{
+			name: "search double dash query",
+			args: []string{"search", "-r", "--", "-v$"},
+			searchCmd: SearchCommand{
+				Query: "-v$",
+				Mode:  tasks.SearchRegex,
+			},
+		},
 	}
 
 	for _, it := range invalid {
@@ -359,6 +619,16 @@ func TestParseCompleteTableDriven(t *testing.T) {
 			args:   []string{"complete", "foobar"},
 			errMsg: "invalid ID format",
 		},
+		{
+			name:   "complete extra argument",
+			args:   []string{"complete", "3", "4"},
+			errMsg: "unexpected argument '4'",
+		},
+		{
+			name:   "complete unknown flag",
+			args:   []string{"complete", "--force", "3"},
+			errMsg: "flag provided but not defined",
+		},
 	}
 	tests := []struct {
 		name        string
@@ -372,6 +642,13 @@ func TestParseCompleteTableDriven(t *testing.T) {
 				Id: 25,
 			},
 		},
+		{
+			name: "complete after double dash",
+			args: []string{"complete", "--", "25"},
+			completeCmd: CompleteCommand{
+				Id: 25,
+			},
+		},
 	}
 
 	for _, it := range invalid {
@@ -471,3 +748,202 @@ func TestParseDeleteTableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEditTableDriven(t *testing.T) {
+	invalid := []struct {
+		name   string
+		args   []string
+		errMsg string
+	}{
+		{name: "edit no ID", args: []string{"edit", "-p", "high"}, errMsg: "ID cannot be empty"},
+		{name: "edit nothing to change", args: []string{"edit", "3"}, errMsg: "nothing to change"},
+		{name: "edit empty title", args: []string{"edit", "3", "--title", " "}, errMsg: "title cannot be empty"},
+		{name: "edit invalid priority", args: []string{"edit", "3", "-p", "urgent"}, errMsg: "invalid priority format"},
+		{name: "edit invalid due", args: []string{"edit", "3", "-d", "someday"}, errMsg: "invalid date format"},
+		{name: "edit invalid status", args: []string{"edit", "3", "--status", ""}, errMsg: "invalid status format"},
+	}
+	title := "Call the dentist"
+	high := tasks.High
+	empty := ""
+	pending := tasks.Pending
+	tests := []struct {
+		name    string
+		args    []string
+		editCmd EditCommand
+	}{
+		{
+			name:    "edit title and priority",
+			args:    []string{"edit", "-p", "high", "3", "--title", "Call the dentist"},
+			editCmd: EditCommand{Id: 3, Title: &title, Priority: &high},
+		},
+		{
+			name:    "edit clears fields",
+			args:    []string{"edit", "3", "-c", "", "--description=", "--tags", ""},
+			editCmd: EditCommand{Id: 3, Category: &empty, Description: &empty, Tags: &[]string{}},
+		},
+		{
+			name:    "edit due, notes and status",
+			args:    []string{"edit", "3", "-d", "tomorrow", "-n", "Morning only", "-s", "pending"},
+			editCmd: EditCommand{Id: 3, Due: &DueTomorrow{}, Notes: []string{"Morning only"}, Status: &pending},
+		},
+	}
+
+	for _, it := range invalid {
+		t.Run(it.name, func(t *testing.T) {
+			cmd, err := Parse(&it.args)
+
+			if err == nil {
+				t.Fatalf("expected err, got: %v", cmd)
+			}
+			if !strings.Contains(err.Error(), it.errMsg) {
+				t.Fatalf("unexpected err text: wanted='%v', got=%v", it.errMsg, err)
+			}
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := Parse(&tt.args)
+
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !reflect.DeepEqual(&tt.editCmd, cmd) {
+				t.Fatalf("unexpected command: wanted=%+v, got=%+v", tt.editCmd, cmd)
+			}
+		})
+	}
+}
+
+func TestParseViewTableDriven(t *testing.T) {
+	invalid := []struct {
+		name   string
+		args   []string
+		errMsg string
+	}{
+		{
+			name:   "view no subcommand",
+			args:   []string{"view"},
+			errMsg: "must specify",
+		},
+		{
+			name:   "view save no name",
+			args:   []string{"view", "save"},
+			errMsg: "name cannot be empty",
+		},
+		{
+			name:   "view save reserved name",
+			args:   []string{"view", "save", "list", "status:pending"},
+			errMsg: "reserved name",
+		},
+		{
+			name:   "view save no query",
+			args:   []string{"view", "save", "today"},
+			errMsg: "query cannot be empty",
+		},
+		{
+			name:   "view save invalid query",
+			args:   []string{"view", "save", "today", "mood:happy"},
+			errMsg: "invalid query field",
+		},
+		{
+			name:   "view save invalid sort",
+			args:   []string{"view", "save", "today", "status:pending", "-sort", "colour"},
+			errMsg: "invalid sort key",
+		},
+		{
+			name:   "view save invalid format",
+			args:   []string{"view", "save", "today", "status:pending", "-format", "xml"},
+			errMsg: "invalid output format",
+		},
+		{
+			name:   "view save invalid columns",
+			args:   []string{"view", "save", "today", "status:pending", "-columns", "id,colour"},
+			errMsg: "invalid column",
+		},
+	}
+	tests := []struct {
+		name string
+		args []string
+		want Command
+	}{
+		{
+			name: "view list",
+			args: []string{"view", "list"},
+			want: &ViewListCommand{},
+		},
+		{
+			name: "view run",
+			args: []string{"view", "today"},
+			want: &ViewRunCommand{Name: "today"},
+		},
+		{
+			name: "view run with table options",
+			args: []string{"view", "today", "-style", "compact", "-relative"},
+			want: &ViewRunCommand{Name: "today", Table: tasks.TableOptions{Style: tasks.StyleCompact, RelativeDue: true}},
+		},
+		{
+			name: "view run with flags first",
+			args: []string{"view", "--style=compact", "today"},
+			want: &ViewRunCommand{Name: "today", Table: tasks.TableOptions{Style: tasks.StyleCompact}},
+		},
+		{
+			name: "view save with short flags first",
+			args: []string{"view", "save", "-s", "-due", "-f", "json", "soon", "due<=+7d"},
+			want: &ViewSaveCommand{
+				View: tasks.View{Name: "soon", Query: "due<=+7d", Sort: "-due", Format: "json"},
+			},
+		},
+		{
+			name: "view delete",
+			args: []string{"view", "delete", "today"},
+			want: &ViewDeleteCommand{Name: "today"},
+		},
+		{
+			name: "view save minimal",
+			args: []string{"view", "save", "today", "status:pending and due<=today"},
+			want: &ViewSaveCommand{
+				View: tasks.View{Name: "today", Query: "status:pending and due<=today"},
+			},
+		},
+		{
+			name: "view save with options",
+			args: []string{"view", "save", "today", "status:pending and due<=today", "-sort", "priority", "-format", "json", "-columns", "id,title,due"},
+			want: &ViewSaveCommand{
+				View: tasks.View{
+					Name:    "today",
+					Query:   "status:pending and due<=today",
+					Sort:    "priority",
+					Format:  "json",
+					Columns: []string{"id", "title", "due"},
+				},
+			},
+		},
+	}
+
+	for _, it := range invalid {
+		t.Run(it.name, func(t *testing.T) {
+			cmd, err := Parse(&it.args)
+
+			if err == nil {
+				t.Fatalf("expected err, got: %v", cmd)
+			}
+			if !strings.Contains(strings.ToLower(err.Error()), strings.ToLower(it.errMsg)) {
+				t.Fatalf("unexpected err text: wanted='%v', got=%v", it.errMsg, err)
+			}
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := Parse(&tt.args)
+
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !reflect.DeepEqual(tt.want, cmd) {
+				t.Fatalf("unexpected command: wanted=%v, got=%v", tt.want, cmd)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package cli
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+const completeCommandName = "__complete"
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+type CompletionCommand struct {
+	Shell string
+}
+
+func parseCompletionCmd(a []string) (Command, error) {
+	args, err := parseArgs(newFlagSet("completion"), a)
+	if err != nil {
+		return flagError("completion", err)
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("completion error: must specify one of %s", quoteList(completionShells))
+	}
+	shell := strings.ToLower(args[0])
+	if !slices.Contains(completionShells, shell) {
+		return nil, fmt.Errorf("completion error: invalid shell '%s': must be one of %s", args[0], quoteList(completionShells))
+	}
+	return &CompletionCommand{Shell: shell}, nil
+}
+
+func (c *CompletionCommand) Execute(m tasks.Manager) (string, error) {
+	switch c.Shell {
+	case "bash":
+		return fmt.Sprintf(bashCompletion, programName, completeCommandName), nil
+	case "zsh":
+		return fmt.Sprintf(zshCompletion, programName, completeCommandName), nil
+	case "fish":
+		return fmt.Sprintf(fishCompletion, programName, completeCommandName), nil
+	}
+	return "", fmt.Errorf("invalid shell '%s': must be one of %s", c.Shell, quoteList(completionShells))
+}
+
+const bashCompletion = `# bash completion for %[1]s
+_%[1]s_completion() {
+    local IFS=$'\n'
+    local candidates
+    candidates=$(%[1]s %[2]s "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)
+    COMPREPLY=($(printf '%%s\n' "$candidates" | cut -f1))
+}
+complete -F _%[1]s_completion %[1]s`
+
+const zshCompletion = `#compdef %[1]s
+_%[1]s() {
+    local -a candidates
+    local line
+    for line in "${(@f)$(%[1]s %[2]s "${words[@]:1:$((CURRENT-1))}" 2>/dev/null)}"; do
+        [[ -z "$line" ]] && continue
+        if [[ "$line" == *$'\t'* ]]; then
+            candidates+=("${${line%%%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+        else
+            candidates+=("${line//:/\\:}")
+        fi
+    done
+    _describe '%[1]s' candidates
+}
+compdef _%[1]s %[1]s`
+
+const fishCompletion = `# fish completion for %[1]s
+function __%[1]s_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    %[1]s %[2]s $tokens[2..-1] 2>/dev/null
+end
+complete -c %[1]s -f -a '(__%[1]s_complete)'`
+
+// CompleteWordsCommand prints completion candidates for the words typed so
+// far, one per line as "value<TAB>description". The last word is the one being
+// completed and may be empty.
+type CompleteWordsCommand struct {
+	Words []string
+}
+
+type candidate struct {
+	value       string
+	description string
+}
+
+func (c *CompleteWordsCommand) Execute(m tasks.Manager) (string, error) {
+	words := c.Words
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	prev, cur := words[:len(words)-1], words[len(words)-1]
+
+	candidates, err := completeWords(m, prev, cur)
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, 0, len(candidates))
+	for _, cand := range candidates {
+		if !strings.HasPrefix(cand.value, cur) {
+			continue
+		}
+		if cand.description != "" {
+			lines = append(lines, cand.value+"\t"+cand.description)
+		} else {
+			lines = append(lines, cand.value)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func completeWords(m tasks.Manager, prev []string, cur string) ([]candidate, error) {
+	for len(prev) > 0 && strings.HasPrefix(prev[0], "-") {
+		if strings.Contains(prev[0], "=") {
+			prev = prev[1:]
+			continue
+		}
+		if len(prev) == 1 {
+			if strings.TrimLeft(prev[0], "-") == "remote" {
+				return nil, nil
+			}
+			return plainCandidates(OutputText, OutputJSON), nil
+		}
+		prev = prev[2:]
+	}
+	if len(prev) == 0 {
+		if strings.HasPrefix(cur, "-") {
+			return []candidate{
+				{"--output", "Error output format: text, json"},
+				{"--remote", "URL of a task server to use instead of the task file"},
+			}, nil
+		}
+		candidates := make([]candidate, 0, len(commandSpecs))
+		for _, spec := range commandSpecs {
+			candidates = append(candidates, candidate{spec.name, spec.summary})
+		}
+		for _, name := range aliasNames() {
+			candidates = append(candidates, candidate{name, "Alias for '" + userConfig.Aliases[name] + "'"})
+		}
+		return candidates, nil
+	}
+	if expanded, err := expandAlias(prev); err == nil {
+		prev = expanded
+	}
+	command, args := prev[0], prev[1:]
+	fs := completionFlagSet(command, args)
+	if fs == nil {
+		return nil, nil
+	}
+
+	if name, value, ok := strings.Cut(cur, "="); ok && strings.HasPrefix(name, "-") {
+		values, err := completeFlagValue(m, fs, strings.TrimLeft(name, "-"), value)
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			values[i].value = name + "=" + values[i].value
+		}
+		return values, nil
+	}
+	if len(args) > 0 {
+		last := args[len(args)-1]
+		name := strings.TrimLeft(last, "-")
+		if strings.HasPrefix(last, "-") && !strings.Contains(last, "=") && fs.Lookup(name) != nil && !isBoolFlag(fs, name) {
+			return completeFlagValue(m, fs, name, cur)
+		}
+	}
+	if strings.HasPrefix(cur, "-") {
+		return completeFlagNames(fs, cur), nil
+	}
+	return completePositional(m, command, positionalArgs(fs, args))
+}
+
+func completionFlagSet(command string, args []string) *flag.FlagSet {
+	if command == "view" {
+		if len(args) > 0 && args[0] == "save" {
+			fs, _ := newViewSaveFlagSet()
+			return fs
+		}
+		fs, _ := newViewRunFlagSet()
+		return fs
+	}
+	spec, ok := findCommandSpec(command)
+	if !ok {
+		return nil
+	}
+	return spec.flagSets()[0]
+}
+
+func positionalArgs(fs *flag.FlagSet, args []string) []string {
+	var positionals []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" || negativeNumberRegex.MatchString(arg) {
+			positionals = append(positionals, arg)
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !hasValue && fs.Lookup(name) != nil && !isBoolFlag(fs, name) {
+			i++
+		}
+	}
+	return positionals
+}
+
+func completeFlagNames(fs *flag.FlagSet, cur string) []candidate {
+	singleDash := !strings.HasPrefix(cur, "--") && len(cur) > 2
+	var candidates []candidate
+	fs.VisitAll(func(f *flag.Flag) {
+		name := "--" + f.Name
+		if len(f.Name) == 1 {
+			name = "-" + f.Name
+		} else if singleDash {
+			name = "-" + f.Name
+		}
+		_, usage := flag.UnquoteUsage(f)
+		candidates = append(candidates, candidate{name, usage})
+	})
+	return candidates
+}
+
+func completeFlagValue(m tasks.Manager, fs *flag.FlagSet, name string, cur string) ([]candidate, error) {
+	f := fs.Lookup(name)
+	if f == nil {
+		return nil, nil
+	}
+	// resolve short aliases to the long flag sharing the same value
+	fs.VisitAll(func(other *flag.Flag) {
+		if other.Value == f.Value && len(other.Name) > len(f.Name) {
+			f = other
+		}
+	})
+
+	switch f.Name {
+	case "priority":
+		return plainCandidates("low", "medium", "high"), nil
+	case "status":
+		return plainCandidates("pending", "completed"), nil
+	case "due":
+		return plainCandidates("today", "tomorrow", "+1d", "+7d", "mon", "tue", "wed", "thu", "fri", "sat", "sun"), nil
+	case "style":
+		return plainCandidates("default", "compact", "borderless"), nil
+	case "color":
+		return plainCandidates("auto", "always", "never"), nil
+	case "format":
+		switch fs.Name() {
+		case "stats":
+			return plainCandidates("text", "json"), nil
+		case "export", "import":
+			return plainCandidates(exchangeFormatNames()...), nil
+		}
+		return plainCandidates("table", "json"), nil
+	case "sort":
+		return plainCandidates(tasks.SortFields...), nil
+	case "columns":
+		return listCandidates(cur, tasks.AllColumns), nil
+	case "category":
+		categories, _, err := categoriesAndTags(m)
+		return plainCandidates(categories...), err
+	case "tags":
+		_, tags, err := categoriesAndTags(m)
+		return listCandidates(cur, tags), err
+	}
+	return nil, nil
+}
+
+func completePositional(m tasks.Manager, command string, positionals []string) ([]candidate, error) {
+	switch command {
+	case "complete", "delete", "edit":
+		if len(positionals) > 0 {
+			return nil, nil
+		}
+		return taskIdCandidates(m, command == "complete")
+	case "help":
+		if len(positionals) > 0 {
+			return nil, nil
+		}
+		return plainCandidates(commandNames()...), nil
+	case "completion":
+		if len(positionals) > 0 {
+			return nil, nil
+		}
+		return plainCandidates(completionShells...), nil
+	case "calendar":
+		if len(positionals) > 0 {
+			return nil, nil
+		}
+		return plainCandidates(append([]string{"next", "last"}, monthNames...)...), nil
+	case "config":
+		switch {
+		case len(positionals) == 0:
+			return plainCandidates("list", "get", "set"), nil
+		case len(positionals) == 1 && positionals[0] != "list":
+			candidates := make([]candidate, 0, len(configKeys))
+			for _, key := range configKeys {
+				candidates = append(candidates, candidate{key.name, key.summary})
+			}
+			for _, name := range aliasNames() {
+				candidates = append(candidates, candidate{aliasKeyPrefix + name, userConfig.Aliases[name]})
+			}
+			return candidates, nil
+		}
+	case "view":
+		switch {
+		case len(positionals) == 0:
+			candidates := plainCandidates("save", "list", "delete")
+			views, err := viewCandidates(m)
+			return append(candidates, views...), err
+		case len(positionals) == 1 && positionals[0] == "delete":
+			return viewCandidates(m)
+		}
+	}
+	return nil, nil
+}
+
+func taskIdCandidates(m tasks.Manager, pendingOnly bool) ([]candidate, error) {
+	var status *tasks.Status
+	if pendingOnly {
+		status = &[]tasks.Status{tasks.Pending}[0]
+	}
+	list, err := m.ListTasks(status, nil, "", false)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(list, func(a, b tasks.Task) int { return cmp.Compare(a.Id, b.Id) })
+	candidates := make([]candidate, 0, len(list))
+	for _, t := range list {
+		candidates = append(candidates, candidate{fmt.Sprint(t.Id), t.Title})
+	}
+	return candidates, nil
+}
+
+func viewCandidates(m tasks.Manager) ([]candidate, error) {
+	views, err := m.ListViews()
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]candidate, 0, len(views))
+	for _, v := range views {
+		candidates = append(candidates, candidate{v.Name, v.Query})
+	}
+	return candidates, nil
+}
+
+func categoriesAndTags(m tasks.Manager) ([]string, []string, error) {
+	list, err := m.ListTasks(nil, nil, "", false)
+	if err != nil {
+		return nil, nil, err
+	}
+	var categories, tags []string
+	for _, t := range list {
+		if t.Category != "" && !slices.Contains(categories, t.Category) {
+			categories = append(categories, t.Category)
+		}
+		for _, tag := range t.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(categories)
+	slices.Sort(tags)
+	return categories, tags, nil
+}
+
+func plainCandidates(values ...string) []candidate {
+	candidates := make([]candidate, 0, len(values))
+	for _, v := range values {
+		candidates = append(candidates, candidate{value: v})
+	}
+	return candidates
+}
+
+// listCandidates completes the last element of a comma-separated value.
+func listCandidates(cur string, values []string) []candidate {
+	i := strings.LastIndex(cur, ",")
+	prefix, done := "", []string{}
+	if i >= 0 {
+		prefix = cur[:i+1]
+		done = strings.Split(cur[:i], ",")
+	}
+	candidates := make([]candidate, 0, len(values))
+	for _, v := range values {
+		if !slices.Contains(done, v) {
+			candidates = append(candidates, candidate{value: prefix + v})
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestParseCompletionTableDriven(t *testing.T) {
+	invalid := []struct {
+		name   string
+		args   []string
+		errMsg string
+	}{
+		{name: "no shell", args: []string{"completion"}, errMsg: "must specify one of 'bash', 'zsh', or 'fish'"},
+		{name: "unknown shell", args: []string{"completion", "tcsh"}, errMsg: "invalid shell 'tcsh'"},
+	}
+	for _, it := range invalid {
+		t.Run(it.name, func(t *testing.T) {
+			cmd, err := Parse(&it.args)
+			if err == nil || !strings.Contains(err.Error(), it.errMsg) {
+				t.Fatalf("expected err containing '%v', got cmd=%v err=%v", it.errMsg, cmd, err)
+			}
+		})
+	}
+
+	for _, shell := range completionShells {
+		t.Run(shell, func(t *testing.T) {
+			args := []string{"completion", shell}
+			cmd, err := Parse(&args)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			got, err := cmd.Execute(nil)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !strings.Contains(got, "todo __complete") {
+				t.Fatalf("expected script to call the hidden __complete command, got:\n%v", got)
+			}
+		})
+	}
+}
+
+func TestCompleteWordsTableDriven(t *testing.T) {
+	m := newMockManager(testTime)
+	tests := []struct {
+		name    string
+		words   []string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "commands",
+			words:   []string{""},
+			want:    []string{"add\tAdd a new task", "list\t", "view\t", "completion\t"},
+			notWant: []string{"__complete"},
+		},
+		{
+			name:    "command prefix",
+			words:   []string{"de"},
+			want:    []string{"delete\t"},
+			notWant: []string{"add", "list"},
+		},
+		{
+			name:  "global flag value",
+			words: []string{"--output", ""},
+			want:  []string{"text", "json"},
+		},
+		{
+			name:  "command after global flag",
+			words: []string{"--output", "json", "com"},
+			want:  []string{"complete\t", "completion\t"},
+		},
+		{
+			name:  "flags",
+			words: []string{"add", "Call dentist", "--p"},
+			want:  []string{"--priority\tPriority"},
+		},
+		{
+			name:  "single dash long flags",
+			words: []string{"list", "-sta"},
+			want:  []string{"-status\t"},
+		},
+		{
+			name:  "flag values",
+			words: []string{"list", "--priority", ""},
+			want:  []string{"low", "medium", "high"},
+		},
+		{
+			name:  "short flag values",
+			words: []string{"list", "-s", "p"},
+			want:  []string{"pending"},
+		},
+		{
+			name:    "categories",
+			words:   []string{"add", "Call dentist", "--category", ""},
+			want:    []string{"health", "work"},
+			notWant: []string{"\t"},
+		},
+		{
+			name:    "categories with equals",
+			words:   []string{"list", "--category=w"},
+			want:    []string{"--category=work"},
+			notWant: []string{"health"},
+		},
+		{
+			name:    "tags complete the last list element",
+			words:   []string{"add", "Deploy", "-t", "code,"},
+			want:    []string{"code,phone", "code,release"},
+			notWant: []string{"code,code"},
+		},
+		{
+			name:    "columns",
+			words:   []string{"list", "--columns", "id,ti"},
+			want:    []string{"id,title"},
+			notWant: []string{"id,due"},
+		},
+		{
+			name:  "task ids with titles",
+			words: []string{"delete", ""},
+			want:  []string{"1\tCall dentist", "2\tDeploy release"},
+		},
+		{
+			name:    "no ids after the first positional",
+			words:   []string{"delete", "1", ""},
+			notWant: []string{"Call dentist"},
+		},
+		{
+			name:  "view subcommands and names",
+			words: []string{"view", ""},
+			want:  []string{"save", "list", "delete", "today\tstatus:pending"},
+		},
+		{
+			name:    "view delete names",
+			words:   []string{"view", "delete", ""},
+			want:    []string{"today\t"},
+			notWant: []string{"save"},
+		},
+		{
+			name:  "view save flags",
+			words: []string{"view", "save", "soon", "due<=+7d", "--sort", "pri"},
+			want:  []string{"priority"},
+		},
+		{
+			name:  "help topics",
+			words: []string{"help", ""},
+			want:  []string{"add", "search", "completion"},
+		},
+		{
+			name:  "shells",
+			words: []string{"completion", "f"},
+			want:  []string{"fish"},
+		},
+		{
+			name:  "unknown command",
+			words: []string{"frobnicate", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.reset()
+			m.listNextOk = []tasks.Task{
+				{Id: 2, Title: "Deploy release", Category: "work", Tags: []string{"code", "release"}},
+				{Id: 1, Title: "Call dentist", Category: "health", Tags: []string{"phone"}},
+			}
+			m.listViewsNextOk = []tasks.View{{Name: "today", Query: "status:pending"}}
+			args := append([]string{"__complete"}, tt.words...)
+
+			cmd, err := Parse(&args)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			got, err := cmd.Execute(&m)
+
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Fatalf("expected candidates to contain %q, got:\n%v", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Fatalf("expected candidates not to contain %q, got:\n%v", notWant, got)
+				}
+			}
+			if len(tt.want) == 0 && len(tt.notWant) == 0 && got != "" {
+				t.Fatalf("expected no candidates, got:\n%v", got)
+			}
+		})
+	}
+}
+
+func TestCompleteWordsPendingIds(t *testing.T) {
+	m := newMockManager(testTime)
+	args := []string{"__complete", "complete", ""}
+	cmd, err := Parse(&args)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := cmd.Execute(&m); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(m.listCalls) != 1 || m.listCalls[0].status == nil || *m.listCalls[0].status != tasks.Pending {
+		t.Fatalf("expected completion to list pending tasks only, got %v", m.listCalls)
+	}
+}
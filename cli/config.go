@@ -0,0 +1,511 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+const configDirName = "golang-todo-cli"
+
+// Config holds user settings. Empty fields mean the built-in default.
+type Config struct {
+	Priority    string            `json:"priority,omitempty"`
+	Due         string            `json:"due,omitempty"`
+	Category    string            `json:"category,omitempty"`
+	Output      string            `json:"output,omitempty"`
+	ErrorOutput string            `json:"errorOutput,omitempty"`
+	Sort        string            `json:"sort,omitempty"`
+	DateFormat  string            `json:"dateFormat,omitempty"`
+	WeekStart   string            `json:"weekStart,omitempty"`
+	Theme       string            `json:"theme,omitempty"`
+	Columns     string            `json:"columns,omitempty"`
+	Style       string            `json:"style,omitempty"`
+	Wrap        string            `json:"wrap,omitempty"`
+	RelativeDue string            `json:"relativeDue,omitempty"`
+	DB          string            `json:"db,omitempty"`
+	Remote      string            `json:"remote,omitempty"`
+	HookTimeout string            `json:"hookTimeout,omitempty"`
+	Aliases     map[string]string `json:"aliases,omitempty"`
+}
+
+// userConfig is loaded by Run and supplies flag defaults. Where a TODO_*
+// environment variable sets the same default, the variable wins.
+var userConfig Config
+
+type configKey struct {
+	name     string
+	summary  string
+	field    func(c *Config) *string
+	validate func(s string) error
+	lower    bool
+}
+
+const aliasKeyPrefix = "alias."
+
+var configKeys = []configKey{
+	{
+		name:     "priority",
+		summary:  "Default priority for add: low, medium, high",
+		field:    func(c *Config) *string { return &c.Priority },
+		validate: func(s string) error { _, err := parsePriority(s); return err },
+		lower:    true,
+	},
+	{
+		name:     "due",
+		summary:  "Default due date for add: today, tomorrow, +Xd, mon..sun, yyyy-MM-dd",
+		field:    func(c *Config) *string { return &c.Due },
+		validate: func(s string) error { _, err := parseDue(s); return err },
+		lower:    true,
+	},
+	{
+		name:    "category",
+		summary: "Default category for add",
+		field:   func(c *Config) *string { return &c.Category },
+		lower:   true,
+	},
+	{
+		name:     "output",
+		summary:  "Default output format for list, search, view and stats: table, json",
+		field:    func(c *Config) *string { return &c.Output },
+		validate: func(s string) error { _, err := parseOutputFormat(s); return err },
+		lower:    true,
+	},
+	{
+		name:     "error-output",
+		summary:  "Default error output format: text, json (--output takes precedence)",
+		field:    func(c *Config) *string { return &c.ErrorOutput },
+		validate: validateErrorOutput,
+		lower:    true,
+	},
+	{
+		name:     "sort",
+		summary:  "Default sort key for list, e.g. 'due' or '-title'",
+		field:    func(c *Config) *string { return &c.Sort },
+		validate: func(s string) error { _, err := tasks.ParseSortKey(s); return err },
+		lower:    true,
+	},
+	{
+		name:     "date-format",
+		summary:  "Date layout for tables, written as Go's reference date, e.g. '02/01/2006' or 'Jan 2'",
+		field:    func(c *Config) *string { return &c.DateFormat },
+		validate: validateDateFormat,
+	},
+	{
+		name:     "week-start",
+		summary:  "First day of the week for agenda and calendar: monday, sunday",
+		field:    func(c *Config) *string { return &c.WeekStart },
+		validate: validateWeekStart,
+		lower:    true,
+	},
+	{
+		name:     "theme",
+		summary:  "Color theme, e.g. 'high=red+bold,overdue=magenta' (TODO_THEME takes precedence)",
+		field:    func(c *Config) *string { return &c.Theme },
+		validate: func(s string) error { _, err := tasks.ParseTheme(s); return err },
+	},
+	{
+		name:     "columns",
+		summary:  "Default table columns, e.g. 'id,title,due' (TODO_COLUMNS takes precedence)",
+		field:    func(c *Config) *string { return &c.Columns },
+		validate: func(s string) error { _, err := tasks.ParseColumns(s); return err },
+		lower:    true,
+	},
+	{
+		name:     "style",
+		summary:  "Default table style: default, compact, borderless (TODO_STYLE takes precedence)",
+		field:    func(c *Config) *string { return &c.Style },
+		validate: func(s string) error { _, err := tasks.ParseTableStyle(s); return err },
+		lower:    true,
+	},
+	{
+		name:     "wrap",
+		summary:  "Wrap long titles in tables by default: true, false (TODO_WRAP takes precedence)",
+		field:    func(c *Config) *string { return &c.Wrap },
+		validate: validateBool,
+		lower:    true,
+	},
+	{
+		name:     "relative-due",
+		summary:  "Show due dates relative to today by default: true, false (TODO_RELATIVE_DUE takes precedence)",
+		field:    func(c *Config) *string { return &c.RelativeDue },
+		validate: validateBool,
+		lower:    true,
+	},
+	{
+		name:    "db",
+		summary: "Path of the task file (default: tasks.db.json in the current directory)",
+		field:   func(c *Config) *string { return &c.DB },
+	},
+	{
+		name:     "remote",
+		summary:  "URL of a task server started with 'todo serve', used instead of the task file",
+		field:    func(c *Config) *string { return &c.Remote },
+		validate: validateRemote,
+	},
+	{
+		name:     "hook-timeout",
+		summary:  "How long a hook may run before it is stopped, e.g. '30s' (default: 10s)",
+		field:    func(c *Config) *string { return &c.HookTimeout },
+		validate: func(s string) error { _, err := parseHookTimeout(s); return err },
+		lower:    true,
+	},
+}
+
+func findConfigKey(name string) (*configKey, bool) {
+	for i := range configKeys {
+		if configKeys[i].name == name {
+			return &configKeys[i], true
+		}
+	}
+	return nil, false
+}
+
+func configKeyNames() []string {
+	names := make([]string, 0, len(configKeys)+1)
+	for _, key := range configKeys {
+		names = append(names, key.name)
+	}
+	return append(names, aliasKeyPrefix+"<name>")
+}
+
+func validateErrorOutput(s string) error {
+	if s != OutputText && s != OutputJSON {
+		return fmt.Errorf("invalid output format '%s': must be 'text' or 'json'", s)
+	}
+	return nil
+}
+
+func validateBool(s string) error {
+	if _, err := strconv.ParseBool(s); err != nil {
+		return fmt.Errorf("invalid boolean '%s': must be 'true' or 'false'", s)
+	}
+	return nil
+}
+
+func validateRemote(s string) error {
+	_, err := tasks.NewRemoteManager(s, nil)
+	return err
+}
+
+func parseHookTimeout(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid hook timeout '%s': must be a duration such as '30s' or '2m'", s)
+	}
+	return d, nil
+}
+
+// validateDateFormat checks that a layout keeps the year, month and day, by
+// formatting a date and parsing it back.
+func validateDateFormat(s string) error {
+	sample := time.Date(2006, 11, 25, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(s, sample.Format(s))
+	if err != nil || parsed.Month() != sample.Month() || parsed.Day() != sample.Day() {
+		return fmt.Errorf("invalid date format '%s': must show the month and day of Go's reference date, e.g. '2006-01-02' or 'Jan 2'", s)
+	}
+	return nil
+}
+
+func validateWeekStart(s string) error {
+	if s != "monday" && s != "sunday" {
+		return fmt.Errorf("invalid week start '%s': must be 'monday' or 'sunday'", s)
+	}
+	return nil
+}
+
+func validateAlias(name string, expansion string) error {
+	if name == "" || strings.ContainsAny(name, " \t=") || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid alias name '%s'", name)
+	}
+	if _, ok := findCommandSpec(name); ok || name == completeCommandName {
+		return fmt.Errorf("invalid alias name '%s': it is already a command", name)
+	}
+	words, err := splitCommandLine(expansion)
+	if err != nil {
+		return fmt.Errorf("invalid alias '%s': %v", name, err)
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("invalid alias '%s': expansion cannot be empty", name)
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	for _, key := range configKeys {
+		if v := *key.field(c); v != "" && key.validate != nil {
+			if err := key.validate(v); err != nil {
+				return err
+			}
+		}
+	}
+	for name, expansion := range c.Aliases {
+		if err := validateAlias(name, expansion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigPath returns $TODO_CONFIG if set, otherwise
+// $XDG_CONFIG_HOME/golang-todo-cli/config (or the platform equivalent).
+func ConfigPath() (string, error) {
+	if path := os.Getenv("TODO_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("config error: %v", err)
+	}
+	return filepath.Join(dir, configDirName, "config"), nil
+}
+
+// HooksDir returns the directory of lifecycle hooks, next to the config
+// file.
+func HooksDir() (string, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "hooks"), nil
+}
+
+// LoadConfig reads a JSON config file. A missing file is an empty config.
+func LoadConfig(path string) (Config, error) {
+	c, err := readConfig(path)
+	if err != nil {
+		return c, err
+	}
+	if err := c.validate(); err != nil {
+		return c, fmt.Errorf("config error: %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// readConfig reads the config file without validating its settings.
+func readConfig(path string) (Config, error) {
+	var c Config
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("config error: %v", err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("config error: %s: %v", path, err)
+	}
+	return c, nil
+}
+
+func (c *Config) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("config error: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("config error: %v", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("config error: %v", err)
+	}
+	return nil
+}
+
+func (c *Config) get(name string) (string, error) {
+	if alias, ok := strings.CutPrefix(name, aliasKeyPrefix); ok {
+		return c.Aliases[alias], nil
+	}
+	key, ok := findConfigKey(name)
+	if !ok {
+		return "", unknownConfigKeyError(name)
+	}
+	return *key.field(c), nil
+}
+
+// set validates and stores a value; an empty value removes the setting.
+func (c *Config) set(name string, value string) error {
+	if alias, ok := strings.CutPrefix(name, aliasKeyPrefix); ok {
+		if value == "" {
+			delete(c.Aliases, alias)
+			return nil
+		}
+		if err := validateAlias(alias, value); err != nil {
+			return err
+		}
+		if c.Aliases == nil {
+			c.Aliases = map[string]string{}
+		}
+		c.Aliases[alias] = value
+		return nil
+	}
+	key, ok := findConfigKey(name)
+	if !ok {
+		return unknownConfigKeyError(name)
+	}
+	if key.lower {
+		value = strings.ToLower(value)
+	}
+	if value != "" && key.validate != nil {
+		if err := key.validate(value); err != nil {
+			return err
+		}
+	}
+	*key.field(c) = value
+	return nil
+}
+
+func unknownConfigKeyError(name string) error {
+	return fmt.Errorf("unknown config key '%s': must be one of %s", name, quoteList(configKeyNames()))
+}
+
+// expandAlias replaces a leading alias with its expansion.
+func expandAlias(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+	expansion, ok := userConfig.Aliases[args[0]]
+	if !ok {
+		return args, nil
+	}
+	words, err := splitCommandLine(expansion)
+	if err != nil {
+		return nil, &UsageError{fmt.Errorf("alias '%s': %v", args[0], err)}
+	}
+	return append(words, args[1:]...), nil
+}
+
+func configDefault(value string, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+func aliasNames() []string {
+	return slices.Sorted(maps.Keys(userConfig.Aliases))
+}
+
+// withHooks runs the scripts in HooksDir around changes made through m, if
+// the directory exists.
+func withHooks(m tasks.Manager, stderr io.Writer) tasks.Manager {
+	dir, err := HooksDir()
+	if err != nil {
+		return m
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return m
+	}
+	hooks := tasks.Hooks{Dir: dir, Stderr: stderr}
+	if userConfig.HookTimeout != "" {
+		hooks.Timeout, _ = parseHookTimeout(userConfig.HookTimeout)
+	}
+	return tasks.WithHooks(m, hooks)
+}
+
+// NewManager opens the task file set by the db setting, or tasks.db.json in
+// the current directory.
+func NewManager() (tasks.Manager, error) {
+	if userConfig.DB == "" {
+		return tasks.NewManager()
+	}
+	path := userConfig.DB
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return tasks.NewManagerWithFile(path)
+}
+
+type ConfigCommand struct {
+	Action string
+	Key    string
+	Value  string
+}
+
+func parseConfigCmd(a []string) (Command, error) {
+	if len(a) > 0 && a[0] == "set" {
+		// the value is taken as written, since alias expansions contain flags
+		if len(a) < 3 {
+			return nil, fmt.Errorf("config error: 'set' expects a key and a value (use \"\" to unset)")
+		}
+		return &ConfigCommand{Action: "set", Key: a[1], Value: strings.Join(a[2:], " ")}, nil
+	}
+	args, err := parseArgs(newFlagSet("config"), a)
+	if err != nil {
+		return flagError("config", err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("config error: must specify one of 'list', 'get' or 'set'")
+	}
+	switch args[0] {
+	case "list":
+		if len(args) > 1 {
+			return nil, fmt.Errorf("config error: unexpected argument '%s'", args[1])
+		}
+		return &ConfigCommand{Action: "list"}, nil
+	case "get":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("config error: 'get' expects exactly one key")
+		}
+		return &ConfigCommand{Action: "get", Key: args[1]}, nil
+	}
+	return nil, fmt.Errorf("config error: invalid action '%s': must be one of 'list', 'get' or 'set'", args[0])
+}
+
+func (c *ConfigCommand) Execute(m tasks.Manager) (string, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	// invalid settings are shown and can be replaced with 'set'
+	config, err := readConfig(path)
+	if err != nil {
+		return "", err
+	}
+	switch c.Action {
+	case "get":
+		value, err := config.get(c.Key)
+		if err != nil {
+			return "", &UsageError{fmt.Errorf("config error: %v", err)}
+		}
+		return value, nil
+	case "set":
+		if err := config.set(c.Key, c.Value); err != nil {
+			return "", &UsageError{fmt.Errorf("config error: %v", err)}
+		}
+		if err := config.Save(path); err != nil {
+			return "", err
+		}
+		value, _ := config.get(c.Key)
+		if value == "" {
+			return fmt.Sprintf("Unset %s", c.Key), nil
+		}
+		return fmt.Sprintf("Set %s = %s", c.Key, value), nil
+	}
+	lines := []string{}
+	for _, key := range configKeys {
+		if v := *key.field(&config); v != "" {
+			lines = append(lines, key.name+" = "+v)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Aliases)) {
+		lines = append(lines, aliasKeyPrefix+name+" = "+config.Aliases[name])
+	}
+	if len(lines) == 0 {
+		return fmt.Sprintf("No settings in %s", path), nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
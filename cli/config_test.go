@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+// TestMain points Run at a config file that does not exist, so tests never
+// read the user's own settings.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "todo-config")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("TODO_CONFIG", filepath.Join(dir, "config"))
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func useConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), configDirName, "config")
+	if contents != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	t.Setenv("TODO_CONFIG", path)
+	t.Cleanup(func() { userConfig = Config{} })
+	return path
+}
+
+func runWith(m tasks.Manager, args ...string) (int, string, string) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := Run(args, func() (tasks.Manager, error) { return m, nil }, stdout, stderr)
+	return code, strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String())
+}
+
+func TestConfigCommandTableDriven(t *testing.T) {
+	path := useConfigFile(t, "")
+	m := newMockManager(testTime)
+	tests := []struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{args: []string{"config", "list"}, wantStdout: "No settings in " + path},
+		{args: []string{"config", "set", "priority", "HIGH"}, wantStdout: "Set priority = high"},
+		{args: []string{"config", "set", "date-format", "Jan 2"}, wantStdout: "Set date-format = Jan 2"},
+		{args: []string{"config", "set", "alias.t", "list", "-status", "pending"}, wantStdout: "Set alias.t = list -status pending"},
+		{args: []string{"config", "get", "priority"}, wantStdout: "high"},
+		{args: []string{"config", "get", "due"}},
+		{args: []string{"config", "list"}, wantStdout: "priority = high\ndate-format = Jan 2\nalias.t = list -status pending"},
+		{args: []string{"config", "set", "date-format", ""}, wantStdout: "Unset date-format"},
+		{args: []string{"config", "set", "priority", "urgent"}, wantCode: ExitUsage, wantStderr: "invalid priority format"},
+		{args: []string{"config", "set", "week-start", "friday"}, wantCode: ExitUsage, wantStderr: "invalid week start"},
+		{args: []string{"config", "set", "date-format", "15:04"}, wantCode: ExitUsage, wantStderr: "invalid date format"},
+		{args: []string{"config", "set", "remote", "localhost:8080"}, wantCode: ExitUsage, wantStderr: "invalid remote URL 'localhost:8080'"},
+		{args: []string{"config", "set", "hook-timeout", "soon"}, wantCode: ExitUsage, wantStderr: "invalid hook timeout 'soon'"},
+		{args: []string{"config", "set", "wrap", "sometimes"}, wantCode: ExitUsage, wantStderr: "invalid boolean 'sometimes': must be 'true' or 'false'"},
+		{args: []string{"config", "set", "colour", "red"}, wantCode: ExitUsage, wantStderr: "unknown config key 'colour'"},
+		{args: []string{"config", "set", "alias.add", "list"}, wantCode: ExitUsage, wantStderr: "it is already a command"},
+		{args: []string{"config", "set", "alias.x", "list 'oops"}, wantCode: ExitUsage, wantStderr: "unterminated ' quote"},
+		{args: []string{"config", "get"}, wantCode: ExitUsage, wantStderr: "'get' expects exactly one key"},
+		{args: []string{"config", "remove", "priority"}, wantCode: ExitUsage, wantStderr: "invalid action 'remove'"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			code, stdout, stderr := runWith(&m, tt.args...)
+			if code != tt.wantCode || stdout != tt.wantStdout || !strings.Contains(stderr, tt.wantStderr) {
+				t.Fatalf("wanted code=%d stdout=%q stderr~%q, got code=%d stdout=%q stderr=%q", tt.wantCode, tt.wantStdout, tt.wantStderr, code, stdout, stderr)
+			}
+		})
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(contents), `"priority": "high"`) || strings.Contains(string(contents), "dateFormat") {
+		t.Fatalf("unexpected config file: %s (%v)", contents, err)
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	useConfigFile(t, `{
+  "priority": "high",
+  "due": "tomorrow",
+  "category": "work",
+  "output": "json",
+  "errorOutput": "json",
+  "sort": "-due",
+  "aliases": {"t": "list -status pending"}
+}`)
+	m := newMockManager(testTime)
+	m.addNextOk = &tasks.Task{Id: 1, Title: "Call dentist"}
+
+	if code, _, stderr := runWith(&m, "add", "Call dentist"); code != ExitOK {
+		t.Fatalf("unexpected failure: %v", stderr)
+	}
+	want := addCall{title: "Call dentist", priority: tasks.High, dueDate: tasks.DueDate(testTime.AddDate(0, 0, 1)), category: "work"}
+	if len(m.addCalls) != 1 || m.addCalls[0].priority != want.priority || m.addCalls[0].dueDate != want.dueDate || m.addCalls[0].category != want.category {
+		t.Fatalf("expected config defaults %v, got %v", want, m.addCalls)
+	}
+
+	if code, _, stderr := runWith(&m, "add", "Call dentist !low", "-c", "home"); code != ExitOK || m.addCalls[1].priority != tasks.Low || m.addCalls[1].category != "home" {
+		t.Fatalf("expected flags and inline tokens to override config, got %v (%v)", m.addCalls, stderr)
+	}
+
+	if code, _, stderr := runWith(&m, "t", "-p", "high"); code != ExitOK {
+		t.Fatalf("unexpected failure: %v", stderr)
+	}
+	if len(m.listCalls) != 1 || *m.listCalls[0].status != tasks.Pending || *m.listCalls[0].priority != tasks.High {
+		t.Fatalf("expected alias to expand to a pending list, got %v", m.listCalls)
+	}
+
+	if code, _, stderr := runWith(&m, "frobnicate"); code != ExitUsage || !strings.HasPrefix(stderr, `{"error":`) {
+		t.Fatalf("expected JSON error output from config, got %q", stderr)
+	}
+	if _, _, stderr := runWith(&m, "--output", "text", "frobnicate"); !strings.HasPrefix(stderr, "Error:") {
+		t.Fatalf("expected --output to override config, got %q", stderr)
+	}
+
+	args := []string{"list"}
+	cmd, err := Parse(&args)
+	if err != nil || cmd.(*ListCommand).Sort.String() != "-due" {
+		t.Fatalf("expected default sort from config, got %v (%v)", cmd, err)
+	}
+
+	m.listNextOk = []tasks.Task{{Id: 1, Title: "Call dentist"}}
+	if code, stdout, _ := runWith(&m, "list"); code != ExitOK || !strings.HasPrefix(stdout, "[") {
+		t.Fatalf("expected JSON list output from config, got %q", stdout)
+	}
+	if _, stdout, _ := runWith(&m, "list", "-format", "table"); !strings.Contains(stdout, "|Call dentist") {
+		t.Fatalf("expected -format to override config, got %q", stdout)
+	}
+	useConfigFile(t, `{"columns": "id,title", "style": "compact", "wrap": "true"}`)
+	t.Setenv("TODO_STYLE", "borderless")
+	code, stdout, stderr := runWith(&m, "config", "get", "columns")
+	if code != ExitOK || stdout != "id,title" {
+		t.Fatalf("unexpected config get result: %d %q %q", code, stdout, stderr)
+	}
+	args = []string{"list"}
+	cmd, err = Parse(&args)
+	if table := cmd.(*ListCommand).Table; err != nil || !reflect.DeepEqual(table.Columns, []string{"id", "title"}) || !table.Wrap || table.Style != tasks.StyleBorderless {
+		t.Fatalf("expected table defaults from config and the environment, got %+v (%v)", cmd, err)
+	}
+
+	useConfigFile(t, `{"output": "json"}`)
+	runWith(&m, "config", "list")
+	args = []string{"stats"}
+	if cmd, err := Parse(&args); err != nil || cmd.(*StatsCommand).Format != "json" {
+		t.Fatalf("expected stats to default to JSON from config, got %v (%v)", cmd, err)
+	}
+}
+
+func TestConfigInvalidFile(t *testing.T) {
+	for _, contents := range []string{`{"priority": `, `{"priority": "urgent"}`, `{"aliases": {"list": "add"}}`} {
+		useConfigFile(t, contents)
+		m := newMockManager(testTime)
+		code, _, stderr := runWith(&m, "list")
+		if code != ExitFailure || !strings.Contains(stderr, "config error") {
+			t.Fatalf("expected config error for %s, got code=%d stderr=%q", contents, code, stderr)
+		}
+		if code, stdout, stderr := runWith(&m, "help", "add"); code != ExitOK || !strings.Contains(stdout, "Usage: todo add") {
+			t.Fatalf("expected help despite %s, got code=%d stderr=%q", contents, code, stderr)
+		}
+	}
+
+	useConfigFile(t, `{"priority": "urgent", "category": "work"}`)
+	m := newMockManager(testTime)
+	if code, stdout, stderr := runWith(&m, "config", "list"); code != ExitOK || stdout != "priority = urgent\ncategory = work" {
+		t.Fatalf("expected config list to show invalid settings, got code=%d stdout=%q stderr=%q", code, stdout, stderr)
+	}
+	if code, stdout, stderr := runWith(&m, "config", "set", "priority", "high"); code != ExitOK || stdout != "Set priority = high" {
+		t.Fatalf("expected config set to fix the file, got code=%d stdout=%q stderr=%q", code, stdout, stderr)
+	}
+	if code, _, stderr := runWith(&m, "list"); code != ExitOK {
+		t.Fatalf("expected the fixed config to load, got code=%d stderr=%q", code, stderr)
+	}
+
+	useConfigFile(t, `{"priority": `)
+	if code, _, stderr := runWith(&m, "config", "set", "priority", "high"); code != ExitFailure || !strings.Contains(stderr, "config error") {
+		t.Fatalf("expected config set to report an unreadable file, got code=%d stderr=%q", code, stderr)
+	}
+}
+
+func TestConfigCompletion(t *testing.T) {
+	useConfigFile(t, "")
+	userConfig.Aliases = map[string]string{"t": "list -status pending"}
+	m := newMockManager(testTime)
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{words: []string{""}, want: []string{"t\tAlias for 'list -status pending'", "config\t"}},
+		{words: []string{"t", "--prio"}, want: []string{"--priority"}},
+		{words: []string{"config", ""}, want: []string{"list", "get", "set"}},
+		{words: []string{"config", "set", "d"}, want: []string{"due\t", "date-format\t", "db\t"}},
+		{words: []string{"config", "get", "alias."}, want: []string{"alias.t\tlist -status pending"}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.words, " "), func(t *testing.T) {
+			args := append([]string{"__complete"}, tt.words...)
+			cmd, err := Parse(&args)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			got, err := cmd.Execute(&m)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Fatalf("expected candidates to contain %q, got:\n%v", want, got)
+				}
+			}
+		})
+	}
+}
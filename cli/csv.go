@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+var csvFields = []string{"id", "title", "priority", "due", "category", "status", "tags", "description", "notes", "created", "completed"}
+
+type csvColumn struct {
+	field  string
+	header string
+}
+
+// parseCSVColumns reads "field" or "field=Header" items separated by commas.
+func parseCSVColumns(s string) ([]csvColumn, error) {
+	var columns []csvColumn
+	for _, item := range strings.Split(s, ",") {
+		field, header, _ := strings.Cut(item, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !slices.Contains(csvFields, field) {
+			return nil, fmt.Errorf("invalid column '%s': must be one of %s", field, strings.Join(csvFields, ", "))
+		}
+		header = strings.TrimSpace(header)
+		if header == "" {
+			header = field
+		}
+		columns = append(columns, csvColumn{field: field, header: header})
+	}
+	return columns, nil
+}
+
+func csvValue(t *tasks.Task, field string) string {
+	switch field {
+	case "id":
+		return strconv.Itoa(t.Id)
+	case "title":
+		return t.Title
+	case "priority":
+		return strings.ToLower(t.Priority.String())
+	case "due":
+		return time.Time(t.DueDate).Format("2006-01-02")
+	case "category":
+		return t.Category
+	case "status":
+		return t.Status.String()
+	case "tags":
+		return strings.Join(t.Tags, ",")
+	case "description":
+		return t.Description
+	case "notes":
+		return strings.Join(t.Notes, "\n")
+	case "created":
+		return formatCSVTime(t.CreatedAt)
+	case "completed":
+		return formatCSVTime(t.CompletedAt)
+	}
+	return ""
+}
+
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func parseCSVTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s': must be yyyy-MM-dd or RFC 3339", s)
+	}
+	return t, nil
+}
+
+// exportCSV writes a header row and one row per task, with every field
+// unless columns are given.
+func exportCSV(w io.Writer, t []tasks.Task, opts exchangeOptions) ([]tasks.Issue, error) {
+	columns := opts.columns
+	if len(columns) == 0 {
+		for _, field := range csvFields {
+			columns = append(columns, csvColumn{field: field, header: field})
+		}
+	}
+	cw := csv.NewWriter(w)
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		row[i] = c.header
+	}
+	if err := cw.Write(row); err != nil {
+		return nil, err
+	}
+	for _, task := range t {
+		for i, c := range columns {
+			row[i] = csvValue(&task, c.field)
+		}
+		if err := cw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	cw.Flush()
+	return nil, cw.Error()
+}
+
+// importCSV reads rows by their header: a header names a field, or is mapped
+// to one by the columns option. Each row is checked like an add command.
+func importCSV(r io.Reader, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("missing header row")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var issues []tasks.Issue
+	fields := make([]string, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		for _, c := range opts.columns {
+			if strings.EqualFold(c.header, name) {
+				fields[i] = c.field
+			}
+		}
+		if fields[i] == "" && slices.Contains(csvFields, strings.ToLower(name)) {
+			fields[i] = strings.ToLower(name)
+		}
+		switch {
+		case fields[i] == "":
+			issues = append(issues, tasks.Issue{Line: 1, Message: fmt.Sprintf("column '%s' ignored", name)})
+		case fields[i] == "id":
+			// ids are assigned on import
+			fields[i] = ""
+		}
+	}
+	if !slices.Contains(fields, "title") {
+		return nil, nil, fmt.Errorf("no title column (use -columns title=<header> to pick one)")
+	}
+
+	var result []tasks.Task
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		values := map[string]string{}
+		for i, value := range record {
+			if i < len(fields) && fields[i] != "" {
+				values[fields[i]] = strings.TrimSpace(value)
+			}
+		}
+		task, err := csvTask(values, opts.now)
+		if err != nil {
+			issues = append(issues, tasks.Issue{Line: line, Message: err.Error(), Skipped: true})
+			continue
+		}
+		result = append(result, task)
+	}
+	return result, issues, nil
+}
+
+func csvTask(values map[string]string, now time.Time) (tasks.Task, error) {
+	args := []string{"-no-parse"}
+	for _, flag := range []string{"priority", "due", "category", "tags", "description"} {
+		if values[flag] != "" {
+			args = append(args, "-"+flag, values[flag])
+		}
+	}
+	for _, note := range strings.Split(values["notes"], "\n") {
+		if strings.TrimSpace(note) != "" {
+			args = append(args, "-note", note)
+		}
+	}
+	task, err := addCommandTask(append(args, "--", values["title"]), now)
+	if err != nil {
+		return task, err
+	}
+	if values["status"] != "" {
+		status, err := parseStatusFilter(values["status"])
+		if err != nil {
+			return task, err
+		}
+		task.Status = *status
+	}
+	if values["created"] != "" {
+		if task.CreatedAt, err = parseCSVTime(values["created"]); err != nil {
+			return task, err
+		}
+	}
+	if values["completed"] != "" && task.Status == tasks.Completed {
+		if task.CompletedAt, err = parseCSVTime(values["completed"]); err != nil {
+			return task, err
+		}
+	}
+	return task, nil
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestExportCSV(t *testing.T) {
+	created := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	task := tasks.Task{Id: 1, Title: "Plan, then ship", Priority: tasks.High, DueDate: tasks.DueDate(testTime),
+		Category: "work", Status: tasks.Pending, Tags: []string{"a", "b"}, Notes: []string{"one", "two"}, CreatedAt: created}
+
+	buf := bytes.Buffer{}
+	if _, err := exportCSV(&buf, []tasks.Task{task}, exchangeOptions{}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := "id,title,priority,due,category,status,tags,description,notes,created,completed\n" +
+		"1,\"Plan, then ship\",high,2024-04-10,work,pending,\"a,b\",,\"one\ntwo\",2024-04-01T09:00:00Z,\n"
+	if buf.String() != want {
+		t.Errorf("expected\n%q\ngot\n%q", want, buf.String())
+	}
+
+	buf.Reset()
+	columns := []csvColumn{{"title", "Task"}, {"due", "Deadline"}}
+	if _, err := exportCSV(&buf, []tasks.Task{task}, exchangeOptions{columns: columns}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if want := "Task,Deadline\n\"Plan, then ship\",2024-04-10\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	in := "Task,Deadline,Status,Owner,tags\n" +
+		"Pay rent,2024-05-01,completed,me,\"home,money\"\n" +
+		",2024-05-01,pending,me,\n" +
+		"Call Bob !high,someday,pending,you,\n" +
+		"Water plants #garden,+2d,,us,\n"
+	columns := []csvColumn{{"title", "Task"}, {"due", "Deadline"}}
+	got, issues, err := importCSV(strings.NewReader(in), exchangeOptions{now: testTime, columns: columns})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	want := []tasks.Task{
+		{Title: "Pay rent", Priority: tasks.Medium, DueDate: tasks.DueDate(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+			Status: tasks.Completed, Tags: []string{"home", "money"}},
+		{Title: "Water plants #garden", Priority: tasks.Medium, DueDate: tasks.DueDate(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)),
+			Status: tasks.Pending},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d tasks, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i].Title != want[i].Title || got[i].Status != want[i].Status || !reflect.DeepEqual(got[i].Tags, want[i].Tags) ||
+			!time.Time(got[i].DueDate).Equal(time.Time(want[i].DueDate)) {
+			t.Errorf("task %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	wantIssues := []string{
+		"line 1: column 'Owner' ignored",
+		"line 3: title cannot be empty",
+		"line 4: invalid date format yyyy-MM-dd",
+	}
+	if len(lines) != len(wantIssues) {
+		t.Fatalf("expected issues %q, got %q", wantIssues, lines)
+	}
+	for i := range wantIssues {
+		if !strings.HasPrefix(lines[i], wantIssues[i]) {
+			t.Errorf("expected issue %q, got %q", wantIssues[i], lines[i])
+		}
+	}
+
+	if _, _, err := importCSV(strings.NewReader("name,due\nx,today\n"), exchangeOptions{now: testTime}); err == nil ||
+		!strings.Contains(err.Error(), "no title column") {
+		t.Errorf("expected missing title column error, got %v", err)
+	}
+}
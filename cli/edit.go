@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type EditCommand struct {
+	Id          int
+	Title       *string
+	Priority    *tasks.Priority
+	Due         IntoDueDate
+	Category    *string
+	Description *string
+	Tags        *[]string
+	Notes       []string
+	Status      *tasks.Status
+}
+
+func (e *EditCommand) Execute(m tasks.Manager) (string, error) {
+	patch := tasks.TaskPatch{
+		Title:       e.Title,
+		Priority:    e.Priority,
+		Category:    e.Category,
+		Description: e.Description,
+		Tags:        e.Tags,
+		Status:      e.Status,
+	}
+	if e.Due != nil {
+		due := e.Due.IntoDueDate(m.Now())
+		patch.DueDate = &due
+	}
+	if len(e.Notes) > 0 {
+		task, err := m.GetTask(e.Id)
+		if err != nil {
+			return "", err
+		}
+		notes := append(slices.Clone(task.Notes), e.Notes...)
+		patch.Notes = &notes
+	}
+
+	updated, err := m.UpdateTask(e.Id, patch)
+	if err != nil {
+		return "", err
+	}
+	return "Updated task '" + updated.Title + "' successfully (ID: " + fmt.Sprint(updated.Id) + ")", nil
+}
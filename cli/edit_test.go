@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestEditExecuteTableDriven(t *testing.T) {
+	m := newMockManager(testTime)
+	title := "Call the dentist"
+	priority := tasks.High
+	due := tasks.DueDate(testTime.Add(48 * time.Hour))
+	tests := []struct {
+		name      string
+		editCmd   EditCommand
+		existing  *tasks.Task
+		wantPatch tasks.TaskPatch
+	}{
+		{
+			name:      "edit fields",
+			editCmd:   EditCommand{Id: 1, Title: &title, Priority: &priority, Due: &DueInDays{Days: 2}},
+			wantPatch: tasks.TaskPatch{Title: &title, Priority: &priority, DueDate: &due},
+		},
+		{
+			name:      "append notes",
+			editCmd:   EditCommand{Id: 1, Notes: []string{"Morning only"}},
+			existing:  &tasks.Task{Id: 1, Title: title, Notes: []string{"Ask about x-rays"}},
+			wantPatch: tasks.TaskPatch{Notes: &[]string{"Ask about x-rays", "Morning only"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.reset()
+			m.getNextOk = tt.existing
+			m.updateNextOk = &tasks.Task{Id: 1, Title: title}
+
+			got, err := tt.editCmd.Execute(&m)
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(m.updateCalls) != 1 || m.updateCalls[0].id != 1 || !reflect.DeepEqual(m.updateCalls[0].patch, tt.wantPatch) {
+				t.Fatalf("unexpected update calls: wanted patch %+v, got %+v", tt.wantPatch, m.updateCalls)
+			}
+			if !strings.Contains(got, "Updated task 'Call the dentist' successfully (ID: 1)") {
+				t.Fatalf("unexpected output: %v", got)
+			}
+		})
+	}
+}
+
+func TestEditExecuteErrorsTableDriven(t *testing.T) {
+	m := newMockManager(testTime)
+	m.updateNextErr = errors.New("task 999 not found")
+
+	_, err := (&EditCommand{Id: 999}).Execute(&m)
+
+	if err == nil || !strings.Contains(err.Error(), "task 999 not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
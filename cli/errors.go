@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+// Exit codes returned by Run. Scripts can rely on these staying stable.
+const (
+	ExitOK          = 0
+	ExitFailure     = 1
+	ExitUsage       = 2
+	ExitNotFound    = 3
+	ExitConflict    = 4
+	ExitStorage     = 5
+	ExitUnavailable = 6
+	ExitRejected    = 7
+)
+
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func ExitCode(err error) int {
+	var usageErr *UsageError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.Is(err, tasks.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, tasks.ErrAlreadyCompleted), errors.Is(err, tasks.ErrInvalidTransition), errors.Is(err, tasks.ErrChanged):
+		return ExitConflict
+	case errors.Is(err, tasks.ErrStorage):
+		return ExitStorage
+	case errors.Is(err, tasks.ErrUnavailable):
+		return ExitUnavailable
+	case errors.Is(err, tasks.ErrRejected):
+		return ExitRejected
+	}
+	return ExitFailure
+}
+
+var exitCodeNames = map[int]string{
+	ExitFailure:     "error",
+	ExitUsage:       "usage",
+	ExitNotFound:    "not_found",
+	ExitConflict:    "conflict",
+	ExitStorage:     "storage",
+	ExitUnavailable: "unavailable",
+	ExitRejected:    "rejected",
+}
+
+var exitCodeDescriptions = map[int]string{
+	ExitOK:          "success",
+	ExitFailure:     "other failure",
+	ExitUsage:       "invalid command, flag or argument",
+	ExitNotFound:    "task or view not found",
+	ExitConflict:    "task is already completed, was changed by someone else, or the status change is not allowed",
+	ExitStorage:     "the task or view file could not be read or written",
+	ExitUnavailable: "the task server given by --remote could not be reached",
+	ExitRejected:    "a pre-hook refused the change",
+}
+
+type errorObject struct {
+	Error    string `json:"error"`
+	Code     string `json:"code"`
+	ExitCode int    `json:"exitCode"`
+}
+
+func FormatError(err error, output string) string {
+	code := ExitCode(err)
+	if output == OutputJSON {
+		b, jsonErr := json.Marshal(errorObject{Error: err.Error(), Code: exitCodeNames[code], ExitCode: code})
+		if jsonErr == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("Error: %v", err)
+}
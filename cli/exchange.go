@@ -0,0 +1,546 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+// exchangeOptions holds the settings of one export or import.
+type exchangeOptions struct {
+	now     time.Time
+	columns []csvColumn
+}
+
+type exchangeFormat struct {
+	name       string
+	extensions []string
+	export     func(w io.Writer, t []tasks.Task, opts exchangeOptions) ([]tasks.Issue, error)
+	parse      func(r io.Reader, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error)
+	// directory formats write and read one file per task instead
+	exportDir func(dir string, t []tasks.Task, opts exchangeOptions) ([]tasks.Issue, error)
+	parseDir  func(dir string, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error)
+}
+
+var exchangeFormats = []exchangeFormat{
+	{name: "json", extensions: []string{".json"}, export: exportJSON, parse: importJSON},
+	{
+		name:       "ics",
+		extensions: []string{".ics", ".ical"},
+		export: func(w io.Writer, t []tasks.Task, opts exchangeOptions) ([]tasks.Issue, error) {
+			return nil, tasks.ExportICS(w, t, opts.now)
+		},
+		parse: func(r io.Reader, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error) {
+			return tasks.ImportICS(r, opts.now)
+		},
+	},
+	{
+		name:       "todotxt",
+		extensions: []string{".txt"},
+		export: func(w io.Writer, t []tasks.Task, opts exchangeOptions) ([]tasks.Issue, error) {
+			return tasks.ExportTodoTxt(w, t, opts.now)
+		},
+		parse: func(r io.Reader, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error) {
+			return tasks.ImportTodoTxt(r, opts.now)
+		},
+	},
+	{
+		name: "taskwarrior",
+		export: func(w io.Writer, t []tasks.Task, opts exchangeOptions) ([]tasks.Issue, error) {
+			return tasks.ExportTaskwarrior(w, t, opts.now)
+		},
+		parse: func(r io.Reader, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error) {
+			return tasks.ImportTaskwarrior(r, opts.now)
+		},
+	},
+	{name: "csv", extensions: []string{".csv"}, export: exportCSV, parse: importCSV},
+	{name: "markdown", extensions: []string{".md", ".markdown"}, export: exportMarkdown, parse: importMarkdown},
+	{
+		name:       "org",
+		extensions: []string{".org"},
+		export: func(w io.Writer, t []tasks.Task, opts exchangeOptions) ([]tasks.Issue, error) {
+			return tasks.ExportOrg(w, t, opts.now)
+		},
+	},
+	{name: "frontmatter", exportDir: exportFrontmatter, parseDir: importFrontmatter},
+}
+
+func exchangeFormatNames() []string {
+	names := make([]string, 0, len(exchangeFormats))
+	for _, f := range exchangeFormats {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// findExchangeFormat looks a format up by name, or when name is empty by the
+// extension of path.
+func findExchangeFormat(name string, path string) (*exchangeFormat, error) {
+	for i, f := range exchangeFormats {
+		if name != "" && strings.EqualFold(f.name, name) {
+			return &exchangeFormats[i], nil
+		}
+		if name == "" && slices.Contains(f.extensions, strings.ToLower(filepath.Ext(path))) {
+			return &exchangeFormats[i], nil
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("cannot tell the format of '%s': use -format with one of %s", path, quoteList(exchangeFormatNames()))
+	}
+	return nil, fmt.Errorf("invalid format '%s': must be one of %s", name, quoteList(exchangeFormatNames()))
+}
+
+func exportJSON(w io.Writer, t []tasks.Task, _ exchangeOptions) ([]tasks.Issue, error) {
+	out, err := tasks.RenderJSON(t)
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintln(w, out)
+	return nil, err
+}
+
+// importJSON reads a task file or 'export -format json' output. Tasks keep
+// their identity through TaskUID, so importing the same file twice updates
+// rather than duplicates them.
+func importJSON(r io.Reader, _ exchangeOptions) ([]tasks.Task, []tasks.Issue, error) {
+	var t []tasks.Task
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, nil, err
+	}
+	result := make([]tasks.Task, 0, len(t))
+	var issues []tasks.Issue
+	for i, task := range t {
+		if strings.TrimSpace(task.Title) == "" {
+			issues = append(issues, tasks.Issue{Message: fmt.Sprintf("task %d has no title", i+1), Skipped: true})
+			continue
+		}
+		task.UID = tasks.TaskUID(&task)
+		result = append(result, task)
+	}
+	return result, issues, nil
+}
+
+type ExportCommand struct {
+	Format         string
+	File           string
+	StatusFilter   *tasks.Status
+	PriorityFilter *tasks.Priority
+	CategoryFilter string
+	Columns        []csvColumn
+}
+
+type exportFlags struct {
+	format   *string
+	file     *string
+	status   *string
+	priority *string
+	category *string
+	columns  *string
+}
+
+func newExportFlagSet() (*flag.FlagSet, *exportFlags) {
+	fs := newFlagSet("export")
+	f := &exportFlags{
+		format:   fs.String("format", "", "Export format: "+strings.Join(exchangeFormatNames(), ", ")+" (default: from the file extension, or json)"),
+		file:     fs.String("file", "", "Write to this file instead of stdout"),
+		status:   fs.String("status", "", "Status filter: pending, completed"),
+		priority: fs.String("priority", "", "Priority filter: low, medium, high"),
+		category: fs.String("category", "", "Category filter"),
+		columns:  fs.String("columns", "", "CSV columns as field or field=Header, e.g. 'title=Task,due' (fields: "+strings.Join(csvFields, ", ")+")"),
+	}
+	alias(fs, "f", "file")
+	alias(fs, "s", "status")
+	alias(fs, "p", "priority")
+	alias(fs, "c", "category")
+	return fs, f
+}
+
+func parseExportCmd(a []string) (Command, error) {
+	exportFlagSet, f := newExportFlagSet()
+	args, err := parseArgs(exportFlagSet, a)
+	if err != nil {
+		return flagError("export", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("export error: unexpected argument '%s' (use -f to write to a file)", args[0])
+	}
+	format := *f.format
+	if format == "" && (*f.file == "" || *f.file == "-") {
+		format = "json"
+	}
+	exchange, err := findExchangeFormat(format, *f.file)
+	if err != nil {
+		return nil, fmt.Errorf("export error: %v", err)
+	}
+	if exchange.exportDir != nil && (*f.file == "" || *f.file == "-") {
+		return nil, fmt.Errorf("export error: the %s format writes one file per task: use -f <directory>", exchange.name)
+	}
+	statusFilter, err := parseStatusFilter(*f.status)
+	if err != nil {
+		return nil, err
+	}
+	priorityFilter, err := parsePriorityFilter(*f.priority)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := parseExchangeColumns("export", exchange.name, *f.columns)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportCommand{
+		Format:         exchange.name,
+		File:           *f.file,
+		StatusFilter:   statusFilter,
+		PriorityFilter: priorityFilter,
+		CategoryFilter: *f.category,
+		Columns:        columns,
+	}, nil
+}
+
+func (e *ExportCommand) Execute(m tasks.Manager) (string, error) {
+	return e.run(m, os.Stderr)
+}
+
+// run reports dropped fields on stderr when the export itself goes to
+// stdout, and after the summary otherwise.
+func (e *ExportCommand) run(m tasks.Manager, stderr io.Writer) (string, error) {
+	exchange, err := findExchangeFormat(e.Format, "")
+	if err != nil {
+		return "", err
+	}
+	t, err := m.ListTasks(e.StatusFilter, e.PriorityFilter, e.CategoryFilter, false)
+	if err != nil {
+		return "", err
+	}
+	opts := exchangeOptions{now: m.Now(), columns: e.Columns}
+	buf := bytes.Buffer{}
+	var issues []tasks.Issue
+	if exchange.exportDir != nil {
+		issues, err = exchange.exportDir(e.File, t, opts)
+	} else {
+		issues, err = exchange.export(&buf, t, opts)
+	}
+	if err != nil {
+		return "", fmt.Errorf("export error: %v", err)
+	}
+	lines := []string{}
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	if e.File == "" || e.File == "-" {
+		for _, line := range lines {
+			fmt.Fprintln(stderr, line)
+		}
+		return strings.TrimRight(buf.String(), "\r\n"), nil
+	}
+	if exchange.exportDir == nil {
+		if err := os.WriteFile(e.File, buf.Bytes(), 0644); err != nil {
+			return "", fmt.Errorf("export error: %v", err)
+		}
+	}
+	return strings.Join(append([]string{fmt.Sprintf("Exported %d task(s) to %s", len(t), e.File)}, lines...), "\n"), nil
+}
+
+type ImportCommand struct {
+	Format  string
+	File    string
+	DryRun  bool
+	Columns []csvColumn
+}
+
+type importFlags struct {
+	format  *string
+	dryRun  *bool
+	columns *string
+}
+
+func newImportFlagSet() (*flag.FlagSet, *importFlags) {
+	fs := newFlagSet("import")
+	f := &importFlags{
+		format:  fs.String("format", "", "Import format: "+strings.Join(exchangeFormatNames(), ", ")+" (default: from the file extension)"),
+		dryRun:  fs.Bool("dry-run", false, "Show what would be imported without saving anything"),
+		columns: fs.String("columns", "", "CSV headers to read as fields, as field=Header, e.g. 'title=Task,due=Deadline'"),
+	}
+	alias(fs, "n", "dry-run")
+	return fs, f
+}
+
+func parseImportCmd(a []string) (Command, error) {
+	importFlagSet, f := newImportFlagSet()
+	args, err := parseArgs(importFlagSet, a)
+	if err != nil {
+		return flagError("import", err)
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("import error: expected one file name ('-' for stdin)")
+	}
+	if args[0] == "-" && *f.format == "" {
+		return nil, fmt.Errorf("import error: -format is required when reading stdin")
+	}
+	exchange, err := findExchangeFormat(*f.format, args[0])
+	if err != nil {
+		return nil, fmt.Errorf("import error: %v", err)
+	}
+	switch {
+	case exchange.parse == nil && exchange.parseDir == nil:
+		return nil, fmt.Errorf("import error: the %s format can only be exported", exchange.name)
+	case exchange.parseDir != nil && args[0] == "-":
+		return nil, fmt.Errorf("import error: the %s format reads a directory, not stdin", exchange.name)
+	}
+	columns, err := parseExchangeColumns("import", exchange.name, *f.columns)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportCommand{Format: exchange.name, File: args[0], DryRun: *f.dryRun, Columns: columns}, nil
+}
+
+func (c *ImportCommand) Execute(m tasks.Manager) (string, error) {
+	exchange, err := findExchangeFormat(c.Format, "")
+	if err != nil {
+		return "", err
+	}
+	imported, issues, err := c.read(exchange, exchangeOptions{now: m.Now(), columns: c.Columns})
+	if err != nil {
+		return "", err
+	}
+	plan, err := planImport(m, imported)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{}
+	skipped := 0
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+		if issue.Skipped {
+			skipped++
+		}
+	}
+	counts := fmt.Sprintf("%d added, %d updated, %d unchanged, %d skipped", len(plan.add), len(plan.update), plan.unchanged, skipped)
+	if c.DryRun {
+		for _, task := range plan.add {
+			lines = append(lines, fmt.Sprintf("add: %s", task.Title))
+		}
+		for _, u := range plan.update {
+			lines = append(lines, fmt.Sprintf("update %d: %s", u.id, u.title))
+		}
+		return strings.Join(append(lines, "Dry run, nothing saved: "+counts), "\n"), nil
+	}
+	if err := plan.apply(m); err != nil {
+		return "", err
+	}
+	return strings.Join(append(lines, fmt.Sprintf("Imported %s: %s", c.File, counts)), "\n"), nil
+}
+
+// read reports a file that cannot be opened as a failure, and one that
+// cannot be parsed as a usage error.
+func (c *ImportCommand) read(exchange *exchangeFormat, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error) {
+	var imported []tasks.Task
+	var issues []tasks.Issue
+	var err error
+	if exchange.parseDir != nil {
+		info, err := os.Stat(c.File)
+		if err != nil {
+			return nil, nil, fmt.Errorf("import error: %v", err)
+		}
+		if !info.IsDir() {
+			return nil, nil, fmt.Errorf("import error: the %s format reads a directory, and %s is a file", exchange.name, c.File)
+		}
+		imported, issues, err = exchange.parseDir(c.File, opts)
+	} else {
+		in := io.Reader(os.Stdin)
+		if c.File != "-" {
+			file, err := os.Open(c.File)
+			if err != nil {
+				return nil, nil, fmt.Errorf("import error: %v", err)
+			}
+			defer file.Close()
+			in = file
+		}
+		imported, issues, err = exchange.parse(in, opts)
+	}
+	if err != nil {
+		return nil, nil, &UsageError{fmt.Errorf("import error: %s: %v", c.File, err)}
+	}
+	return imported, issues, nil
+}
+
+func parseExchangeColumns(command string, format string, s string) ([]csvColumn, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if format != "csv" {
+		return nil, fmt.Errorf("%s error: -columns only applies to the csv format", command)
+	}
+	columns, err := parseCSVColumns(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %v", command, err)
+	}
+	return columns, nil
+}
+
+// addCommandTask validates an imported record with the add command's rules
+// and returns the task it would add. Fields the record leaves out get the
+// built-in defaults rather than the user's configured ones, so a file means
+// the same thing whoever imports it.
+func addCommandTask(args []string, now time.Time) (tasks.Task, error) {
+	cmd, err := parseAdd(args, Config{})
+	if err != nil {
+		return tasks.Task{}, errors.New(strings.TrimPrefix(err.Error(), "add error: "))
+	}
+	add := cmd.(*AddCommand)
+	return tasks.Task{
+		Title:       add.Title,
+		Priority:    add.Priority,
+		DueDate:     add.Due.IntoDueDate(now),
+		Category:    add.Category,
+		Status:      tasks.Pending,
+		Description: add.Description,
+		Notes:       add.Notes,
+		Tags:        add.Tags,
+	}, nil
+}
+
+type importUpdate struct {
+	id    int
+	title string
+	patch tasks.TaskPatch
+}
+
+type importPlan struct {
+	add       []tasks.Task
+	update    []importUpdate
+	unchanged int
+}
+
+// planImport matches imported tasks with a UID to the existing task with the
+// same TaskUID, and tasks with only an id to the existing task with that id;
+// the rest are added.
+func planImport(m tasks.Manager, imported []tasks.Task) (*importPlan, error) {
+	existing, err := m.ListTasks(nil, nil, "", false)
+	if err != nil {
+		return nil, err
+	}
+	byKey := map[string]*tasks.Task{}
+	for i := range existing {
+		byKey["uid:"+tasks.TaskUID(&existing[i])] = &existing[i]
+		byKey[fmt.Sprintf("id:%d", existing[i].Id)] = &existing[i]
+	}
+	added := map[string]int{}
+	plan := &importPlan{}
+	for _, task := range imported {
+		key := ""
+		switch {
+		case task.UID != "":
+			key = "uid:" + task.UID
+		case task.Id != 0:
+			key = fmt.Sprintf("id:%d", task.Id)
+		}
+		if key != "" {
+			if i, ok := added[key]; ok {
+				// a later copy in the same file wins
+				plan.add[i] = task
+				continue
+			}
+			if current, ok := byKey[key]; ok {
+				patch := importPatch(current, &task)
+				if patch.IsEmpty() {
+					plan.unchanged++
+				} else {
+					plan.update = append(plan.update, importUpdate{id: current.Id, title: task.Title, patch: patch})
+				}
+				continue
+			}
+			added[key] = len(plan.add)
+		}
+		plan.add = append(plan.add, task)
+	}
+	return plan, nil
+}
+
+// importPatch returns the changes that make current match imported.
+func importPatch(current *tasks.Task, imported *tasks.Task) tasks.TaskPatch {
+	var patch tasks.TaskPatch
+	if current.Title != imported.Title {
+		patch.Title = &imported.Title
+	}
+	if current.Priority != imported.Priority {
+		patch.Priority = &imported.Priority
+	}
+	if !time.Time(current.DueDate).Equal(time.Time(imported.DueDate)) {
+		patch.DueDate = &imported.DueDate
+	}
+	if current.Category != imported.Category {
+		patch.Category = &imported.Category
+	}
+	if current.Status != imported.Status {
+		patch.Status = &imported.Status
+		patch.CompletedAt = &imported.CompletedAt
+	}
+	if current.Description != imported.Description {
+		patch.Description = &imported.Description
+	}
+	if !slices.Equal(current.Notes, imported.Notes) {
+		patch.Notes = &imported.Notes
+	}
+	if !slices.Equal(current.Tags, imported.Tags) {
+		patch.Tags = &imported.Tags
+	}
+	if current.Recurrence != imported.Recurrence {
+		patch.Recurrence = &imported.Recurrence
+	}
+	return patch
+}
+
+// apply saves the plan in one batch when the manager supports batching.
+// Inside a shell or batch session the plan joins the open batch, which the
+// session commits or rolls back.
+func (p *importPlan) apply(m tasks.Manager) error {
+	batcher, batching := m.(tasks.Batcher)
+	if !batching || batcher.InBatch() {
+		return p.run(m)
+	}
+	batcher.Begin()
+	err := p.run(m)
+	if err != nil {
+		batcher.Rollback()
+		return err
+	}
+	return batcher.Commit()
+}
+
+func (p *importPlan) run(m tasks.Manager) error {
+	for _, task := range p.add {
+		opts := []tasks.TaskOption{
+			tasks.WithDescription(task.Description),
+			tasks.WithNotes(task.Notes...),
+			tasks.WithTags(task.Tags...),
+			tasks.WithUID(task.UID),
+			tasks.WithRecurrence(task.Recurrence),
+			tasks.WithCreatedAt(task.CreatedAt),
+		}
+		if task.Status == tasks.Completed {
+			opts = append(opts, tasks.WithCompletion(task.CompletedAt))
+		}
+		due := task.DueDate
+		if _, err := m.AddTask(task.Title, task.Priority, func(time.Time) tasks.DueDate { return due }, task.Category, opts...); err != nil {
+			return err
+		}
+	}
+	for _, u := range p.update {
+		if _, err := m.UpdateTask(u.id, u.patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestParseExportImportTableDriven(t *testing.T) {
+	pending := tasks.Pending
+	tests := []struct {
+		args   []string
+		want   Command
+		errMsg string
+	}{
+		{args: []string{"export"}, want: &ExportCommand{Format: "json"}},
+		{args: []string{"export", "-f", "out.ics", "-s", "pending"}, want: &ExportCommand{Format: "ics", File: "out.ics", StatusFilter: &pending}},
+		{args: []string{"export", "-format", "ICS", "-c", "work"}, want: &ExportCommand{Format: "ics", CategoryFilter: "work"}},
+		{args: []string{"export", "-f", "out.xyz"}, errMsg: "cannot tell the format of 'out.xyz'"},
+		{args: []string{"export", "-format", "xml"}, errMsg: "invalid format 'xml'"},
+		{args: []string{"export", "out.ics"}, errMsg: "use -f to write to a file"},
+		{args: []string{"import", "tasks.ics", "-n"}, want: &ImportCommand{Format: "ics", File: "tasks.ics", DryRun: true}},
+		{args: []string{"import", "-format", "json", "-"}, want: &ImportCommand{Format: "json", File: "-"}},
+		{args: []string{"import", "-"}, errMsg: "-format is required when reading stdin"},
+		{args: []string{"import"}, errMsg: "expected one file name"},
+		{args: []string{"import", "notes.doc"}, errMsg: "cannot tell the format of 'notes.doc'"},
+		{args: []string{"import", "todo.txt"}, want: &ImportCommand{Format: "todotxt", File: "todo.txt"}},
+		{args: []string{"export", "-f", "out.csv", "-columns", "title=Task, due"}, want: &ExportCommand{Format: "csv", File: "out.csv", Columns: []csvColumn{{"title", "Task"}, {"due", "due"}}}},
+		{args: []string{"export", "-columns", "title"}, errMsg: "-columns only applies to the csv format"},
+		{args: []string{"import", "in.csv", "-columns", "owner=Who"}, errMsg: "invalid column 'owner'"},
+		{args: []string{"import", "list.md"}, want: &ImportCommand{Format: "markdown", File: "list.md"}},
+		{args: []string{"import", "-format", "taskwarrior", "tw.json", "-n"}, want: &ImportCommand{Format: "taskwarrior", File: "tw.json", DryRun: true}},
+		{args: []string{"export", "-f", "tasks.org"}, want: &ExportCommand{Format: "org", File: "tasks.org"}},
+		{args: []string{"import", "tasks.org"}, errMsg: "the org format can only be exported"},
+		{args: []string{"export", "-format", "frontmatter"}, errMsg: "use -f <directory>"},
+		{args: []string{"export", "-format", "frontmatter", "-f", "vault"}, want: &ExportCommand{Format: "frontmatter", File: "vault"}},
+		{args: []string{"import", "-format", "frontmatter", "-"}, errMsg: "reads a directory, not stdin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			cmd, err := Parse(&tt.args)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(cmd, tt.want) {
+				t.Fatalf("expected %+v, got %+v (%v)", tt.want, cmd, err)
+			}
+		})
+	}
+}
+
+func newFileManager(t *testing.T, name string) tasks.Manager {
+	t.Helper()
+	m, err := tasks.NewManagerWithFile(filepath.Join(t.TempDir(), name))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	return m
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	src := newFileManager(t, "src.json")
+	due := func(time.Time) tasks.DueDate { return tasks.DueDate(testTime) }
+	src.AddTask("Call dentist", tasks.High, due, "health", tasks.WithTags("phone"), tasks.WithNotes("Morning only"))
+	src.AddTask("Buy milk", tasks.Low, due, "")
+	src.CompleteTask(2)
+
+	// formats that keep every field and a UID
+	for _, format := range []string{"json", "ics", "taskwarrior"} {
+		t.Run(format, func(t *testing.T) {
+			file := filepath.Join(dir, "tasks."+format)
+			if code, stdout, stderr := runWith(src, "export", "-format", format, "-f", file); code != ExitOK || stdout != "Exported 2 task(s) to "+file {
+				t.Fatalf("unexpected export result: %d %q %q", code, stdout, stderr)
+			}
+
+			dst := newFileManager(t, "dst.json")
+			code, stdout, stderr := runWith(dst, "import", "-format", format, file, "--dry-run")
+			if code != ExitOK || stdout != "add: Call dentist\nadd: Buy milk\nDry run, nothing saved: 2 added, 0 updated, 0 unchanged, 0 skipped" {
+				t.Fatalf("unexpected dry run result: %d %q %q", code, stdout, stderr)
+			}
+			if got, _ := dst.ListTasks(nil, nil, "", false); len(got) != 0 {
+				t.Fatalf("expected a dry run to add nothing, got %v", got)
+			}
+
+			if code, stdout, stderr := runWith(dst, "import", "-format", format, file); code != ExitOK || !strings.HasSuffix(stdout, ": 2 added, 0 updated, 0 unchanged, 0 skipped") {
+				t.Fatalf("unexpected import result: %d %q %q", code, stdout, stderr)
+			}
+			got, _ := dst.ListTasks(nil, nil, "", false)
+			want, _ := src.ListTasks(nil, nil, "", false)
+			for i := range want {
+				if got[i].Title != want[i].Title || got[i].Priority != want[i].Priority || got[i].Category != want[i].Category ||
+					got[i].Status != want[i].Status || !reflect.DeepEqual(got[i].Tags, want[i].Tags) || !reflect.DeepEqual(got[i].Notes, want[i].Notes) ||
+					got[i].CompletedAt.IsZero() != want[i].CompletedAt.IsZero() {
+					t.Fatalf("expected %+v, got %+v", want[i], got[i])
+				}
+			}
+
+			// importing again matches by UID
+			contents, _ := os.ReadFile(file)
+			os.WriteFile(file, []byte(strings.Replace(string(contents), "Buy milk", "Buy oat milk", 1)), 0644)
+			if code, stdout, _ := runWith(dst, "import", "-format", format, file); code != ExitOK || !strings.HasSuffix(stdout, ": 0 added, 1 updated, 1 unchanged, 0 skipped") {
+				t.Fatalf("expected the second import to update one task, got %q", stdout)
+			}
+			if task, err := dst.GetTask(2); err != nil || task.Title != "Buy oat milk" {
+				t.Fatalf("expected task 2 to be renamed, got %v (%v)", task, err)
+			}
+		})
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`[{"title": "Fine"}, {"title": " "}]`), 0644)
+	broken := filepath.Join(dir, "broken.json")
+	os.WriteFile(broken, []byte(`[{"title": `), 0644)
+
+	m := newFileManager(t, "tasks.json")
+	if code, stdout, _ := runWith(m, "import", bad); code != ExitOK || stdout != "task 2 has no title\nImported "+bad+": 1 added, 0 updated, 0 unchanged, 1 skipped" {
+		t.Fatalf("unexpected result: %d %q", code, stdout)
+	}
+	if code, _, stderr := runWith(m, "import", broken); code != ExitUsage || !strings.Contains(stderr, "import error: "+broken) {
+		t.Fatalf("expected a usage error for a broken file, got %d %q", code, stderr)
+	}
+	if code, _, stderr := runWith(m, "import", filepath.Join(dir, "missing.ics")); code != ExitFailure || !strings.Contains(stderr, "no such file") {
+		t.Fatalf("expected a failure for a missing file, got %d %q", code, stderr)
+	}
+}
+
+func TestImportIgnoresConfigDefaults(t *testing.T) {
+	useConfigFile(t, `{"priority": "high", "due": "tomorrow", "category": "work"}`)
+	dir := t.TempDir()
+	m := newFileManager(t, "tasks.json")
+	due := func(time.Time) tasks.DueDate { return tasks.DueDate(testTime) }
+	m.AddTask("Buy milk", tasks.Low, due, "")
+
+	vault := filepath.Join(dir, "vault")
+	if code, _, stderr := runWith(m, "export", "-format", "frontmatter", "-f", vault); code != ExitOK {
+		t.Fatalf("unexpected export failure: %v", stderr)
+	}
+	if code, stdout, _ := runWith(m, "import", "-format", "frontmatter", vault); code != ExitOK || !strings.HasSuffix(stdout, ": 0 added, 0 updated, 1 unchanged, 0 skipped") {
+		t.Fatalf("expected an unchanged re-import, got %d %q", code, stdout)
+	}
+
+	// a record without priority, due date or category gets the built-in defaults
+	file := filepath.Join(dir, "tasks.csv")
+	os.WriteFile(file, []byte("title\nWater plants\n"), 0644)
+	if code, _, stderr := runWith(m, "import", file); code != ExitOK {
+		t.Fatalf("unexpected import failure: %v", stderr)
+	}
+	task, _ := m.GetTask(2)
+	if task.Priority != tasks.Medium || task.Category != "" || time.Time(task.DueDate).After(m.Now()) {
+		t.Fatalf("expected built-in defaults, got %+v", task)
+	}
+}
+
+func TestImportInsideAtomicBatch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "import.json")
+	os.WriteFile(file, []byte(`[{"title": "Water plants"}]`), 0644)
+	path := filepath.Join(dir, "tasks.json")
+	m, err := tasks.NewManagerWithFile(path)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	script := "add 'Call dentist'\nimport " + file + "\nadd 'Buy milk'\ncomplete 999\n"
+	_, err = (&BatchCommand{Atomic: true}).run(m, strings.NewReader(script), &strings.Builder{}, &strings.Builder{})
+	if err == nil || !strings.Contains(err.Error(), "no changes were saved") {
+		t.Fatalf("expected the batch to fail and save nothing, got %v", err)
+	}
+	reloaded, err := tasks.NewManagerWithFile(path)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got, _ := reloaded.ListTasks(nil, nil, "", false); len(got) != 0 {
+		t.Fatalf("expected the import to be rolled back with the batch, got %v", got)
+	}
+}
+
+func TestExportReportsDroppedFields(t *testing.T) {
+	m := newMockManager(testTime)
+	m.listNextOk = []tasks.Task{{Id: 1, Title: "Call dentist", Priority: tasks.High, DueDate: tasks.DueDate(testTime), Description: "Ask about x-rays"}}
+
+	stderr := &strings.Builder{}
+	got, err := (&ExportCommand{Format: "todotxt"}).run(&m, stderr)
+	if err != nil || got != "(A) Call dentist due:2024-04-10" || stderr.String() != "description dropped from 1 task(s)\n" {
+		t.Fatalf("unexpected export: %q %q (%v)", got, stderr.String(), err)
+	}
+
+	file := filepath.Join(t.TempDir(), "todo.txt")
+	got, err = (&ExportCommand{Format: "todotxt", File: file}).run(&m, stderr)
+	if err != nil || got != "Exported 1 task(s) to "+file+"\ndescription dropped from 1 task(s)" {
+		t.Fatalf("unexpected export summary: %q (%v)", got, err)
+	}
+}
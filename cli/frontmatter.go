@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+var (
+	frontmatterSlugRegex = regexp.MustCompile(`[^\pL\pN]+`)
+	frontmatterFileRegex = regexp.MustCompile(`^(\d+)-.*\.md$`)
+)
+
+// frontmatterFileName names a task's file by id and title, e.g.
+// "3-call-dentist.md", so notes apps list them readably.
+func frontmatterFileName(t *tasks.Task) string {
+	slug := strings.Trim(frontmatterSlugRegex.ReplaceAllString(strings.ToLower(t.Title), "-"), "-")
+	if runes := []rune(slug); len(runes) > 40 {
+		slug = strings.TrimRight(string(runes[:40]), "-")
+	}
+	return fmt.Sprintf("%d-%s.md", t.Id, slug)
+}
+
+// yamlString quotes s as JSON, which YAML reads as a double-quoted string.
+func yamlString(s string) string {
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSpace(buf.String())
+}
+
+func yamlList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, yamlString(item))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// renderFrontmatter writes a task as YAML frontmatter with the description
+// as the note body.
+func renderFrontmatter(t *tasks.Task) []byte {
+	sb := strings.Builder{}
+	sb.WriteString("---\n")
+	fmt.Fprintf(&sb, "id: %d\n", t.Id)
+	fmt.Fprintf(&sb, "title: %s\n", yamlString(t.Title))
+	fmt.Fprintf(&sb, "status: %s\n", t.Status.String())
+	fmt.Fprintf(&sb, "priority: %s\n", strings.ToLower(t.Priority.String()))
+	fmt.Fprintf(&sb, "due: %s\n", time.Time(t.DueDate).Format("2006-01-02"))
+	if t.Category != "" {
+		fmt.Fprintf(&sb, "category: %s\n", yamlString(t.Category))
+	}
+	if len(t.Tags) > 0 {
+		fmt.Fprintf(&sb, "tags: %s\n", yamlList(t.Tags))
+	}
+	if len(t.Notes) > 0 {
+		fmt.Fprintf(&sb, "notes: %s\n", yamlList(t.Notes))
+	}
+	if t.Recurrence != "" {
+		fmt.Fprintf(&sb, "recurrence: %s\n", yamlString(t.Recurrence))
+	}
+	if !t.CreatedAt.IsZero() {
+		fmt.Fprintf(&sb, "created: %s\n", t.CreatedAt.Format(time.RFC3339))
+	}
+	if !t.CompletedAt.IsZero() {
+		fmt.Fprintf(&sb, "completed: %s\n", t.CompletedAt.Format(time.RFC3339))
+	}
+	sb.WriteString("---\n")
+	if t.Description != "" {
+		sb.WriteString("\n" + strings.TrimSpace(t.Description) + "\n")
+	}
+	return []byte(sb.String())
+}
+
+// exportFrontmatter writes one Markdown file per task into dir. Files left
+// from an earlier export under a task's old title are reported, not removed.
+func exportFrontmatter(dir string, t []tasks.Task, _ exchangeOptions) ([]tasks.Issue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var issues []tasks.Issue
+	for _, task := range t {
+		name := frontmatterFileName(&task)
+		for _, entry := range entries {
+			if m := frontmatterFileRegex.FindStringSubmatch(entry.Name()); m != nil && m[1] == strconv.Itoa(task.Id) && entry.Name() != name {
+				issues = append(issues, tasks.Issue{Message: fmt.Sprintf("task %d: %s is left over from an earlier export", task.Id, entry.Name())})
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), renderFrontmatter(&task), 0644); err != nil {
+			return issues, err
+		}
+	}
+	return issues, nil
+}
+
+// importFrontmatter reads every Markdown file in dir. A task whose id
+// matches an existing task updates it; unknown frontmatter fields, such as
+// ones a notes app adds, are ignored.
+func importFrontmatter(dir string, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var result []tasks.Task
+	var issues []tasks.Issue
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+		task, err := parseFrontmatter(content, opts.now)
+		if err != nil {
+			issues = append(issues, tasks.Issue{Message: entry.Name() + ": " + err.Error(), Skipped: true})
+			continue
+		}
+		result = append(result, task)
+	}
+	return result, issues, nil
+}
+
+// parseFrontmatter reads the subset of YAML that frontmatter uses: plain,
+// quoted and flow-list scalars, and block lists of "- item" lines.
+func parseFrontmatter(content []byte, now time.Time) (tasks.Task, error) {
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	if !sc.Scan() || strings.TrimSpace(sc.Text()) != "---" {
+		return tasks.Task{}, fmt.Errorf("no frontmatter")
+	}
+	values := map[string][]string{}
+	key, closed := "", false
+	for n := 2; sc.Scan(); n++ {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "---" {
+			closed = true
+			break
+		}
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok && key != "" {
+			values[key] = append(values[key], yamlScalar(item))
+			continue
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		k, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return tasks.Task{}, fmt.Errorf("line %d: expected 'key: value'", n)
+		}
+		key = strings.ToLower(strings.TrimSpace(k))
+		values[key] = yamlValues(strings.TrimSpace(value))
+	}
+	if !closed {
+		return tasks.Task{}, fmt.Errorf("frontmatter is not closed with '---'")
+	}
+	var body []string
+	for sc.Scan() {
+		body = append(body, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		return tasks.Task{}, err
+	}
+	first := func(key string) string {
+		if len(values[key]) == 0 {
+			return ""
+		}
+		return values[key][0]
+	}
+
+	args := []string{"-no-parse", "-description", strings.TrimSpace(strings.Join(body, "\n"))}
+	for _, flag := range []string{"priority", "due", "category"} {
+		if value := first(flag); value != "" {
+			args = append(args, "-"+flag, value)
+		}
+	}
+	if tags := values["tags"]; len(tags) > 0 {
+		args = append(args, "-tags", strings.Join(tags, ","))
+	}
+	for _, note := range values["notes"] {
+		args = append(args, "-note", note)
+	}
+	task, err := addCommandTask(append(args, "--", first("title")), now)
+	if err != nil {
+		return task, err
+	}
+	if id := first("id"); id != "" {
+		if task.Id, err = strconv.Atoi(id); err != nil {
+			return task, fmt.Errorf("invalid id '%s'", id)
+		}
+	}
+	if status := first("status"); status != "" {
+		s, err := parseStatusFilter(status)
+		if err != nil {
+			return task, err
+		}
+		task.Status = *s
+	}
+	task.Recurrence = first("recurrence")
+	for _, field := range []struct {
+		key string
+		at  *time.Time
+	}{{"created", &task.CreatedAt}, {"completed", &task.CompletedAt}} {
+		if value := first(field.key); value != "" {
+			if *field.at, err = parseCSVTime(value); err != nil {
+				return task, err
+			}
+		}
+	}
+	if task.Status != tasks.Completed {
+		task.CompletedAt = time.Time{}
+	}
+	return task, nil
+}
+
+// yamlValues reads a scalar or a flow list such as [a, "b c"]. An empty
+// value starts a block list.
+func yamlValues(s string) []string {
+	if s == "" {
+		return nil
+	}
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return []string{yamlScalar(s)}
+	}
+	var items []string
+	if err := json.Unmarshal([]byte(s), &items); err == nil {
+		return items
+	}
+	for _, item := range strings.Split(s[1:len(s)-1], ",") {
+		if item = yamlScalar(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, `"`):
+		var v string
+		if err := json.Unmarshal([]byte(s), &v); err == nil {
+			return v
+		}
+	case len(s) >= 2 && strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'"):
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
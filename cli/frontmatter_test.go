@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestFrontmatterRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "vault")
+	m := newFileManager(t, "tasks.json")
+	due := func(time.Time) tasks.DueDate { return tasks.DueDate(testTime) }
+	m.AddTask(`Call "Dr. Who": re/billing`, tasks.High, due, "health", tasks.WithTags("phone", "q2"),
+		tasks.WithNotes("Morning only"), tasks.WithDescription("Ask about\nthe bill"))
+	m.AddTask("Buy milk", tasks.Low, due, "")
+	m.CompleteTask(2)
+
+	if code, stdout, stderr := runWith(m, "export", "-format", "frontmatter", "-f", dir); code != ExitOK || stdout != "Exported 2 task(s) to "+dir {
+		t.Fatalf("unexpected export result: %d %q %q", code, stdout, stderr)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "1-call-dr-who-re-billing.md"))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	for _, line := range []string{`title: "Call \"Dr. Who\": re/billing"`, "priority: high", "due: 2024-04-10", `tags: ["phone", "q2"]`, "---\n\nAsk about\nthe bill\n"} {
+		if !strings.Contains(string(content), line) {
+			t.Errorf("expected file to contain %q, got\n%s", line, content)
+		}
+	}
+
+	if code, stdout, _ := runWith(m, "import", "-format", "frontmatter", dir); code != ExitOK || !strings.HasSuffix(stdout, ": 0 added, 0 updated, 2 unchanged, 0 skipped") {
+		t.Fatalf("expected an unchanged re-import, got %d %q", code, stdout)
+	}
+
+	// edits made in a notes app update the task with the file's id
+	edited := "---\nid: 2\ntitle: Buy oat milk\nstatus: pending\npriority: medium\ndue: 2024-04-12\ntags:\n  - shop\naliases: [milk]\n---\n"
+	os.WriteFile(filepath.Join(dir, "2-buy-milk.md"), []byte(edited), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# My vault\n"), 0644)
+	code, stdout, _ := runWith(m, "import", "-format", "frontmatter", dir)
+	if code != ExitOK || stdout != "README.md: no frontmatter\nImported "+dir+": 0 added, 1 updated, 1 unchanged, 1 skipped" {
+		t.Fatalf("unexpected import result: %d %q", code, stdout)
+	}
+	task, _ := m.GetTask(2)
+	want := tasks.DueDate(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC))
+	if task.Title != "Buy oat milk" || task.Status != tasks.Pending || task.Priority != tasks.Medium ||
+		!reflect.DeepEqual(task.Tags, []string{"shop"}) || !time.Time(task.DueDate).Equal(time.Time(want)) {
+		t.Fatalf("expected task 2 to be updated, got %+v", task)
+	}
+}
+
+func TestParseFrontmatterErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		errMsg  string
+	}{
+		{content: "---\ntitle: x\n", errMsg: "not closed"},
+		{content: "---\ntitle: x\nnot yaml\n---\n", errMsg: "line 3: expected 'key: value'"},
+		{content: "---\ntitle: ''\n---\n", errMsg: "title cannot be empty"},
+		{content: "---\ntitle: x\nid: three\n---\n", errMsg: "invalid id 'three'"},
+		{content: "---\ntitle: x\npriority: urgent\n---\n", errMsg: "invalid priority"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.errMsg, func(t *testing.T) {
+			if _, err := parseFrontmatter([]byte(tt.content), testTime); err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+const programName = "todo"
+
+type commandSpec struct {
+	name     string
+	usage    []string
+	summary  string
+	flagSets func() []*flag.FlagSet
+	examples []string
+}
+
+var commandSpecs = []commandSpec{
+	{
+		name:     "add",
+		usage:    []string{"add <title> [flags]"},
+		summary:  "Add a new task",
+		flagSets: func() []*flag.FlagSet { fs, _ := newAddFlagSet(userConfig); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`add "Call dentist"`,
+			`add "Buy groceries" -priority high -due tomorrow`,
+			`add "File taxes" -priority high -due 2025-04-15 -category finance`,
+			`add "Review code" -due +7d -tags work,code`,
+			`add -p high -d tomorrow -- "-v flag is broken"`,
+			`add "Ship release notes !high @work #docs due:fri"`,
+			`add -no-parse "Reply to @bob about #docs"`,
+		},
+	},
+	{
+		name:     "list",
+		usage:    []string{"list [flags]"},
+		summary:  "List tasks, optionally filtered",
+		flagSets: func() []*flag.FlagSet { fs, _ := newListFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`list`,
+			`list -status pending -priority high`,
+			`list -overdue`,
+			`list -columns id,title,due -relative`,
+			`list -status pending -format json`,
+		},
+	},
+	{
+		name:     "search",
+		usage:    []string{"search <query> [flags]"},
+		summary:  "Search titles, descriptions, notes, tags and categories",
+		flagSets: func() []*flag.FlagSet { fs, _ := newSearchFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`search "doctor"`,
+			`search "title:deploy" -status pending`,
+			`search "^(call|email) " -regex`,
+			`search -s pending -l 5 dentist`,
+		},
+	},
+	{
+		name:     "complete",
+		usage:    []string{"complete <id>"},
+		summary:  "Mark a task as completed",
+		flagSets: func() []*flag.FlagSet { return []*flag.FlagSet{newFlagSet("complete")} },
+		examples: []string{`complete 1`},
+	},
+	{
+		name:     "delete",
+		usage:    []string{"delete <id>"},
+		summary:  "Delete a task",
+		flagSets: func() []*flag.FlagSet { return []*flag.FlagSet{newFlagSet("delete")} },
+		examples: []string{`delete 5`},
+	},
+	{
+		name:     "edit",
+		usage:    []string{"edit <id> [flags]"},
+		summary:  "Change a task's title, priority, due date, category, tags, notes or status",
+		flagSets: func() []*flag.FlagSet { fs, _ := newEditFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`edit 3 -p high -d +2d`,
+			`edit 3 --title "Call the dentist" --note "Ask about x-rays"`,
+			`edit 3 --tags "" --status pending`,
+		},
+	},
+	{
+		name:     "agenda",
+		usage:    []string{"agenda [flags]"},
+		summary:  "Show pending tasks grouped by when they are due",
+		flagSets: func() []*flag.FlagSet { fs, _ := newAgendaFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`agenda`,
+			`agenda -c work -relative`,
+		},
+	},
+	{
+		name:     "calendar",
+		usage:    []string{"calendar [month] [flags]"},
+		summary:  "Show a month of tasks as a calendar grid",
+		flagSets: func() []*flag.FlagSet { fs, _ := newCalendarFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`calendar`,
+			`calendar next --counts`,
+			`calendar 2025-04 --all`,
+		},
+	},
+	{
+		name:     "stats",
+		usage:    []string{"stats [flags]"},
+		summary:  "Show task counts, completion rates and weekly throughput",
+		flagSets: func() []*flag.FlagSet { fs, _ := newStatsFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`stats`,
+			`stats -c work --weeks 12`,
+			`stats --format json`,
+		},
+	},
+	{
+		name:     "export",
+		usage:    []string{"export [flags]"},
+		summary:  "Write tasks to stdout or a file in another format",
+		flagSets: func() []*flag.FlagSet { fs, _ := newExportFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`export > backup.json`,
+			`export -format ics -f tasks.ics`,
+			`export -format ics -s pending -c work`,
+			`export -f tasks.csv -columns "title=Task,due=Deadline"`,
+			`export -format frontmatter -f ~/vault/tasks`,
+		},
+	},
+	{
+		name:     "import",
+		usage:    []string{"import <file> [flags]"},
+		summary:  "Add or update tasks from a file",
+		flagSets: func() []*flag.FlagSet { fs, _ := newImportFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`import tasks.ics`,
+			`import backup.json --dry-run`,
+			`import -format ics - < calendar.ics`,
+			`import sheet.csv -columns "title=Task,due=Deadline"`,
+			`import -format taskwarrior tw.json --dry-run`,
+			`import -format frontmatter ~/vault/tasks`,
+		},
+	},
+	{
+		name:     "serve",
+		usage:    []string{"serve [flags]"},
+		summary:  "Serve the task list as a JSON REST API",
+		flagSets: func() []*flag.FlagSet { fs, _ := newServeFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`serve`,
+			`serve -addr 127.0.0.1:9000`,
+		},
+	},
+	{
+		name:     "rpc",
+		usage:    []string{"rpc"},
+		summary:  "Answer JSON-RPC 2.0 requests on stdin, for editor integrations",
+		flagSets: func() []*flag.FlagSet { return []*flag.FlagSet{newFlagSet("rpc")} },
+		examples: []string{
+			`rpc`,
+		},
+	},
+	{
+		name: "view",
+		usage: []string{
+			"view save <name> <query> [flags]",
+			"view <name> [flags]",
+			"view list",
+			"view delete <name>",
+		},
+		summary: "Save and run named queries",
+		flagSets: func() []*flag.FlagSet {
+			saveFs, _ := newViewSaveFlagSet()
+			runFs, _ := newViewRunFlagSet()
+			return []*flag.FlagSet{saveFs, runFs}
+		},
+		examples: []string{
+			`view save today "status:pending and due<=today" -sort priority`,
+			`view today`,
+			`view list`,
+		},
+	},
+	{
+		name:     "ui",
+		usage:    []string{"ui [flags]"},
+		summary:  "Browse and edit tasks in a full-screen terminal interface",
+		flagSets: func() []*flag.FlagSet { fs, _ := newUIFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`ui`,
+			`ui --filter "-status pending" --sort due`,
+		},
+	},
+	{
+		name:     "shell",
+		usage:    []string{"shell"},
+		summary:  "Run commands at a prompt, saving changes on 'commit'",
+		flagSets: func() []*flag.FlagSet { return []*flag.FlagSet{newFlagSet("shell")} },
+		examples: []string{`shell`},
+	},
+	{
+		name:     "batch",
+		usage:    []string{"batch [flags] < commands.txt"},
+		summary:  "Run one command per line from a file or stdin",
+		flagSets: func() []*flag.FlagSet { fs, _ := newBatchFlagSet(); return []*flag.FlagSet{fs} },
+		examples: []string{
+			`batch < commands.txt`,
+			`batch -f commands.txt --continue`,
+			`batch -f commands.txt --atomic`,
+		},
+	},
+	{
+		name: "config",
+		usage: []string{
+			"config list",
+			"config get <key>",
+			"config set <key> <value>",
+		},
+		summary:  "Show and change settings and aliases in the config file",
+		flagSets: func() []*flag.FlagSet { return []*flag.FlagSet{newFlagSet("config")} },
+		examples: []string{
+			`config set priority high`,
+			`config set alias.t "list -status pending -sort due"`,
+			`config set date-format "Jan 2"`,
+			`config set category ""`,
+			`config get db`,
+			`config list`,
+		},
+	},
+	{
+		name:     "completion",
+		usage:    []string{"completion bash|zsh|fish"},
+		summary:  "Generate a shell completion script",
+		flagSets: func() []*flag.FlagSet { return []*flag.FlagSet{newFlagSet("completion")} },
+		examples: []string{
+			`completion bash > /etc/bash_completion.d/todo`,
+			`completion zsh > "${fpath[1]}/_todo"`,
+			`completion fish > ~/.config/fish/completions/todo.fish`,
+		},
+	},
+	{
+		name:     "help",
+		usage:    []string{"help [command]"},
+		summary:  "Show help for a command",
+		flagSets: func() []*flag.FlagSet { return []*flag.FlagSet{newFlagSet("help")} },
+		examples: []string{`help`, `help add`},
+	},
+}
+
+func findCommandSpec(name string) (*commandSpec, bool) {
+	for i := range commandSpecs {
+		if commandSpecs[i].name == name {
+			return &commandSpecs[i], true
+		}
+	}
+	return nil, false
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commandSpecs))
+	for _, spec := range commandSpecs {
+		names = append(names, spec.name)
+	}
+	return names
+}
+
+type HelpCommand struct {
+	Topic string
+}
+
+func (h *HelpCommand) Execute(m tasks.Manager) (string, error) {
+	if h.Topic == "" {
+		return Usage(), nil
+	}
+	return CommandUsage(h.Topic)
+}
+
+func Usage() string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "Usage: %s [--output text|json] [--remote URL] <command> [arguments] [flags]\n\nCommands:\n", programName)
+	for _, spec := range commandSpecs {
+		fmt.Fprintf(&sb, "  %-10s %s\n", spec.name, spec.summary)
+	}
+	sb.WriteString("\nGlobal flags:\n")
+	sb.WriteString("  --output string\n    \tError output format: text (default), json\n")
+	sb.WriteString("  --remote string\n    \tURL of a task server started with 'serve', used instead of the task file\n")
+	sb.WriteString("\nExit codes:\n")
+	for _, code := range []int{ExitOK, ExitFailure, ExitUsage, ExitNotFound, ExitConflict, ExitStorage, ExitUnavailable, ExitRejected} {
+		fmt.Fprintf(&sb, "  %d  %s\n", code, exitCodeDescriptions[code])
+	}
+	fmt.Fprintf(&sb, "\nRun '%s help <command>' or '%s <command> -h' for details on a command.", programName, programName)
+	return sb.String()
+}
+
+func CommandUsage(name string) (string, error) {
+	spec, ok := findCommandSpec(name)
+	if !ok {
+		return "", fmt.Errorf("invalid help topic '%s': must be one of %s", name, quoteList(commandNames()))
+	}
+	sb := strings.Builder{}
+	for i, usage := range spec.usage {
+		prefix := "Usage:"
+		if i > 0 {
+			prefix = "      "
+		}
+		fmt.Fprintf(&sb, "%s %s %s\n", prefix, programName, usage)
+	}
+	fmt.Fprintf(&sb, "\n%s.\n", spec.summary)
+	for _, fs := range spec.flagSets() {
+		defaults := flagDefaults(fs)
+		if defaults == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "\nFlags for '%s':\n%s", fs.Name(), defaults)
+	}
+	sb.WriteString("\nExamples:\n")
+	for _, example := range spec.examples {
+		fmt.Fprintf(&sb, "  %s %s\n", programName, example)
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+func flagDefaults(fs *flag.FlagSet) string {
+	byValue := map[flag.Value][]*flag.Flag{}
+	fs.VisitAll(func(f *flag.Flag) {
+		byValue[f.Value] = append(byValue[f.Value], f)
+	})
+	groups := slices.Collect(maps.Values(byValue))
+	for _, group := range groups {
+		slices.SortFunc(group, func(a, b *flag.Flag) int { return len(a.Name) - len(b.Name) })
+	}
+	slices.SortFunc(groups, func(a, b []*flag.Flag) int {
+		return strings.Compare(a[len(a)-1].Name, b[len(b)-1].Name)
+	})
+
+	sb := strings.Builder{}
+	for _, group := range groups {
+		f := group[len(group)-1]
+		names := make([]string, 0, len(group))
+		for _, alias := range group {
+			if len(alias.Name) == 1 {
+				names = append(names, "-"+alias.Name)
+			} else {
+				names = append(names, "--"+alias.Name)
+			}
+		}
+		indent := "  "
+		if len(group[0].Name) > 1 {
+			indent = "      "
+		}
+		typeName, usage := flag.UnquoteUsage(f)
+		fmt.Fprintf(&sb, "%s%s", indent, strings.Join(names, ", "))
+		if typeName != "" {
+			fmt.Fprintf(&sb, " %s", typeName)
+		}
+		fmt.Fprintf(&sb, "\n    \t%s", usage)
+		if f.DefValue != "" && f.DefValue != "0" && f.DefValue != "false" {
+			if typeName == "string" {
+				fmt.Fprintf(&sb, " (default %q)", f.DefValue)
+			} else {
+				fmt.Fprintf(&sb, " (default %v)", f.DefValue)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func flagError(name string, err error) (Command, error) {
+	if errors.Is(err, flag.ErrHelp) {
+		return &HelpCommand{Topic: name}, nil
+	}
+	return nil, fmt.Errorf("%s error: %v (run '%s help %s' for usage)", name, err, programName, name)
+}
+
+func quoteList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, "'"+item+"'")
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + ", or " + quoted[len(quoted)-1]
+}
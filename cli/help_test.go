@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHelpTableDriven(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		topic string
+	}{
+		{name: "help", args: []string{"help"}, topic: ""},
+		{name: "help flag", args: []string{"-h"}, topic: ""},
+		{name: "help topic", args: []string{"help", "add"}, topic: "add"},
+		{name: "add -h", args: []string{"add", "-h"}, topic: "add"},
+		{name: "add title --help", args: []string{"add", "Call dentist", "-priority", "high", "--help"}, topic: "add"},
+		{name: "list -help", args: []string{"list", "-help"}, topic: "list"},
+		{name: "search -h", args: []string{"search", "-h"}, topic: "search"},
+		{name: "complete -h", args: []string{"complete", "-h"}, topic: "complete"},
+		{name: "delete -h", args: []string{"delete", "-h"}, topic: "delete"},
+		{name: "view run -h", args: []string{"view", "today", "-h"}, topic: "view"},
+		{name: "view save -h", args: []string{"view", "save", "today", "status:pending", "-h"}, topic: "view"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := Parse(&tt.args)
+
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			helpCmd, ok := cmd.(*HelpCommand)
+			if !ok {
+				t.Fatalf("expected help command, got: %v", cmd)
+			}
+			if helpCmd.Topic != tt.topic {
+				t.Fatalf("expected topic '%v', got '%v'", tt.topic, helpCmd.Topic)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name   string
+		args   []string
+		errMsg string
+	}{
+		{name: "unknown topic", args: []string{"help", "frobnicate"}, errMsg: "unknown command 'frobnicate'"},
+		{name: "too many topics", args: []string{"help", "add", "list"}, errMsg: "at most one"},
+		{name: "unknown flag", args: []string{"list", "-frobnicate"}, errMsg: "list error: flag provided but not defined: -frobnicate (run 'todo help list' for usage)"},
+		{name: "bad flag value", args: []string{"search", "dentist", "-limit", "many"}, errMsg: "search error: invalid value"},
+	}
+
+	for _, it := range invalid {
+		t.Run(it.name, func(t *testing.T) {
+			cmd, err := Parse(&it.args)
+
+			if err == nil {
+				t.Fatalf("expected err, got: %v", cmd)
+			}
+			if !strings.Contains(err.Error(), it.errMsg) {
+				t.Fatalf("unexpected err text: wanted='%v', got=%v", it.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestHelpExecuteTableDriven(t *testing.T) {
+	m := newMockManager(testTime)
+	tests := []struct {
+		topic string
+		want  []string
+	}{
+		{
+			topic: "",
+			want:  []string{"Usage: todo [--output text|json] [--remote URL] <command>", "3  task or view not found", "add ", "list ", "search ", "complete ", "delete ", "view ", "todo help <command>"},
+		},
+		{
+			topic: "add",
+			want:  []string{"Usage: todo add <title> [flags]", "-priority string", "-note value", `todo add "Buy groceries" -priority high -due tomorrow`},
+		},
+		{
+			topic: "list",
+			want:  []string{"Usage: todo list [flags]", "-overdue", "-columns string", "todo list -status pending -priority high"},
+		},
+		{
+			topic: "complete",
+			want:  []string{"Usage: todo complete <id>", "todo complete 1"},
+		},
+		{
+			topic: "view",
+			want:  []string{"todo view save <name> <query> [flags]", "Flags for 'view save':", "-sort string", "Flags for 'view <name>':", "todo view today"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			got, err := (&HelpCommand{Topic: tt.topic}).Execute(&m)
+
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Fatalf("expected help to contain '%v', got:\n%v", want, got)
+				}
+			}
+		})
+	}
+
+	if _, err := (&HelpCommand{Topic: "frobnicate"}).Execute(&m); err == nil {
+		t.Fatalf("expected err for unknown topic, got none")
+	}
+}
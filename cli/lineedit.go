@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"bufio"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type key string
+
+const (
+	keyUp        key = "up"
+	keyDown      key = "down"
+	keyLeft      key = "left"
+	keyRight     key = "right"
+	keyHome      key = "home"
+	keyEnd       key = "end"
+	keyPageUp    key = "pgup"
+	keyPageDown  key = "pgdn"
+	keyEnter     key = "enter"
+	keyEsc       key = "esc"
+	keyTab       key = "tab"
+	keyBackspace key = "backspace"
+	keyDelete    key = "delete"
+	keyCtrlA     key = "ctrl-a"
+	keyCtrlC     key = "ctrl-c"
+	keyCtrlD     key = "ctrl-d"
+	keyCtrlE     key = "ctrl-e"
+	keyCtrlK     key = "ctrl-k"
+	keyCtrlL     key = "ctrl-l"
+	keyCtrlU     key = "ctrl-u"
+	keyCtrlW     key = "ctrl-w"
+	keyUnknown   key = "unknown"
+)
+
+var controlKeys = map[rune]key{
+	'\r':   keyEnter,
+	'\n':   keyEnter,
+	'\t':   keyTab,
+	0x7f:   keyBackspace,
+	0x08:   keyBackspace,
+	0x01:   keyCtrlA,
+	0x03:   keyCtrlC,
+	0x04:   keyCtrlD,
+	0x05:   keyCtrlE,
+	0x0b:   keyCtrlK,
+	0x0c:   keyCtrlL,
+	0x15:   keyCtrlU,
+	0x17:   keyCtrlW,
+	0x1b:   keyEsc,
+	0xfffd: keyUnknown,
+}
+
+var escapeKeys = map[string]key{
+	"A": keyUp, "B": keyDown, "C": keyRight, "D": keyLeft,
+	"H": keyHome, "F": keyEnd, "1~": keyHome, "7~": keyHome, "4~": keyEnd, "8~": keyEnd,
+	"3~": keyDelete, "5~": keyPageUp, "6~": keyPageDown,
+}
+
+// readKey reads one key press from a terminal in raw mode. Printable keys are
+// returned as themselves, everything else as one of the named keys.
+func readKey(r *bufio.Reader) (key, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	if c == 0x1b && r.Buffered() > 0 {
+		next, _ := r.ReadByte()
+		if next != '[' && next != 'O' {
+			return keyUnknown, nil
+		}
+		seq := strings.Builder{}
+		for {
+			b, err := r.ReadByte()
+			if err != nil {
+				return keyUnknown, nil
+			}
+			seq.WriteByte(b)
+			if b >= 0x40 && b <= 0x7e {
+				break
+			}
+		}
+		if k, ok := escapeKeys[seq.String()]; ok {
+			return k, nil
+		}
+		return keyUnknown, nil
+	}
+	if k, ok := controlKeys[c]; ok {
+		return k, nil
+	}
+	if !unicode.IsPrint(c) {
+		return keyUnknown, nil
+	}
+	return key(string(c)), nil
+}
+
+func (k key) printable() bool {
+	r, size := utf8.DecodeRuneInString(string(k))
+	return size == len(k) && unicode.IsPrint(r)
+}
+
+type lineEditor struct {
+	buf     []rune
+	pos     int
+	history []string
+	hpos    int
+	draft   string
+}
+
+func (e *lineEditor) String() string {
+	return string(e.buf)
+}
+
+func (e *lineEditor) set(s string) {
+	e.buf = []rune(s)
+	e.pos = len(e.buf)
+}
+
+// handle applies an editing key and reports whether the key was consumed.
+func (e *lineEditor) handle(k key) bool {
+	switch k {
+	case keyLeft:
+		e.pos = max(0, e.pos-1)
+	case keyRight:
+		e.pos = min(len(e.buf), e.pos+1)
+	case keyHome, keyCtrlA:
+		e.pos = 0
+	case keyEnd, keyCtrlE:
+		e.pos = len(e.buf)
+	case keyBackspace:
+		if e.pos > 0 {
+			e.buf = append(e.buf[:e.pos-1], e.buf[e.pos:]...)
+			e.pos--
+		}
+	case keyDelete:
+		if e.pos < len(e.buf) {
+			e.buf = append(e.buf[:e.pos], e.buf[e.pos+1:]...)
+		}
+	case keyCtrlU:
+		e.buf = e.buf[e.pos:]
+		e.pos = 0
+	case keyCtrlK:
+		e.buf = e.buf[:e.pos]
+	case keyCtrlW:
+		start := e.pos
+		for start > 0 && e.buf[start-1] == ' ' {
+			start--
+		}
+		for start > 0 && e.buf[start-1] != ' ' {
+			start--
+		}
+		e.buf = append(e.buf[:start], e.buf[e.pos:]...)
+		e.pos = start
+	case keyUp:
+		if e.hpos > 0 {
+			if e.hpos == len(e.history) {
+				e.draft = e.String()
+			}
+			e.hpos--
+			e.set(e.history[e.hpos])
+		}
+	case keyDown:
+		if e.hpos < len(e.history) {
+			e.hpos++
+			if e.hpos == len(e.history) {
+				e.set(e.draft)
+			} else {
+				e.set(e.history[e.hpos])
+			}
+		}
+	default:
+		if !k.printable() {
+			return false
+		}
+		r := []rune(string(k))
+		e.buf = append(e.buf[:e.pos], append(r, e.buf[e.pos:]...)...)
+		e.pos += len(r)
+	}
+	return true
+}
+
+// commit records the current line in the history and clears the editor.
+func (e *lineEditor) commit() string {
+	line := e.String()
+	if strings.TrimSpace(line) != "" && (len(e.history) == 0 || e.history[len(e.history)-1] != line) {
+		e.history = append(e.history, line)
+	}
+	e.hpos = len(e.history)
+	e.draft = ""
+	e.set("")
+	return line
+}
+
+// render returns the line with the cursor position shown in reverse video.
+func (e *lineEditor) render() string {
+	before := string(e.buf[:e.pos])
+	cursor, after := " ", ""
+	if e.pos < len(e.buf) {
+		cursor, after = string(e.buf[e.pos]), string(e.buf[e.pos+1:])
+	}
+	return before + "\x1b[7m" + cursor + "\x1b[0m" + after
+}
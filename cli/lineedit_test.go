@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bufio"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadKeyTableDriven(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []key
+	}{
+		{input: "a", want: []key{"a"}},
+		{input: "é", want: []key{"é"}},
+		{input: "\r\n\t", want: []key{keyEnter, keyEnter, keyTab}},
+		{input: "\x7f\x15\x17", want: []key{keyBackspace, keyCtrlU, keyCtrlW}},
+		{input: "\x1b[A\x1b[B\x1b[C\x1b[D", want: []key{keyUp, keyDown, keyRight, keyLeft}},
+		{input: "\x1bOH\x1b[4~", want: []key{keyHome, keyEnd}},
+		{input: "\x1b[5~\x1b[6~\x1b[3~", want: []key{keyPageUp, keyPageDown, keyDelete}},
+		{input: "\x1b", want: []key{keyEsc}},
+		{input: "\x1b[15~x", want: []key{keyUnknown, "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input))
+			var got []key
+			for {
+				k, err := readKey(r)
+				if err != nil {
+					break
+				}
+				got = append(got, k)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("wanted=%v, got=%v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLineEditor(t *testing.T) {
+	e := lineEditor{}
+	for _, k := range []key{"b", "u", "y", " ", "m", "l", "k", keyLeft, keyLeft, "i"} {
+		e.handle(k)
+	}
+	if e.String() != "buy milk" || e.pos != 6 {
+		t.Fatalf("unexpected line %q at %d", e.String(), e.pos)
+	}
+
+	e.handle(keyEnd)
+	e.handle(keyCtrlW)
+	if e.String() != "buy " {
+		t.Fatalf("expected ctrl-w to delete the last word, got %q", e.String())
+	}
+	e.handle(keyHome)
+	e.handle(keyDelete)
+	if e.String() != "uy " || e.pos != 0 {
+		t.Fatalf("unexpected line %q at %d", e.String(), e.pos)
+	}
+	if e.handle(keyTab) {
+		t.Fatalf("expected tab not to be consumed")
+	}
+
+	e.set("first")
+	e.commit()
+	e.set("second")
+	e.commit()
+	e.set("draft")
+	e.handle(keyUp)
+	e.handle(keyUp)
+	if e.String() != "first" {
+		t.Fatalf("expected oldest history entry, got %q", e.String())
+	}
+	e.handle(keyDown)
+	e.handle(keyDown)
+	if e.String() != "draft" {
+		t.Fatalf("expected draft to be restored, got %q", e.String())
+	}
+}
+
+func TestSplitCommandLineTableDriven(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   []string
+		errMsg string
+	}{
+		{line: "add Call dentist", want: []string{"add", "Call", "dentist"}},
+		{line: `  add   'Call  dentist' -p high `, want: []string{"add", "Call  dentist", "-p", "high"}},
+		{line: `add "say \"hi\"" it's\ fine`, errMsg: "unterminated ' quote"},
+		{line: `add "say \"hi\"" it\'s\ fine`, want: []string{"add", `say "hi"`, "it's fine"}},
+		{line: `search ''`, want: []string{"search", ""}},
+		{line: `add trailing\`, errMsg: "unterminated escape"},
+		{line: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := splitCommandLine(tt.line)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected err containing %q, got %v (%q)", tt.errMsg, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("wanted=%q, got=%q", tt.want, got)
+			}
+		})
+	}
+
+	for _, s := range []string{"plain", "two words", "it's", "", `back\slash`} {
+		got, err := splitCommandLine(quoteArg(s))
+		if err != nil || len(got) != 1 || got[0] != s {
+			t.Fatalf("expected %q to round trip through quoteArg, got %q (%v)", s, got, err)
+		}
+	}
+}
@@ -9,6 +9,9 @@ type ListCommand struct {
 	PriorityFilter *tasks.Priority
 	CategoryFilter string
 	OverdueFilter  bool
+	Sort           tasks.SortKey
+	Format         string
+	Table          tasks.TableOptions
 }
 
 func (l *ListCommand) Execute(m tasks.Manager) (string, error) {
@@ -16,5 +19,6 @@ func (l *ListCommand) Execute(m tasks.Manager) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return tasks.RenderTable(t), nil
+	tasks.SortTasks(t, l.Sort)
+	return renderTasks(t, l.Format, tableOptionsFor(m, l.Table))
 }
@@ -36,6 +36,15 @@ func TestListExecuteTableDriven(t *testing.T) {
 			},
 			want: "Task 1",
 		},
+		{
+			name: "list tasks with relative due",
+			listCmd: ListCommand{
+				Table: tasks.TableOptions{Columns: []string{"title", "due"}, RelativeDue: true},
+			},
+			mockReturn: []tasks.Task{{Id: 1, Title: "Task 1", DueDate: tasks.DueDate(testTime.AddDate(0, 0, 2))}},
+			wantCall:   listCall{},
+			want:       "in 2d",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+var (
+	markdownItemRegex    = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	markdownHeadingRegex = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	markdownMetaRegex    = regexp.MustCompile(`^(.*?)\s*\(([^()]*)\)$`)
+)
+
+// exportMarkdown writes a checklist with one section per category:
+// "- [ ] Title #tag (2024-04-12, high)". Uncategorized tasks come first.
+func exportMarkdown(w io.Writer, t []tasks.Task, _ exchangeOptions) ([]tasks.Issue, error) {
+	var issues []tasks.Issue
+	dropped := map[string]int{}
+	byCategory := map[string][]tasks.Task{}
+	for _, task := range t {
+		byCategory[task.Category] = append(byCategory[task.Category], task)
+	}
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+
+	bw := bufio.NewWriter(w)
+	for i, category := range categories {
+		if category != "" {
+			if i > 0 {
+				bw.WriteString("\n")
+			}
+			bw.WriteString("## " + category + "\n\n")
+		}
+		for _, task := range byCategory[category] {
+			box := " "
+			if task.Status == tasks.Completed {
+				box = "x"
+			}
+			words := strings.Fields(task.Title)
+			for j, word := range words {
+				if isQuickAddToken(word) || strings.HasPrefix(word, `\`) && isQuickAddToken(word[1:]) {
+					words[j] = `\` + word
+				}
+			}
+			for _, tag := range task.Tags {
+				if !quickTagRegex.MatchString("#" + tag) {
+					issues = append(issues, tasks.Issue{Message: fmt.Sprintf("task %d: tag '%s' cannot be written as #tag and was dropped", task.Id, tag)})
+					continue
+				}
+				words = append(words, "#"+tag)
+			}
+			fmt.Fprintf(bw, "- [%s] %s (%s, %s)\n", box, strings.Join(words, " "),
+				time.Time(task.DueDate).Format("2006-01-02"), strings.ToLower(task.Priority.String()))
+			if task.Description != "" {
+				dropped["description"]++
+			}
+			if len(task.Notes) > 0 {
+				dropped["notes"]++
+			}
+			if task.Recurrence != "" {
+				dropped["recurrence"]++
+			}
+		}
+	}
+	for _, field := range []string{"description", "notes", "recurrence"} {
+		if n := dropped[field]; n > 0 {
+			issues = append(issues, tasks.Issue{Message: fmt.Sprintf("%s dropped from %d task(s)", field, n)})
+		}
+	}
+	return issues, bw.Flush()
+}
+
+// importMarkdown reads checklist items; a heading sets the category of the
+// items below it. Each item is checked like an add command, so inline
+// !priority, @category, #tag and due: words work as they do there.
+func importMarkdown(r io.Reader, opts exchangeOptions) ([]tasks.Task, []tasks.Issue, error) {
+	var result []tasks.Task
+	var issues []tasks.Issue
+	category := ""
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if m := markdownHeadingRegex.FindStringSubmatch(line); m != nil {
+			category = strings.ToLower(m[1])
+			continue
+		}
+		m := markdownItemRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		title, meta := markdownMeta(strings.TrimSpace(m[2]))
+		var args []string
+		for _, value := range meta {
+			if _, err := parsePriority(value); err == nil {
+				args = append(args, "-priority", value)
+			} else {
+				args = append(args, "-due", value)
+			}
+		}
+		if category != "" && !hasQuickCategory(title) {
+			args = append(args, "-category", category)
+		}
+		task, err := addCommandTask(append(args, "--", title), opts.now)
+		if err != nil {
+			issues = append(issues, tasks.Issue{Line: n, Message: err.Error(), Skipped: true})
+			continue
+		}
+		if m[1] != " " {
+			task.Status = tasks.Completed
+		}
+		result = append(result, task)
+	}
+	return result, issues, sc.Err()
+}
+
+// markdownMeta splits a trailing "(due, priority)" off a title. Parentheses
+// holding anything else are part of the title.
+func markdownMeta(s string) (string, []string) {
+	m := markdownMetaRegex.FindStringSubmatch(s)
+	if m == nil || strings.TrimSpace(m[2]) == "" {
+		return s, nil
+	}
+	var meta []string
+	for _, part := range strings.Split(m[2], ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		_, priorityErr := parsePriority(part)
+		_, dueErr := parseDue(part)
+		if priorityErr != nil && dueErr != nil {
+			return s, nil
+		}
+		meta = append(meta, part)
+	}
+	return m[1], meta
+}
+
+func hasQuickCategory(title string) bool {
+	return slices.ContainsFunc(strings.Fields(title), quickCategoryRegex.MatchString)
+}
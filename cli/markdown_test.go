@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestExportMarkdown(t *testing.T) {
+	due := tasks.DueDate(testTime)
+	in := []tasks.Task{
+		{Id: 1, Title: "Ship it", Priority: tasks.High, DueDate: due, Category: "work", Status: tasks.Completed},
+		{Id: 2, Title: "Email @bob", Priority: tasks.Low, DueDate: due, Tags: []string{"mail", "two words"}},
+		{Id: 3, Title: "Plan", Priority: tasks.Medium, DueDate: due, Category: "home", Notes: []string{"n"}},
+	}
+	buf := bytes.Buffer{}
+	issues, err := exportMarkdown(&buf, in, exchangeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := `- [ ] Email \@bob #mail (2024-04-10, low)
+
+## home
+
+- [ ] Plan (2024-04-10, medium)
+
+## work
+
+- [x] Ship it (2024-04-10, high)
+`
+	if buf.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, buf.String())
+	}
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	wantIssues := []string{"task 2: tag 'two words' cannot be written as #tag and was dropped", "notes dropped from 1 task(s)"}
+	if !reflect.DeepEqual(lines, wantIssues) {
+		t.Errorf("expected issues %q, got %q", wantIssues, lines)
+	}
+}
+
+func TestImportMarkdown(t *testing.T) {
+	in := `# Home
+
+Some text that is not a task.
+- [ ] Water plants (tomorrow, low)
+- [x] Fix sink @garage
+* [ ] Read (the book) !high
+## Work
+  - [ ] Review PR #code (2024-06-01)
+- [ ] @a @b twice
+- [ ] Email \@bob
+`
+	got, issues, err := importMarkdown(strings.NewReader(in), exchangeOptions{now: testTime})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	day := func(d int) tasks.DueDate { return tasks.DueDate(time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC)) }
+	want := []tasks.Task{
+		{Title: "Water plants", Priority: tasks.Low, DueDate: day(11), Category: "home", Status: tasks.Pending},
+		{Title: "Fix sink", Priority: tasks.Medium, DueDate: day(10), Category: "garage", Status: tasks.Completed},
+		{Title: "Read (the book)", Priority: tasks.High, DueDate: day(10), Category: "home", Status: tasks.Pending},
+		{Title: "Review PR", Priority: tasks.Medium, DueDate: tasks.DueDate(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)),
+			Category: "work", Status: tasks.Pending, Tags: []string{"code"}},
+		{Title: "Email @bob", Priority: tasks.Medium, DueDate: day(10), Category: "work", Status: tasks.Pending},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d tasks, got %+v", len(want), got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Title != w.Title || g.Priority != w.Priority || g.Category != w.Category || g.Status != w.Status ||
+			!reflect.DeepEqual(g.Tags, w.Tags) || !time.Time(g.DueDate).Equal(time.Time(w.DueDate)) {
+			t.Errorf("task %d: expected %+v, got %+v", i, w, g)
+		}
+	}
+	if len(issues) != 1 || !strings.HasPrefix(issues[0].String(), "line 9: more than one category") || !issues[0].Skipped {
+		t.Errorf("expected a skipped line 9, got %+v", issues)
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	quickPriorityRegex = regexp.MustCompile(`(?i)^!(low|medium|high)$`)
+	quickCategoryRegex = regexp.MustCompile(`^@([^\s@]+)$`)
+	quickTagRegex      = regexp.MustCompile(`^#(\pL[\pL\pN_-]*)$`)
+	quickDueRegex      = regexp.MustCompile(`(?i)^due:(\S+)$`)
+)
+
+// quickAdd holds the fields written inline in an add title, e.g.
+// "Ship release notes !high @work #docs due:fri".
+type quickAdd struct {
+	title    string
+	priority string
+	category string
+	due      string
+	tags     []string
+}
+
+func isQuickAddToken(word string) bool {
+	return quickPriorityRegex.MatchString(word) ||
+		quickCategoryRegex.MatchString(word) ||
+		quickTagRegex.MatchString(word) ||
+		quickDueRegex.MatchString(word)
+}
+
+// parseQuickAdd strips inline tokens from a title. A token prefixed with a
+// backslash is kept in the title as written, without the backslash. Titles
+// without tokens or escapes are returned unchanged.
+func parseQuickAdd(title string) (quickAdd, error) {
+	q := quickAdd{title: title}
+	var words []string
+	changed := false
+	for _, word := range strings.Fields(title) {
+		if escaped, ok := strings.CutPrefix(word, `\`); ok && isQuickAddToken(escaped) {
+			words = append(words, escaped)
+			changed = true
+			continue
+		}
+		if m := quickPriorityRegex.FindStringSubmatch(word); m != nil {
+			if q.priority != "" {
+				return q, fmt.Errorf("add error: more than one priority in title ('!%s' and '%s')", q.priority, word)
+			}
+			q.priority = strings.ToLower(m[1])
+		} else if m := quickCategoryRegex.FindStringSubmatch(word); m != nil {
+			if q.category != "" {
+				return q, fmt.Errorf("add error: more than one category in title ('@%s' and '%s')", q.category, word)
+			}
+			q.category = strings.ToLower(m[1])
+		} else if m := quickTagRegex.FindStringSubmatch(word); m != nil {
+			q.tags = append(q.tags, m[1])
+		} else if m := quickDueRegex.FindStringSubmatch(word); m != nil {
+			if q.due != "" {
+				return q, fmt.Errorf("add error: more than one due date in title ('due:%s' and '%s')", q.due, word)
+			}
+			due := strings.ToLower(m[1])
+			if _, err := parseDue(due); err != nil {
+				return q, fmt.Errorf("add error: invalid due date '%s': %v", word, err)
+			}
+			q.due = due
+		} else {
+			words = append(words, word)
+			continue
+		}
+		changed = true
+	}
+	if changed {
+		q.title = strings.Join(words, " ")
+	}
+	return q, nil
+}
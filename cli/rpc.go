@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type RPCCommand struct{}
+
+func parseRPCCmd(a []string) (Command, error) {
+	args, err := parseArgs(newFlagSet("rpc"), a)
+	if err != nil {
+		return flagError("rpc", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("rpc error: unexpected argument '%s'", args[0])
+	}
+	return &RPCCommand{}, nil
+}
+
+// Execute answers requests on stdin until it is closed. Nothing else may be
+// written to stdout, so the result is always empty.
+func (r *RPCCommand) Execute(m tasks.Manager) (string, error) {
+	return r.run(m, os.Stdin, os.Stdout)
+}
+
+func (r *RPCCommand) run(m tasks.Manager, in io.Reader, out io.Writer) (string, error) {
+	if err := tasks.ServeRPC(m, in, out); err != nil {
+		return "", fmt.Errorf("rpc error: %v", err)
+	}
+	return "", nil
+}
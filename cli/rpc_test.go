@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseRPC(t *testing.T) {
+	args := []string{"rpc"}
+	if cmd, err := Parse(&args); err != nil || cmd == nil {
+		t.Fatalf("expected an rpc command, got %v", err)
+	}
+	args = []string{"rpc", "stdio"}
+	if _, err := Parse(&args); err == nil || !strings.Contains(err.Error(), "unexpected argument 'stdio'") {
+		t.Fatalf("expected an argument error, got %v", err)
+	}
+}
+
+func TestRPCRun(t *testing.T) {
+	m := newMockManager(testTime)
+	request := `{"jsonrpc": "2.0", "id": 7, "method": "now"}`
+	in := strings.NewReader("Content-Length: 44\r\n\r\n" + request)
+	out := &bytes.Buffer{}
+	got, err := (&RPCCommand{}).run(&m, in, out)
+	if err != nil || got != "" {
+		t.Fatalf("expected no result, got %q (%v)", got, err)
+	}
+	want := `{"jsonrpc":"2.0","id":7,"result":"2024-04-10T00:00:00Z"}`
+	if !strings.HasSuffix(out.String(), "\r\n\r\n"+want) {
+		t.Fatalf("expected response %s, got %q", want, out.String())
+	}
+
+	if _, err := (&RPCCommand{}).run(&m, strings.NewReader("hello\r\n\r\n"), out); err == nil ||
+		!strings.HasPrefix(err.Error(), "rpc error: invalid message header") {
+		t.Fatalf("expected a framing error, got %v", err)
+	}
+}
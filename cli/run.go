@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+type Options struct {
+	Output string
+	// Remote is the URL of a task server to use instead of the local file.
+	Remote string
+}
+
+func ParseOptions(a []string) (Options, []string, error) {
+	opts := Options{Output: OutputText, Remote: userConfig.Remote}
+	if userConfig.ErrorOutput != "" {
+		opts.Output = userConfig.ErrorOutput
+	}
+	for len(a) > 0 && strings.HasPrefix(a[0], "-") && !isHelpFlag(a[0]) {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(a[0], "-"), "=")
+		if name != "output" && name != "remote" {
+			return opts, nil, &UsageError{fmt.Errorf("invalid global flag '%s': must be '--output' or '--remote'", a[0])}
+		}
+		a = a[1:]
+		if !hasValue {
+			if len(a) == 0 {
+				return opts, nil, &UsageError{fmt.Errorf("invalid global flag '--%s': value required", name)}
+			}
+			value, a = a[0], a[1:]
+		}
+		if name == "remote" {
+			// an empty URL uses the local task file despite a configured remote
+			if value != "" {
+				if err := validateRemote(value); err != nil {
+					return opts, nil, &UsageError{err}
+				}
+			}
+			opts.Remote = value
+			continue
+		}
+		switch strings.ToLower(value) {
+		case OutputText, OutputJSON:
+			opts.Output = strings.ToLower(value)
+		default:
+			return opts, nil, &UsageError{fmt.Errorf("invalid output format '%s': must be 'text' or 'json'", value)}
+		}
+	}
+	return opts, a, nil
+}
+
+func Run(args []string, newManager func() (tasks.Manager, error), stdout io.Writer, stderr io.Writer) int {
+	path, err := ConfigPath()
+	if err == nil {
+		userConfig, err = LoadConfig(path)
+	}
+	// help and config run with built-in defaults, so a broken config file
+	// can still be fixed
+	configErr := err
+	if configErr != nil {
+		userConfig = Config{}
+	}
+
+	opts, args, err := ParseOptions(args)
+	if err != nil {
+		fmt.Fprintln(stderr, FormatError(err, opts.Output))
+		return ExitCode(err)
+	}
+	if args, err = expandAlias(args); err != nil {
+		fmt.Fprintln(stderr, FormatError(err, opts.Output))
+		return ExitCode(err)
+	}
+
+	cmd, err := Parse(&args)
+	if err != nil {
+		fmt.Fprintln(stderr, FormatError(err, opts.Output))
+		return ExitCode(err)
+	}
+	if configErr != nil && needsConfig(cmd) {
+		fmt.Fprintln(stderr, FormatError(configErr, opts.Output))
+		return ExitCode(configErr)
+	}
+
+	var m tasks.Manager
+	if needsManager(cmd) {
+		// a remote server runs its own hooks
+		if opts.Remote != "" {
+			m, err = tasks.NewRemoteManager(opts.Remote, nil)
+		} else if m, err = newManager(); err == nil {
+			m = withHooks(m, stderr)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, FormatError(err, opts.Output))
+			return ExitCode(err)
+		}
+	}
+
+	res, err := cmd.Execute(m)
+	if err != nil {
+		fmt.Fprintln(stderr, FormatError(err, opts.Output))
+		return ExitCode(err)
+	}
+
+	if res != "" {
+		fmt.Fprintln(stdout, res)
+	}
+	return ExitOK
+}
+
+func needsConfig(cmd Command) bool {
+	switch cmd.(type) {
+	case *HelpCommand, *ConfigCommand:
+		return false
+	}
+	return true
+}
+
+func needsManager(cmd Command) bool {
+	switch cmd.(type) {
+	case *HelpCommand, *CompletionCommand, *ConfigCommand:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestRunExitCodesTableDriven(t *testing.T) {
+	m := newMockManager(testTime)
+	tests := []struct {
+		name       string
+		args       []string
+		setup      func()
+		managerErr error
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "success",
+			args:       []string{"complete", "1"},
+			wantCode:   ExitOK,
+			wantStdout: "Task completed successfully (ID: 1)",
+		},
+		{
+			name:       "help skips manager",
+			args:       []string{"help"},
+			managerErr: fmt.Errorf("should not be called"),
+			wantCode:   ExitOK,
+			wantStdout: "Usage: todo",
+		},
+		{
+			name:       "usage error",
+			args:       []string{"frobnicate"},
+			wantCode:   ExitUsage,
+			wantStderr: "Error: invalid command",
+		},
+		{
+			name:       "invalid global flag",
+			args:       []string{"--verbose", "list"},
+			wantCode:   ExitUsage,
+			wantStderr: "invalid global flag '--verbose'",
+		},
+		{
+			name:       "not found",
+			args:       []string{"delete", "9"},
+			setup:      func() { m.deleteNextErr = fmt.Errorf("task 9 %w", tasks.ErrNotFound) },
+			wantCode:   ExitNotFound,
+			wantStderr: "Error: task 9 not found",
+		},
+		{
+			name:       "conflict",
+			args:       []string{"complete", "2"},
+			setup:      func() { m.completeNextErr = fmt.Errorf("task 2 is %w", tasks.ErrAlreadyCompleted) },
+			wantCode:   ExitConflict,
+			wantStderr: "Error: task 2 is already completed",
+		},
+		{
+			name:       "storage",
+			args:       []string{"list"},
+			managerErr: fmt.Errorf("%w: read tasks.db.json: permission denied", tasks.ErrStorage),
+			wantCode:   ExitStorage,
+			wantStderr: "Error: storage error",
+		},
+		{
+			name:       "other failure",
+			args:       []string{"list"},
+			setup:      func() { m.listNextErr = fmt.Errorf("boom") },
+			wantCode:   ExitFailure,
+			wantStderr: "Error: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.reset()
+			if tt.setup != nil {
+				tt.setup()
+			}
+			newManager := func() (tasks.Manager, error) {
+				if tt.managerErr != nil {
+					return nil, tt.managerErr
+				}
+				return &m, nil
+			}
+			stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+			code := Run(tt.args, newManager, &stdout, &stderr)
+
+			if code != tt.wantCode {
+				t.Fatalf("expected exit code %d, got %d (stderr %q)", tt.wantCode, code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Fatalf("expected stdout to contain %q, got %q", tt.wantStdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Fatalf("expected stderr to contain %q, got %q", tt.wantStderr, stderr.String())
+			}
+			if tt.wantStderr == "" && stderr.Len() > 0 || tt.wantStderr != "" && stdout.Len() > 0 {
+				t.Fatalf("expected output on one stream only, got stdout %q, stderr %q", stdout.String(), stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunJSONError(t *testing.T) {
+	m := newMockManager(testTime)
+	m.deleteNextErr = fmt.Errorf("task 9 %w", tasks.ErrNotFound)
+	newManager := func() (tasks.Manager, error) { return &m, nil }
+
+	for _, args := range [][]string{
+		{"--output", "json", "delete", "9"},
+		{"--output=JSON", "delete", "9"},
+	} {
+		stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+		code := Run(args, newManager, &stdout, &stderr)
+		if code != ExitNotFound {
+			t.Fatalf("expected exit code %d, got %d", ExitNotFound, code)
+		}
+
+		var got errorObject
+		if err := json.Unmarshal(stderr.Bytes(), &got); err != nil {
+			t.Fatalf("expected JSON error on stderr, got %q: %v", stderr.String(), err)
+		}
+		want := errorObject{Error: "task 9 not found", Code: "not_found", ExitCode: ExitNotFound}
+		if got != want {
+			t.Fatalf("expected %+v, got %+v", want, got)
+		}
+	}
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	if code := Run([]string{"--output", "yaml", "list"}, newManager, &stdout, &stderr); code != ExitUsage {
+		t.Fatalf("expected exit code %d, got %d", ExitUsage, code)
+	}
+}
+
+func TestRunRemote(t *testing.T) {
+	srv := httptest.NewServer(tasks.NewHandler(newFileManager(t, "shared.json")))
+	defer srv.Close()
+	local := func() (tasks.Manager, error) { return nil, fmt.Errorf("local task file opened") }
+	run := func(args ...string) (int, string, string) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := Run(args, local, stdout, stderr)
+		return code, strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String())
+	}
+
+	// each step sees the changes of the ones before it on the server
+	tests := []struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{args: []string{"--remote", srv.URL, "add", "Call dentist", "-p", "high", "-c", "health"}, wantStdout: "Added task 'Call dentist' successfully (ID: 1)"},
+		{args: []string{"--remote=" + srv.URL, "edit", "1", "-tags", "phone"}, wantStdout: "Updated task 'Call dentist' successfully (ID: 1)"},
+		{args: []string{"--remote", srv.URL, "view", "save", "health", "category:health"}, wantStdout: "Saved view 'health'"},
+		{args: []string{"--remote", srv.URL, "view", "health"}, wantStdout: "Call dentist"},
+		{args: []string{"--remote", srv.URL, "complete", "1"}, wantStdout: "Task completed successfully (ID: 1)"},
+		{args: []string{"--remote", srv.URL, "complete", "1"}, wantCode: ExitConflict, wantStderr: "Error: task 1 is already completed"},
+		{args: []string{"--remote", srv.URL, "delete", "9"}, wantCode: ExitNotFound, wantStderr: "Error: task 9 not found"},
+		{args: []string{"--remote", "localhost:8080", "list"}, wantCode: ExitUsage, wantStderr: "invalid remote URL 'localhost:8080'"},
+		{args: []string{"--remote"}, wantCode: ExitUsage, wantStderr: "invalid global flag '--remote': value required"},
+		{args: []string{"--remote=", "list"}, wantCode: ExitFailure, wantStderr: "local task file opened"},
+	}
+	for _, tt := range tests {
+		code, stdout, stderr := run(tt.args...)
+		if code != tt.wantCode || !strings.Contains(stdout, tt.wantStdout) || !strings.Contains(stderr, tt.wantStderr) {
+			t.Fatalf("%v: wanted code=%d stdout~%q stderr~%q, got code=%d stdout=%q stderr=%q", tt.args, tt.wantCode, tt.wantStdout, tt.wantStderr, code, stdout, stderr)
+		}
+	}
+
+	// the configured remote is used without the flag
+	useConfigFile(t, `{"remote": "`+srv.URL+`"}`)
+	if code, stdout, stderr := run("list", "-status", "completed"); code != ExitOK || !strings.Contains(stdout, "Call dentist") {
+		t.Fatalf("expected the remote list, got %d %q %q", code, stdout, stderr)
+	}
+
+	srv.Close()
+	code, _, stderr := run("--output", "json", "list")
+	var got errorObject
+	if err := json.Unmarshal([]byte(stderr), &got); err != nil || code != ExitUnavailable || got.Code != "unavailable" ||
+		!strings.HasPrefix(got.Error, "task server unavailable at "+srv.URL) {
+		t.Fatalf("expected an unavailable error, got %d %q", code, stderr)
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	path := useConfigFile(t, `{"hookTimeout": "5s"}`)
+	dir := filepath.Join(filepath.Dir(path), "hooks")
+	os.MkdirAll(dir, 0755)
+	hook := `#!/bin/sh
+input=$(cat)
+case "$input" in *'"category":"work"'*)
+	case "$input" in *'"tags"'*) ;; *) echo 'work tasks need a tag' >&2; exit 1;; esac
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "pre-add"), []byte(hook), 0755); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	m := newFileManager(t, "tasks.json")
+
+	code, stdout, stderr := runWith(m, "--output", "json", "add", "Write report", "-c", "work")
+	var got errorObject
+	if err := json.Unmarshal([]byte(stderr), &got); err != nil || code != ExitRejected || got.Code != "rejected" ||
+		got.Error != "pre-add hook rejected the change: work tasks need a tag" {
+		t.Fatalf("expected a veto, got %d %q %q", code, stdout, stderr)
+	}
+	if code, stdout, stderr := runWith(m, "add", "Write report", "-c", "work", "-t", "q3"); code != ExitOK {
+		t.Fatalf("expected the add to pass the hook, got %d %q %q", code, stdout, stderr)
+	}
+
+	// a remote server runs its own hooks, so the client does not
+	srv := httptest.NewServer(tasks.NewHandler(newFileManager(t, "remote.json")))
+	defer srv.Close()
+	if code, stdout, stderr := runWith(m, "--remote", srv.URL, "add", "Write report", "-c", "work"); code != ExitOK {
+		t.Fatalf("expected the remote add to skip local hooks, got %d %q %q", code, stdout, stderr)
+	}
+}
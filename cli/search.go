@@ -1,17 +1,42 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/stevexciv/golang-todo-cli/tasks"
 )
 
 type SearchCommand struct {
-	Query string
+	Query          string
+	Mode           tasks.SearchMode
+	StatusFilter   *tasks.Status
+	PriorityFilter *tasks.Priority
+	CategoryFilter string
+	OverdueFilter  bool
+	Limit          int
+	Format         string
+	Table          tasks.TableOptions
 }
 
 func (s *SearchCommand) Execute(m tasks.Manager) (string, error) {
-	t, err := m.SearchTasks(s.Query)
+	t, err := m.SearchTasks(s.Query, tasks.SearchOptions{
+		Mode:        s.Mode,
+		Status:      s.StatusFilter,
+		Priority:    s.PriorityFilter,
+		Category:    s.CategoryFilter,
+		OverdueOnly: s.OverdueFilter,
+		Limit:       s.Limit,
+	})
 	if err != nil {
 		return "", err
 	}
-	return tasks.RenderTable(t), nil
+	if outputFormat(s.Format) == "json" {
+		return renderTasks(t, "json", tasks.TableOptions{})
+	}
+	if len(t) == 0 {
+		return fmt.Sprintf("No tasks found matching %q", s.Query), nil
+	}
+	opts := tableOptionsFor(m, s.Table)
+	opts.Highlight = tasks.HighlightPattern(s.Query, s.Mode)
+	return fmt.Sprintf("Found %d task(s):\n", len(t)) + tasks.RenderTableWith(t, opts), nil
 }
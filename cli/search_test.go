@@ -11,6 +11,8 @@ import (
 
 func TestSearchExecuteTableDriven(t *testing.T) {
 	m := newMockManager(testTime)
+	pending := tasks.Pending
+	priority := tasks.High
 	tests := []struct {
 		name       string
 		searchCmd  SearchCommand
@@ -29,6 +31,82 @@ func TestSearchExecuteTableDriven(t *testing.T) {
 			wantCall: searchCall{
 				query: "tasks",
 			},
+			want: "Found 1 task(s)",
+		},
+		{
+			name: "search tasks with filters",
+			searchCmd: SearchCommand{
+				Query:          "tasks",
+				StatusFilter:   &pending,
+				PriorityFilter: &priority,
+				CategoryFilter: "work",
+				OverdueFilter:  true,
+				Limit:          5,
+			},
+			mockReturn: []tasks.Task{
+				{Id: 1, Title: "Do some tasks"},
+				{Id: 2, Title: "Do more tasks"},
+			},
+			wantCall: searchCall{
+				query:       "tasks",
+				status:      &pending,
+				priority:    &priority,
+				category:    "work",
+				overdueOnly: true,
+				limit:       5,
+			},
+			want: "Found 2 task(s)",
+		},
+		{
+			name: "search tasks no results",
+			searchCmd: SearchCommand{
+				Query: "xyz",
+			},
+			mockReturn: []tasks.Task{},
+			wantCall: searchCall{
+				query: "xyz",
+			},
+			want: `No tasks found matching "xyz"`,
+		},
+		{
+			name: "search tasks as json",
+			searchCmd: SearchCommand{
+				Query:  "xyz",
+				Format: "json",
+			},
+			mockReturn: []tasks.Task{},
+			wantCall: searchCall{
+				query: "xyz",
+			},
+			want: "[]",
+		},
+		{
+			name: "search tasks highlights matches",
+			searchCmd: SearchCommand{
+				Query: "some",
+				Table: tasks.TableOptions{Color: tasks.ColorAlways},
+			},
+			mockReturn: []tasks.Task{
+				{Id: 1, Title: "Do some tasks"},
+			},
+			wantCall: searchCall{
+				query: "some",
+			},
+			want: "\x1b[7msome\x1b[0m",
+		},
+		{
+			name: "search tasks regex",
+			searchCmd: SearchCommand{
+				Query: "^do",
+				Mode:  tasks.SearchRegex,
+			},
+			mockReturn: []tasks.Task{
+				{Id: 1, Title: "Do some tasks"},
+			},
+			wantCall: searchCall{
+				query: "^do",
+				mode:  tasks.SearchRegex,
+			},
 			want: "Do some tasks",
 		},
 	}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+const defaultServeAddr = "127.0.0.1:8080"
+
+type ServeCommand struct {
+	Addr string
+}
+
+type serveFlags struct {
+	addr *string
+}
+
+func newServeFlagSet() (*flag.FlagSet, *serveFlags) {
+	fs := newFlagSet("serve")
+	f := &serveFlags{
+		addr: fs.String("addr", defaultServeAddr, "Address to listen on, as host:port"),
+	}
+	alias(fs, "a", "addr")
+	return fs, f
+}
+
+func parseServeCmd(a []string) (Command, error) {
+	serveFlagSet, f := newServeFlagSet()
+	args, err := parseArgs(serveFlagSet, a)
+	if err != nil {
+		return flagError("serve", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("serve error: unexpected argument '%s' (use -addr to pick the address)", args[0])
+	}
+	if _, _, err := net.SplitHostPort(*f.addr); err != nil {
+		return nil, fmt.Errorf("serve error: invalid address '%s': %v", *f.addr, err)
+	}
+	return &ServeCommand{Addr: *f.addr}, nil
+}
+
+// Execute serves until interrupted.
+func (s *ServeCommand) Execute(m tasks.Manager) (string, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return s.run(ctx, m, os.Stderr)
+}
+
+func (s *ServeCommand) run(ctx context.Context, m tasks.Manager, stderr io.Writer) (string, error) {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return "", fmt.Errorf("serve error: %v", err)
+	}
+	if host, _, _ := net.SplitHostPort(s.Addr); !isLoopback(host) {
+		fmt.Fprintln(stderr, "Warning: the API has no authentication and is reachable from other machines")
+	}
+	fmt.Fprintf(stderr, "Serving tasks on http://%s (API description at /openapi.json, Ctrl-C to stop)\n", ln.Addr())
+	return serve(ctx, ln, m)
+}
+
+// serve stops the server when ctx is done, and waits for the shutdown
+// either way, so a failing server does not leave it behind.
+func serve(ctx context.Context, ln net.Listener, m tasks.Manager) (string, error) {
+	srv := &http.Server{Handler: tasks.NewHandler(m), ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	err := srv.Serve(ln)
+	stop()
+	<-done
+	if !errors.Is(err, http.ErrServerClosed) {
+		return "", fmt.Errorf("serve error: %v", err)
+	}
+	return "Server stopped", nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
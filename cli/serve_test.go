@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseServeTableDriven(t *testing.T) {
+	tests := []struct {
+		args   []string
+		want   Command
+		errMsg string
+	}{
+		{args: []string{"serve"}, want: &ServeCommand{Addr: "127.0.0.1:8080"}},
+		{args: []string{"serve", "-addr", ":9000"}, want: &ServeCommand{Addr: ":9000"}},
+		{args: []string{"serve", "-a", "localhost:0"}, want: &ServeCommand{Addr: "localhost:0"}},
+		{args: []string{"serve", "-addr", "9000"}, errMsg: "invalid address '9000'"},
+		{args: []string{"serve", "now"}, errMsg: "unexpected argument 'now'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			cmd, err := Parse(&tt.args)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(cmd, tt.want) {
+				t.Fatalf("expected %+v, got %+v (%v)", tt.want, cmd, err)
+			}
+		})
+	}
+}
+
+func TestServeStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	m := newMockManager(testTime)
+	stderr := &bytes.Buffer{}
+	got, err := (&ServeCommand{Addr: "127.0.0.1:0"}).run(ctx, &m, stderr)
+	if err != nil || got != "Server stopped" {
+		t.Fatalf("expected a clean stop, got %q (%v)", got, err)
+	}
+	if !strings.HasPrefix(stderr.String(), "Serving tasks on http://127.0.0.1:") {
+		t.Fatalf("unexpected stderr %q", stderr.String())
+	}
+
+	if _, err := (&ServeCommand{Addr: "256.0.0.1:0"}).run(ctx, &m, stderr); err == nil ||
+		!strings.HasPrefix(err.Error(), "serve error:") {
+		t.Fatalf("expected a listen error, got %v", err)
+	}
+}
+
+func TestServeFailureStopsShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	ln.Close()
+	m := newMockManager(testTime)
+	if _, err := serve(context.Background(), ln, &m); err == nil || !strings.HasPrefix(err.Error(), "serve error:") {
+		t.Fatalf("expected a serve error, got %v", err)
+	}
+	buf := make([]byte, 1<<20)
+	if stacks := string(buf[:runtime.Stack(buf, true)]); strings.Contains(stacks, "cli.serve.func") {
+		t.Fatalf("expected the shutdown goroutine to finish, got:\n%s", stacks)
+	}
+}
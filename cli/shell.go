@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type ShellCommand struct{}
+
+var shellBuiltins = []struct{ name, summary string }{
+	{"commit", "Write the changes made since the last commit"},
+	{"rollback", "Discard the changes made since the last commit"},
+	{"exit", "Leave the shell (also 'quit' or Ctrl-D)"},
+}
+
+// sessionUnavailable lists commands that make no sense inside a shell or
+// batch session.
+var sessionUnavailable = []string{"shell", "batch", "ui", "rpc", "serve", "completion"}
+
+func parseShellCmd(a []string) (Command, error) {
+	args, err := parseArgs(newFlagSet("shell"), a)
+	if err != nil {
+		return flagError("shell", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("shell error: unexpected argument '%s'", args[0])
+	}
+	return &ShellCommand{}, nil
+}
+
+func (s *ShellCommand) Execute(m tasks.Manager) (string, error) {
+	sh := newShell(m, os.Stdout, os.Stderr)
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		// not a terminal, so read commands line by line without editing
+		return "", sh.runLines(os.Stdin)
+	}
+	restore()
+	return "", sh.runTerminal(os.Stdin)
+}
+
+// shell runs commands against one loaded manager. When the manager supports
+// batching, changes are kept in memory until 'commit'.
+type shell struct {
+	m       tasks.Manager
+	batch   tasks.Batcher
+	stdout  io.Writer
+	stderr  io.Writer
+	input   lineEditor
+	leaving bool
+	done    bool
+}
+
+func newShell(m tasks.Manager, stdout io.Writer, stderr io.Writer) *shell {
+	s := &shell{m: m, stdout: stdout, stderr: stderr}
+	if b, ok := m.(tasks.Batcher); ok {
+		s.batch = b
+		b.Begin()
+	}
+	return s
+}
+
+func (s *shell) pending() int {
+	if s.batch == nil {
+		return 0
+	}
+	return s.batch.Pending()
+}
+
+func (s *shell) prompt() string {
+	if n := s.pending(); n > 0 {
+		return fmt.Sprintf("%s(%d)> ", programName, n)
+	}
+	return programName + "> "
+}
+
+// runLines reads commands that are not typed at a terminal. Changes still
+// uncommitted at the end of input are discarded and reported as an error, so
+// a script that forgets 'commit' does not appear to have worked.
+func (s *shell) runLines(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for !s.done && sc.Scan() {
+		s.exec(sc.Text())
+	}
+	if s.done {
+		return sc.Err()
+	}
+	n := s.pending()
+	if n > 0 {
+		s.batch.Rollback()
+	}
+	s.done = true
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("shell error: discarded %d uncommitted change(s) at the end of input: end the input with 'commit', or use 'batch' to save at the end", n)
+	}
+	return nil
+}
+
+func (s *shell) runTerminal(f *os.File) error {
+	in := bufio.NewReader(f)
+	fmt.Fprintln(s.stdout, "Type 'help' for commands, 'commit' to save changes and 'exit' to leave.")
+	for !s.done {
+		line, err := s.readLine(in, f)
+		if errors.Is(err, io.EOF) {
+			s.exit()
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		s.exec(line)
+	}
+	return nil
+}
+
+// readLine reads one line with editing and history, switching the terminal
+// to raw mode only while the user is typing.
+func (s *shell) readLine(in *bufio.Reader, f *os.File) (string, error) {
+	restore, err := makeRaw(f)
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+	for {
+		fmt.Fprint(s.stdout, "\r\x1b[K"+s.prompt()+s.input.String())
+		if back := len(s.input.buf) - s.input.pos; back > 0 {
+			fmt.Fprintf(s.stdout, "\x1b[%dD", back)
+		}
+		k, err := readKey(in)
+		if err != nil {
+			return "", err
+		}
+		switch k {
+		case keyEnter:
+			fmt.Fprint(s.stdout, "\r\n")
+			return s.input.commit(), nil
+		case keyCtrlC:
+			fmt.Fprint(s.stdout, "^C\r\n")
+			s.input.set("")
+			s.input.hpos = len(s.input.history)
+		case keyCtrlD:
+			if len(s.input.buf) == 0 {
+				fmt.Fprint(s.stdout, "\r\n")
+				return "", io.EOF
+			}
+			s.input.handle(keyDelete)
+		case keyCtrlL:
+			fmt.Fprint(s.stdout, "\x1b[H\x1b[2J")
+		default:
+			s.input.handle(k)
+		}
+	}
+}
+
+func (s *shell) exec(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+	args, err := splitCommandLine(line)
+	if err != nil {
+		s.printError(&UsageError{err}, OutputText)
+		return
+	}
+	if args[0] != "exit" && args[0] != "quit" {
+		s.leaving = false
+	}
+	switch args[0] {
+	case "exit", "quit":
+		s.exit()
+		return
+	case "commit":
+		s.commit()
+		return
+	case "rollback":
+		s.rollback()
+		return
+	case "help":
+		if len(args) == 1 {
+			fmt.Fprintln(s.stdout, shellUsage())
+			return
+		}
+	}
+	if output, err := execArgs(s.m, args, s.stdout); err != nil {
+		s.printError(err, output)
+	}
+}
+
+// execArgs runs one command line of a shell or batch session and prints its
+// result. It returns the output format requested on the line, for errors.
+func execArgs(m tasks.Manager, args []string, stdout io.Writer) (string, error) {
+	opts, args, err := ParseOptions(args)
+	if err != nil {
+		return opts.Output, err
+	}
+	if opts.Remote != userConfig.Remote {
+		return opts.Output, &UsageError{fmt.Errorf("'--remote' is not available inside a shell or batch: pass it before the command that starts the session")}
+	}
+	if args, err = expandAlias(args); err != nil {
+		return opts.Output, err
+	}
+	if len(args) > 0 && slices.Contains(sessionUnavailable, args[0]) {
+		return opts.Output, &UsageError{fmt.Errorf("'%s' is not available inside a shell or batch", args[0])}
+	}
+	cmd, err := Parse(&args)
+	if err != nil {
+		return opts.Output, err
+	}
+	res, err := cmd.Execute(m)
+	if err != nil {
+		return opts.Output, err
+	}
+	if res != "" {
+		fmt.Fprintln(stdout, res)
+	}
+	return opts.Output, nil
+}
+
+func (s *shell) printError(err error, output string) {
+	fmt.Fprintln(s.stderr, FormatError(err, output))
+}
+
+func (s *shell) commit() {
+	if s.batch == nil {
+		s.printError(errors.New("commit error: changes are saved immediately by this task store"), OutputText)
+		return
+	}
+	n := s.batch.Pending()
+	if err := s.batch.Commit(); err != nil {
+		s.printError(err, OutputText)
+		return
+	}
+	s.batch.Begin()
+	if n == 0 {
+		fmt.Fprintln(s.stdout, "Nothing to commit")
+		return
+	}
+	fmt.Fprintf(s.stdout, "Committed %d change(s)\n", n)
+}
+
+func (s *shell) rollback() {
+	if s.batch == nil {
+		s.printError(errors.New("rollback error: changes are saved immediately by this task store"), OutputText)
+		return
+	}
+	n := s.batch.Pending()
+	s.batch.Rollback()
+	s.batch.Begin()
+	fmt.Fprintf(s.stdout, "Rolled back %d change(s)\n", n)
+}
+
+// exit leaves the shell. With uncommitted changes the first request only
+// warns, and a second one in a row discards them.
+func (s *shell) exit() {
+	if n := s.pending(); n > 0 && !s.leaving {
+		s.leaving = true
+		fmt.Fprintf(s.stderr, "There are %d uncommitted change(s): run 'commit' to save them, or 'exit' again to discard them\n", n)
+		return
+	}
+	s.discard()
+}
+
+func (s *shell) discard() {
+	if n := s.pending(); n > 0 {
+		s.batch.Rollback()
+		fmt.Fprintf(s.stderr, "Discarded %d uncommitted change(s)\n", n)
+	}
+	s.done = true
+}
+
+func shellUsage() string {
+	sb := strings.Builder{}
+	sb.WriteString("Commands:\n")
+	for _, spec := range commandSpecs {
+		if !slices.Contains(sessionUnavailable, spec.name) {
+			fmt.Fprintf(&sb, "  %-10s %s\n", spec.name, spec.summary)
+		}
+	}
+	sb.WriteString("\nShell commands:\n")
+	for _, builtin := range shellBuiltins {
+		fmt.Fprintf(&sb, "  %-10s %s\n", builtin.name, builtin.summary)
+	}
+	sb.WriteString("\nRun 'help <command>' for details on a command.")
+	return sb.String()
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type batchingMock struct {
+	*mockManager
+	begins    int
+	commits   int
+	rollbacks int
+	pending   int
+}
+
+func (b *batchingMock) Begin()        { b.begins++ }
+func (b *batchingMock) Pending() int  { return b.pending }
+func (b *batchingMock) InBatch() bool { return b.begins > b.commits+b.rollbacks }
+
+func (b *batchingMock) Commit() error {
+	b.commits++
+	b.pending = 0
+	return nil
+}
+
+func (b *batchingMock) Rollback() {
+	b.rollbacks++
+	b.pending = 0
+}
+
+func runShell(m tasks.Manager, input string) (*shell, string, string) {
+	stdout, stderr := &strings.Builder{}, &strings.Builder{}
+	sh := newShell(m, stdout, stderr)
+	sh.runLines(strings.NewReader(input))
+	return sh, stdout.String(), stderr.String()
+}
+
+func TestShellRunsCommands(t *testing.T) {
+	m := newMockManager(testTime)
+	m.addNextOk = &tasks.Task{Id: 1, Title: "Call dentist"}
+	input := strings.Join([]string{
+		"# comments and blank lines are skipped",
+		"",
+		"add 'Call dentist' -p high",
+		"frobnicate",
+		"add \"unterminated",
+		"--output json complete",
+		"ui",
+		"--output text rpc",
+		"serve",
+		"--remote http://localhost:8080 list",
+		"complete 1",
+		"commit",
+		"exit",
+		"delete 1",
+	}, "\n")
+
+	sh, stdout, stderr := runShell(&m, input)
+
+	if !sh.done {
+		t.Fatalf("expected shell to be done")
+	}
+	if len(m.addCalls) != 1 || m.addCalls[0].title != "Call dentist" || m.addCalls[0].priority != tasks.High {
+		t.Fatalf("unexpected add calls: %v", m.addCalls)
+	}
+	if len(m.completeCalls) != 1 || len(m.deleteCalls) != 0 {
+		t.Fatalf("expected complete before exit and nothing after, got %v, %v", m.completeCalls, m.deleteCalls)
+	}
+	for _, want := range []string{"Added task 'Call dentist' successfully (ID: 1)", "Task completed successfully (ID: 1)"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected stdout to contain %q, got:\n%v", want, stdout)
+		}
+	}
+	for _, want := range []string{
+		"Error: invalid command",
+		"Error: unterminated \" quote",
+		`"code":"usage"`,
+		"'ui' is not available inside a shell or batch",
+		"'rpc' is not available inside a shell or batch",
+		"'serve' is not available inside a shell or batch",
+		"'--remote' is not available inside a shell or batch",
+		"changes are saved immediately",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Fatalf("expected stderr to contain %q, got:\n%v", want, stderr)
+		}
+	}
+}
+
+func TestShellBatching(t *testing.T) {
+	mock := newMockManager(testTime)
+	m := &batchingMock{mockManager: &mock}
+
+	sh := newShell(m, &strings.Builder{}, &strings.Builder{})
+	if m.begins != 1 || sh.prompt() != "todo> " {
+		t.Fatalf("expected a batch to be started, got %d begins, prompt %q", m.begins, sh.prompt())
+	}
+	m.pending = 2
+	if sh.prompt() != "todo(2)> " {
+		t.Fatalf("expected prompt to show pending changes, got %q", sh.prompt())
+	}
+
+	tests := []struct {
+		name          string
+		pending       int
+		input         string
+		wantCommits   int
+		wantRollbacks int
+		wantListed    bool
+		wantOut       string
+		wantErr       string
+	}{
+		{name: "commit", pending: 2, input: "commit", wantCommits: 1, wantOut: "Committed 2 change(s)"},
+		{name: "nothing to commit", input: "commit", wantCommits: 1, wantOut: "Nothing to commit"},
+		{name: "rollback", pending: 1, input: "rollback", wantRollbacks: 1, wantOut: "Rolled back 1 change(s)"},
+		{name: "exit warns first", pending: 3, input: "exit\nlist", wantRollbacks: 1, wantListed: true, wantErr: "There are 3 uncommitted change(s)"},
+		{name: "exit twice discards", pending: 3, input: "exit\nquit\nlist", wantRollbacks: 1, wantErr: "Discarded 3 uncommitted change(s)"},
+		{name: "exit without changes", input: "exit\nlist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*m = batchingMock{mockManager: &mock, pending: tt.pending}
+			mock.reset()
+			_, stdout, stderr := runShell(m, tt.input)
+			if m.commits != tt.wantCommits || m.rollbacks != tt.wantRollbacks {
+				t.Fatalf("expected %d commits and %d rollbacks, got %d and %d", tt.wantCommits, tt.wantRollbacks, m.commits, m.rollbacks)
+			}
+			if listed := len(mock.listCalls) > 0; listed != tt.wantListed {
+				t.Fatalf("expected listed=%v, got %v", tt.wantListed, listed)
+			}
+			if !strings.Contains(stdout, tt.wantOut) || !strings.Contains(stderr, tt.wantErr) {
+				t.Fatalf("expected stdout %q and stderr %q, got:\n%v\n%v", tt.wantOut, tt.wantErr, stdout, stderr)
+			}
+		})
+	}
+}
+
+func TestShellEndOfInput(t *testing.T) {
+	mock := newMockManager(testTime)
+	tests := []struct {
+		name          string
+		pending       int
+		input         string
+		wantCommits   int
+		wantRollbacks int
+		wantErr       string
+	}{
+		{name: "committed", pending: 2, input: "complete 1\ncommit", wantCommits: 1},
+		{name: "uncommitted", pending: 2, input: "complete 1", wantRollbacks: 1, wantErr: "shell error: discarded 2 uncommitted change(s) at the end of input"},
+		{name: "nothing to save", input: "list"},
+		{name: "explicit exit", pending: 2, input: "exit\nexit", wantRollbacks: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &batchingMock{mockManager: &mock, pending: tt.pending}
+			mock.reset()
+			sh := newShell(m, &strings.Builder{}, &strings.Builder{})
+			err := sh.runLines(strings.NewReader(tt.input))
+			if m.commits != tt.wantCommits || m.rollbacks != tt.wantRollbacks {
+				t.Fatalf("expected %d commits and %d rollbacks, got %d and %d", tt.wantCommits, tt.wantRollbacks, m.commits, m.rollbacks)
+			}
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSessionRejectsAliasedSessions(t *testing.T) {
+	useConfigFile(t, "")
+	userConfig.Aliases = map[string]string{"r": "rpc", "sh": "shell"}
+	m := newMockManager(testTime)
+	for _, alias := range []string{"r", "sh"} {
+		if _, err := execArgs(&m, []string{alias}, &strings.Builder{}); err == nil || !strings.Contains(err.Error(), "is not available inside a shell or batch") {
+			t.Fatalf("expected alias '%s' to be rejected, got %v", alias, err)
+		}
+	}
+}
+
+func TestShellHelp(t *testing.T) {
+	m := newMockManager(testTime)
+	_, stdout, _ := runShell(&m, "help\nhelp add")
+	for _, want := range []string{"commit", "rollback", "Usage: todo add <title> [flags]"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected help to contain %q, got:\n%v", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "  ui ") {
+		t.Fatalf("expected help not to list commands unavailable in the shell, got:\n%v", stdout)
+	}
+}
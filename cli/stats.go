@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type StatsCommand struct {
+	CategoryFilter string
+	Weeks          int
+	Oldest         int
+	Format         string
+}
+
+type statsFlags struct {
+	category *string
+	weeks    *int
+	oldest   *int
+	format   *string
+}
+
+func newStatsFlagSet() (*flag.FlagSet, *statsFlags) {
+	fs := newFlagSet("stats")
+	format := "text"
+	if userConfig.Output == "json" {
+		format = "json"
+	}
+	f := &statsFlags{
+		category: fs.String("category", "", "Category filter"),
+		weeks:    fs.Int("weeks", 8, "Number of weeks in the throughput history"),
+		oldest:   fs.Int("oldest", 5, "Number of oldest open tasks to show"),
+		format:   fs.String("format", format, "Output format: text, json"),
+	}
+	alias(fs, "c", "category")
+	alias(fs, "w", "weeks")
+	return fs, f
+}
+
+func parseStatsCmd(a []string) (Command, error) {
+	statsFlagSet, f := newStatsFlagSet()
+	args, err := parseArgs(statsFlagSet, a)
+	if err != nil {
+		return flagError("stats", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("stats error: unexpected argument '%s'", args[0])
+	}
+	if *f.weeks < 1 || *f.weeks > 104 {
+		return nil, fmt.Errorf("stats error: weeks must be between 1 and 104")
+	}
+	if *f.oldest < 0 {
+		return nil, fmt.Errorf("stats error: oldest cannot be negative")
+	}
+	if *f.format != "text" && *f.format != "json" {
+		return nil, fmt.Errorf("invalid output format '%s': must be 'text' or 'json'", *f.format)
+	}
+	return &StatsCommand{CategoryFilter: *f.category, Weeks: *f.weeks, Oldest: *f.oldest, Format: *f.format}, nil
+}
+
+func (s *StatsCommand) Execute(m tasks.Manager) (string, error) {
+	t, err := m.ListTasks(nil, nil, s.CategoryFilter, false)
+	if err != nil {
+		return "", err
+	}
+	stats := tasks.ComputeStats(t, tasks.StatsOptions{Now: m.Now(), WeekStart: weekStart(), Weeks: s.Weeks, Oldest: s.Oldest})
+	if s.Format == "json" {
+		return tasks.RenderStatsJSON(stats)
+	}
+	return tasks.RenderStats(stats), nil
+}
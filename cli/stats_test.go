@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestParseStatsTableDriven(t *testing.T) {
+	tests := []struct {
+		args   []string
+		want   *StatsCommand
+		errMsg string
+	}{
+		{args: []string{"stats"}, want: &StatsCommand{Weeks: 8, Oldest: 5, Format: "text"}},
+		{args: []string{"stats", "-c", "work", "-w", "12", "--oldest", "0", "--format", "json"}, want: &StatsCommand{CategoryFilter: "work", Weeks: 12, Oldest: 0, Format: "json"}},
+		{args: []string{"stats", "--weeks", "0"}, errMsg: "weeks must be between 1 and 104"},
+		{args: []string{"stats", "--oldest", "-1"}, errMsg: "oldest cannot be negative"},
+		{args: []string{"stats", "--format", "table"}, errMsg: "invalid output format 'table'"},
+		{args: []string{"stats", "work"}, errMsg: "unexpected argument 'work'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			cmd, err := Parse(&tt.args)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(cmd, tt.want) {
+				t.Fatalf("expected %+v, got %+v (%v)", tt.want, cmd, err)
+			}
+		})
+	}
+}
+
+func TestStatsExecute(t *testing.T) {
+	m := newMockManager(testTime)
+	m.listNextOk = []tasks.Task{
+		{Id: 1, Title: "Call dentist", Priority: tasks.High, DueDate: tasks.DueDate(testTime.AddDate(0, 0, -1)), Category: "health", CreatedAt: testTime.AddDate(0, 0, -3)},
+		{Id: 2, Title: "Buy milk", Status: tasks.Completed, CreatedAt: testTime.AddDate(0, 0, -1), CompletedAt: testTime},
+	}
+
+	got, err := (&StatsCommand{CategoryFilter: "health", Weeks: 4, Oldest: 5, Format: "text"}).Execute(&m)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	for _, want := range []string{"Tasks:      2 (1 pending, 1 completed)", "Overdue:    1", "Avg. time to complete: 24.0h (1 task(s))", "1  Call dentist  3d"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected report to contain %q, got:\n%s", want, got)
+		}
+	}
+	if len(m.listCalls) != 1 || m.listCalls[0].category != "health" || m.listCalls[0].status != nil {
+		t.Fatalf("expected an unfiltered list of the category, got %v", m.listCalls)
+	}
+
+	got, err = (&StatsCommand{Weeks: 4, Oldest: 5, Format: "json"}).Execute(&m)
+	var stats tasks.Stats
+	if err != nil || json.Unmarshal([]byte(got), &stats) != nil || stats.Completed != 1 || len(stats.Weekly) != 4 {
+		t.Fatalf("expected JSON stats, got %s (%v)", got, err)
+	}
+}
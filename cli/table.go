@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type tableFlags struct {
+	columns  *string
+	style    *string
+	width    *int
+	wrap     *bool
+	relative *bool
+	color    *string
+}
+
+func addTableFlags(fs *flag.FlagSet) *tableFlags {
+	return &tableFlags{
+		columns:  fs.String("columns", configDefault(os.Getenv("TODO_COLUMNS"), userConfig.Columns), "Comma-separated table columns: id, title, priority, due, category, status, tags (default from $TODO_COLUMNS or the 'columns' setting)"),
+		style:    fs.String("style", configDefault(os.Getenv("TODO_STYLE"), userConfig.Style), "Table style: default, compact, borderless (default from $TODO_STYLE or the 'style' setting)"),
+		width:    fs.Int("width", 0, "Maximum table width (default: terminal width)"),
+		wrap:     fs.Bool("wrap", settingBool("TODO_WRAP", userConfig.Wrap), "Wrap long titles instead of truncating them (default from $TODO_WRAP or the 'wrap' setting)"),
+		relative: fs.Bool("relative", settingBool("TODO_RELATIVE_DUE", userConfig.RelativeDue), "Show due dates relative to today, e.g. 'in 2d' or '3d ago' (default from $TODO_RELATIVE_DUE or the 'relative-due' setting)"),
+		color:    fs.String("color", "auto", "Colored output: always, never, auto (default: color when stdout is a terminal and NO_COLOR is unset)"),
+	}
+}
+
+func (f *tableFlags) options() (tasks.TableOptions, error) {
+	columns, err := tasks.ParseColumns(*f.columns)
+	if err != nil {
+		return tasks.TableOptions{}, err
+	}
+	style, err := tasks.ParseTableStyle(*f.style)
+	if err != nil {
+		return tasks.TableOptions{}, err
+	}
+	if *f.width < 0 {
+		return tasks.TableOptions{}, fmt.Errorf("invalid width: cannot be negative")
+	}
+	color, err := tasks.ParseColorMode(*f.color)
+	if err != nil {
+		return tasks.TableOptions{}, err
+	}
+	var theme tasks.Theme
+	if spec := configDefault(os.Getenv("TODO_THEME"), userConfig.Theme); spec != "" {
+		if theme, err = tasks.ParseTheme(spec); err != nil {
+			return tasks.TableOptions{}, err
+		}
+	}
+	opts := tasks.TableOptions{
+		Columns:     columns,
+		Style:       style,
+		Width:       *f.width,
+		Wrap:        *f.wrap,
+		RelativeDue: *f.relative,
+		Color:       color,
+		Theme:       theme,
+	}
+	return opts, nil
+}
+
+func tableOptionsFor(m tasks.Manager, opts tasks.TableOptions) tasks.TableOptions {
+	opts.Now = m.Now()
+	if opts.DateFormat == "" {
+		opts.DateFormat = userConfig.DateFormat
+	}
+	if opts.Width == 0 {
+		opts.Width = terminalWidth(os.Stdout)
+	}
+	if opts.Color == tasks.ColorAuto {
+		opts.Color = tasks.ColorNever
+		if os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout) {
+			opts.Color = tasks.ColorAlways
+		}
+	}
+	return opts
+}
+
+func isTerminal(f *os.File) bool {
+	return terminalWidth(f) > 0
+}
+
+// settingBool reads a boolean default from the environment variable key,
+// falling back to the configured value.
+func settingBool(key string, configured string) bool {
+	v, err := strconv.ParseBool(configDefault(os.Getenv(key), configured))
+	return err == nil && v
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !darwin
+
+package cli
+
+import (
+	"errors"
+	"os"
+)
+
+func terminalWidth(f *os.File) int {
+	return 0
+}
+
+func terminalSize(f *os.File) (int, int) {
+	return 0, 0
+}
+
+func makeRaw(f *os.File) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+func notifyResize(c chan<- os.Signal) {}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"strings"
 	"time"
 
 	"github.com/stevexciv/golang-todo-cli/tasks"
@@ -9,10 +10,13 @@ import (
 var testTime = time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
 
 type addCall struct {
-	title    string
-	priority tasks.Priority
-	dueDate  tasks.DueDate
-	category string
+	title       string
+	priority    tasks.Priority
+	dueDate     tasks.DueDate
+	category    string
+	description string
+	notes       string
+	tags        string
 }
 
 type completeCall struct {
@@ -31,7 +35,22 @@ type listCall struct {
 }
 
 type searchCall struct {
-	query string
+	query       string
+	mode        tasks.SearchMode
+	status      *tasks.Status
+	priority    *tasks.Priority
+	category    string
+	overdueOnly bool
+	limit       int
+}
+
+type updateCall struct {
+	id    int
+	patch tasks.TaskPatch
+}
+
+type queryCall struct {
+	filter string
 }
 
 type mockManager struct {
@@ -43,23 +62,43 @@ type mockManager struct {
 	completeNextErr error
 	deleteCalls     []deleteCall
 	deleteNextErr   error
+	getNextOk       *tasks.Task
+	getNextErr      error
+	updateCalls     []updateCall
+	updateNextOk    *tasks.Task
+	updateNextErr   error
 	listCalls       []listCall
 	listNextOk      []tasks.Task
 	listNextErr     error
 	searchCalls     []searchCall
 	searchNextOk    []tasks.Task
 	searchNextErr   error
+	queryCalls      []queryCall
+	queryNextOk     []tasks.Task
+	queryNextErr    error
+	savedViews      []tasks.View
+	getViewNextOk   *tasks.View
+	listViewsNextOk []tasks.View
+	deletedViews    []string
+	viewNextErr     error
 }
 
-func (m *mockManager) AddTask(title string, priority tasks.Priority, getDueDate func(time.Time) tasks.DueDate, category string) (*tasks.Task, error) {
+func (m *mockManager) AddTask(title string, priority tasks.Priority, getDueDate func(time.Time) tasks.DueDate, category string, opts ...tasks.TaskOption) (*tasks.Task, error) {
 	if m.addNextErr != nil {
 		return nil, m.addNextErr
 	}
+	var t tasks.Task
+	for _, opt := range opts {
+		opt(&t)
+	}
 	call := addCall{
-		title:    title,
-		priority: priority,
-		dueDate:  getDueDate(m.now),
-		category: category,
+		title:       title,
+		priority:    priority,
+		dueDate:     getDueDate(m.now),
+		category:    category,
+		description: t.Description,
+		notes:       strings.Join(t.Notes, "|"),
+		tags:        strings.Join(t.Tags, ","),
 	}
 	m.addCalls = append(m.addCalls, call)
 	return m.addNextOk, nil
@@ -87,6 +126,21 @@ func (m *mockManager) DeleteTask(id int) error {
 	return nil
 }
 
+func (m *mockManager) GetTask(id int) (*tasks.Task, error) {
+	if m.getNextErr != nil {
+		return nil, m.getNextErr
+	}
+	return m.getNextOk, nil
+}
+
+func (m *mockManager) UpdateTask(id int, patch tasks.TaskPatch) (*tasks.Task, error) {
+	if m.updateNextErr != nil {
+		return nil, m.updateNextErr
+	}
+	m.updateCalls = append(m.updateCalls, updateCall{id: id, patch: patch})
+	return m.updateNextOk, nil
+}
+
 func (m *mockManager) ListTasks(status *tasks.Status, priority *tasks.Priority, category string, overdueOnly bool) ([]tasks.Task, error) {
 	if m.listNextErr != nil {
 		return []tasks.Task{}, m.listNextErr
@@ -101,17 +155,68 @@ func (m *mockManager) ListTasks(status *tasks.Status, priority *tasks.Priority,
 	return m.listNextOk, nil
 }
 
-func (m *mockManager) SearchTasks(query string) ([]tasks.Task, error) {
+func (m *mockManager) SearchTasks(query string, opts tasks.SearchOptions) ([]tasks.Task, error) {
 	if m.searchNextErr != nil {
 		return []tasks.Task{}, m.searchNextErr
 	}
 	call := searchCall{
-		query: query,
+		query:       query,
+		mode:        opts.Mode,
+		status:      opts.Status,
+		priority:    opts.Priority,
+		category:    opts.Category,
+		overdueOnly: opts.OverdueOnly,
+		limit:       opts.Limit,
 	}
 	m.searchCalls = append(m.searchCalls, call)
 	return m.searchNextOk, nil
 }
 
+func (m *mockManager) QueryTasks(filter tasks.Filter) ([]tasks.Task, error) {
+	if m.queryNextErr != nil {
+		return []tasks.Task{}, m.queryNextErr
+	}
+	call := queryCall{
+		filter: filter.String(),
+	}
+	m.queryCalls = append(m.queryCalls, call)
+	return m.queryNextOk, nil
+}
+
+func (m *mockManager) SaveView(view tasks.View) error {
+	if m.viewNextErr != nil {
+		return m.viewNextErr
+	}
+	m.savedViews = append(m.savedViews, view)
+	return nil
+}
+
+func (m *mockManager) GetView(name string) (*tasks.View, error) {
+	if m.viewNextErr != nil {
+		return nil, m.viewNextErr
+	}
+	return m.getViewNextOk, nil
+}
+
+func (m *mockManager) ListViews() ([]tasks.View, error) {
+	if m.viewNextErr != nil {
+		return nil, m.viewNextErr
+	}
+	return m.listViewsNextOk, nil
+}
+
+func (m *mockManager) DeleteView(name string) error {
+	if m.viewNextErr != nil {
+		return m.viewNextErr
+	}
+	m.deletedViews = append(m.deletedViews, name)
+	return nil
+}
+
+func (m *mockManager) Now() time.Time {
+	return m.now
+}
+
 func (m *mockManager) resetAdd() {
 	m.addCalls = []addCall{}
 	m.addNextErr = nil
@@ -127,6 +232,14 @@ func (m *mockManager) resetDelete() {
 	m.deleteNextErr = nil
 }
 
+func (m *mockManager) resetUpdate() {
+	m.getNextOk = nil
+	m.getNextErr = nil
+	m.updateCalls = []updateCall{}
+	m.updateNextOk = nil
+	m.updateNextErr = nil
+}
+
 func (m *mockManager) resetList() {
 	m.listCalls = []listCall{}
 	m.listNextErr = nil
@@ -139,12 +252,29 @@ func (m *mockManager) resetSearch() {
 	m.searchNextOk = []tasks.Task{}
 }
 
+func (m *mockManager) resetQuery() {
+	m.queryCalls = []queryCall{}
+	m.queryNextErr = nil
+	m.queryNextOk = []tasks.Task{}
+}
+
+func (m *mockManager) resetViews() {
+	m.savedViews = []tasks.View{}
+	m.getViewNextOk = nil
+	m.listViewsNextOk = []tasks.View{}
+	m.deletedViews = []string{}
+	m.viewNextErr = nil
+}
+
 func (m *mockManager) reset() {
 	m.resetAdd()
 	m.resetComplete()
 	m.resetDelete()
+	m.resetUpdate()
 	m.resetList()
 	m.resetSearch()
+	m.resetQuery()
+	m.resetViews()
 }
 
 func newMockManager(now time.Time) mockManager {
@@ -0,0 +1,592 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type UICommand struct {
+	Filter string
+	Sort   string
+}
+
+func newUIFlagSet() (*flag.FlagSet, *uiFlags) {
+	fs := newFlagSet("ui")
+	f := &uiFlags{
+		filter: fs.String("filter", "", "Initial filter, using list flags and search words, e.g. '-status pending dentist'"),
+		sort:   fs.String("sort", "", "Initial sort key: id, title, priority, due, category, status (prefix with '-' to reverse)"),
+	}
+	alias(fs, "f", "filter")
+	alias(fs, "s", "sort")
+	return fs, f
+}
+
+type uiFlags struct {
+	filter *string
+	sort   *string
+}
+
+func parseUICmd(a []string) (Command, error) {
+	uiFlagSet, f := newUIFlagSet()
+	args, err := parseArgs(uiFlagSet, a)
+	if err != nil {
+		return flagError("ui", err)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("ui error: unexpected argument '%s'", args[0])
+	}
+	if _, err := parseUIFilter(*f.filter); err != nil {
+		return nil, fmt.Errorf("ui error: %v", err)
+	}
+	sortKey, err := tasks.ParseSortKey(*f.sort)
+	if err != nil {
+		return nil, fmt.Errorf("ui error: %v", err)
+	}
+	return &UICommand{Filter: *f.filter, Sort: sortKey.String()}, nil
+}
+
+func (u *UICommand) Execute(m tasks.Manager) (string, error) {
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("ui error: stdin is not a terminal: %v", err)
+	}
+	defer restore()
+
+	model := newUIModel(m, u.Filter, u.Sort)
+	keys := make(chan key)
+	errs := make(chan error, 1)
+	go func() {
+		in := bufio.NewReader(os.Stdin)
+		for {
+			k, err := readKey(in)
+			if err != nil {
+				errs <- err
+				return
+			}
+			keys <- k
+		}
+	}()
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+
+	out := bufio.NewWriter(os.Stdout)
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+		out.Flush()
+	}()
+	for !model.quit {
+		model.width, model.height = terminalSize(os.Stdout)
+		fmt.Fprint(out, "\x1b[H\x1b[2J"+strings.Join(model.view(), "\r\n"))
+		out.Flush()
+		select {
+		case k := <-keys:
+			model.handleKey(k)
+		case <-resize:
+		case err := <-errs:
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+type uiMode int
+
+const (
+	uiBrowse uiMode = iota
+	uiFilter
+	uiAdd
+	uiEdit
+	uiConfirmDelete
+	uiHelp
+)
+
+var uiPrompts = map[uiMode]string{
+	uiFilter: "Filter: ",
+	uiAdd:    "Add: ",
+	uiEdit:   "Edit: ",
+}
+
+type uiQuery struct {
+	status   *tasks.Status
+	priority *tasks.Priority
+	category string
+	overdue  bool
+	query    string
+}
+
+// parseUIFilter accepts the same flags as list; any remaining words are used
+// as a search query.
+func parseUIFilter(s string) (uiQuery, error) {
+	words, err := splitCommandLine(s)
+	if err != nil {
+		return uiQuery{}, err
+	}
+	fs, f := newListFlagSet()
+	args, err := parseArgs(fs, words)
+	if err != nil {
+		return uiQuery{}, err
+	}
+	filter := uiQuery{category: *f.category, overdue: *f.overdue, query: strings.Join(args, " ")}
+	if filter.priority, err = parsePriorityFilter(*f.priority); err != nil {
+		return uiQuery{}, err
+	}
+	if filter.status, err = parseStatusFilter(*f.status); err != nil {
+		return uiQuery{}, err
+	}
+	return filter, nil
+}
+
+type uiModel struct {
+	m          tasks.Manager
+	tasks      []tasks.Task
+	cursor     int
+	offset     int
+	filter     string
+	sortIndex  int
+	sortDesc   bool
+	mode       uiMode
+	input      lineEditor
+	editId     int
+	message    string
+	isError    bool
+	showDetail bool
+	width      int
+	height     int
+	quit       bool
+}
+
+var uiSortFields = append([]string{""}, tasks.SortFields...)
+
+func newUIModel(m tasks.Manager, filter string, sort string) *uiModel {
+	model := &uiModel{m: m, filter: filter, showDetail: true}
+	model.sortDesc = strings.HasPrefix(sort, "-")
+	model.sortIndex = max(0, slices.Index(uiSortFields, strings.TrimPrefix(sort, "-")))
+	model.reload(0)
+	return model
+}
+
+func (u *uiModel) selected() *tasks.Task {
+	if u.cursor < 0 || u.cursor >= len(u.tasks) {
+		return nil
+	}
+	return &u.tasks[u.cursor]
+}
+
+func (u *uiModel) sortKey() tasks.SortKey {
+	field := uiSortFields[u.sortIndex]
+	if u.sortDesc && field != "" {
+		field = "-" + field
+	}
+	k, _ := tasks.ParseSortKey(field)
+	return k
+}
+
+// reload fetches the tasks for the current filter and sort, keeping the cursor
+// on the task with the given ID when it is still visible.
+func (u *uiModel) reload(selectId int) {
+	filter, err := parseUIFilter(u.filter)
+	if err != nil {
+		u.setError(err)
+		return
+	}
+	var list []tasks.Task
+	if filter.query != "" {
+		list, err = u.m.SearchTasks(filter.query, tasks.SearchOptions{
+			Status:      filter.status,
+			Priority:    filter.priority,
+			Category:    filter.category,
+			OverdueOnly: filter.overdue,
+		})
+	} else {
+		list, err = u.m.ListTasks(filter.status, filter.priority, filter.category, filter.overdue)
+	}
+	if err != nil {
+		u.setError(err)
+		return
+	}
+	if uiSortFields[u.sortIndex] != "" {
+		tasks.SortTasks(list, u.sortKey())
+	}
+	u.tasks = list
+	if i := slices.IndexFunc(list, func(t tasks.Task) bool { return t.Id == selectId }); i >= 0 {
+		u.cursor = i
+	}
+	u.cursor = max(0, min(u.cursor, len(list)-1))
+}
+
+func (u *uiModel) selectedId() int {
+	if t := u.selected(); t != nil {
+		return t.Id
+	}
+	return 0
+}
+
+func (u *uiModel) setMessage(msg string) {
+	u.message, u.isError = msg, false
+}
+
+func (u *uiModel) setError(err error) {
+	u.message, u.isError = "Error: "+err.Error(), true
+}
+
+func (u *uiModel) handleKey(k key) {
+	if k == keyCtrlC {
+		u.quit = true
+		return
+	}
+	switch u.mode {
+	case uiFilter, uiAdd, uiEdit:
+		u.handleInputKey(k)
+	case uiConfirmDelete:
+		if t := u.selected(); t != nil && (k == "y" || k == "Y") {
+			u.run(&DeleteCommand{Id: t.Id}, 0)
+		} else {
+			u.setMessage("Delete cancelled")
+		}
+		u.mode = uiBrowse
+	case uiHelp:
+		u.mode = uiBrowse
+	default:
+		u.handleBrowseKey(k)
+	}
+}
+
+func (u *uiModel) handleBrowseKey(k key) {
+	u.message = ""
+	switch k {
+	case "q", keyEsc:
+		u.quit = true
+	case "j", keyDown:
+		u.cursor = min(u.cursor+1, len(u.tasks)-1)
+	case "k", keyUp:
+		u.cursor = max(u.cursor-1, 0)
+	case "g", keyHome:
+		u.cursor = 0
+	case "G", keyEnd:
+		u.cursor = max(len(u.tasks)-1, 0)
+	case keyPageDown, keyCtrlD:
+		u.cursor = min(u.cursor+u.listHeight(), len(u.tasks)-1)
+	case keyPageUp:
+		u.cursor = max(u.cursor-u.listHeight(), 0)
+	case keyEnter, keyTab:
+		u.showDetail = !u.showDetail
+	case "/":
+		u.mode = uiFilter
+		u.input.set(u.filter)
+	case "a":
+		u.mode = uiAdd
+		u.input.set("")
+	case "e":
+		if t := u.selected(); t != nil {
+			u.mode = uiEdit
+			u.editId = t.Id
+			u.input.set(editLine(t))
+		}
+	case "c", " ":
+		if t := u.selected(); t != nil {
+			if t.Status == tasks.Completed {
+				pending := tasks.Pending
+				u.run(&EditCommand{Id: t.Id, Status: &pending}, t.Id)
+			} else {
+				u.run(&CompleteCommand{Id: t.Id}, t.Id)
+			}
+		}
+	case "d", keyDelete:
+		if t := u.selected(); t != nil {
+			u.mode = uiConfirmDelete
+		}
+	case "s":
+		u.sortIndex = (u.sortIndex + 1) % len(uiSortFields)
+		u.reload(u.selectedId())
+	case "S":
+		u.sortDesc = !u.sortDesc
+		u.reload(u.selectedId())
+	case "r", keyCtrlL:
+		u.reload(u.selectedId())
+	case "?":
+		u.mode = uiHelp
+	}
+}
+
+func (u *uiModel) handleInputKey(k key) {
+	switch k {
+	case keyEsc:
+		u.mode = uiBrowse
+		u.setMessage("")
+		return
+	case keyEnter:
+	default:
+		u.input.handle(k)
+		return
+	}
+
+	line := u.input.commit()
+	switch u.mode {
+	case uiFilter:
+		if _, err := parseUIFilter(line); err != nil {
+			u.input.set(line)
+			u.setError(err)
+			return
+		}
+		u.filter = line
+		u.reload(u.selectedId())
+		u.setMessage("")
+	case uiAdd, uiEdit:
+		words, err := splitCommandLine(line)
+		if err != nil {
+			u.input.set(line)
+			u.setError(err)
+			return
+		}
+		var cmd Command
+		if u.mode == uiAdd {
+			cmd, err = parseAddCmd(words)
+		} else {
+			cmd, err = parseEditCmd(append([]string{fmt.Sprint(u.editId)}, words...))
+		}
+		if err != nil {
+			u.input.set(line)
+			u.setError(err)
+			return
+		}
+		selectId := u.editId
+		if u.mode == uiAdd {
+			selectId = 0
+		}
+		if !u.run(cmd, selectId) {
+			u.input.set(line)
+			return
+		}
+		if u.mode == uiAdd {
+			u.selectNewest()
+		}
+	}
+	u.mode = uiBrowse
+}
+
+// run executes a command, reloads the list and reports whether it succeeded.
+func (u *uiModel) run(cmd Command, selectId int) bool {
+	res, err := cmd.Execute(u.m)
+	if err != nil {
+		u.setError(err)
+		return false
+	}
+	u.reload(selectId)
+	u.setMessage(res)
+	return true
+}
+
+func (u *uiModel) selectNewest() {
+	newest := -1
+	for i, t := range u.tasks {
+		if newest < 0 || t.Id > u.tasks[newest].Id {
+			newest = i
+		}
+	}
+	if newest >= 0 {
+		u.cursor = newest
+	}
+}
+
+func editLine(t *tasks.Task) string {
+	parts := []string{
+		"--title " + quoteArg(t.Title),
+		"-p " + strings.ToLower(t.Priority.String()),
+		"-d " + time.Time(t.DueDate).Format("2006-01-02"),
+		"-c " + quoteArg(t.Category),
+		"-t " + quoteArg(strings.Join(t.Tags, ",")),
+		"--description " + quoteArg(t.Description),
+	}
+	return strings.Join(parts, " ")
+}
+
+const uiHelpText = `Keys
+  j, ↓ / k, ↑        move down / up
+  g, Home / G, End   first / last task
+  PgDn / PgUp        page down / up
+  Enter, Tab         toggle the detail pane
+  /                  filter, e.g. -status pending -p high dentist
+  a                  add a task, e.g. Buy milk -p high -d tomorrow
+  e                  edit the selected task
+  c, Space           complete or reopen the selected task
+  d, Delete          delete the selected task
+  s / S              cycle the sort key / reverse the sort
+  r                  reload
+  q, Esc             quit
+
+In prompts: Enter applies, Esc cancels, ↑/↓ recall earlier input.`
+
+func (u *uiModel) listHeight() int {
+	return max(1, u.height-5-u.detailHeight())
+}
+
+func (u *uiModel) detailHeight() int {
+	if !u.showDetail || u.selected() == nil {
+		return 0
+	}
+	return min(len(u.detailLines())+1, u.height/2)
+}
+
+func (u *uiModel) detailLines() []string {
+	t := u.selected()
+	if t == nil {
+		return nil
+	}
+	lines := []string{
+		fmt.Sprintf("#%d %s", t.Id, t.Title),
+		fmt.Sprintf("Status: %s   Priority: %s   Due: %s (%s)", t.Status.String(), t.Priority.String(), time.Time(t.DueDate).Format("2006-01-02"), tasks.RelativeDue(t.DueDate, u.m.Now())),
+	}
+	if t.Category != "" {
+		lines = append(lines, "Category: "+t.Category)
+	}
+	if len(t.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(t.Tags, ", "))
+	}
+	if t.Description != "" {
+		lines = append(lines, "")
+		lines = append(lines, wrapWords(t.Description, max(10, u.width-2))...)
+	}
+	for _, note := range t.Notes {
+		lines = append(lines, "- "+note)
+	}
+	return lines
+}
+
+func wrapWords(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, line)
+}
+
+// view renders the whole screen as exactly height lines.
+func (u *uiModel) view() []string {
+	if u.width <= 0 || u.height <= 0 {
+		u.width, u.height = 80, 24
+	}
+	if u.mode == uiHelp {
+		return u.fit(strings.Split(uiHelpText, "\n"), nil)
+	}
+
+	sortName := uiSortFields[u.sortIndex]
+	if sortName == "" {
+		sortName = "default"
+	}
+	if u.sortDesc {
+		sortName += " ↓"
+	}
+	header := fmt.Sprintf(" todo ui — %d task(s) — sort: %s", len(u.tasks), sortName)
+	if u.filter != "" {
+		header += " — filter: " + u.filter
+	}
+	lines := []string{reverse(pad(header, u.width))}
+
+	if prompt, ok := uiPrompts[u.mode]; ok {
+		lines = append(lines, prompt+u.input.render())
+	} else if u.mode == uiConfirmDelete {
+		lines = append(lines, fmt.Sprintf("Delete task %d? (y/N)", u.selectedId()))
+	} else {
+		lines = append(lines, "Filter: "+u.filter)
+	}
+
+	table := strings.Split(strings.TrimRight(tasks.RenderTableWith(u.tasks, tasks.TableOptions{
+		Columns: []string{"id", "priority", "due", "status", "title"},
+		Style:   tasks.StyleCompact,
+		Width:   u.width,
+		Now:     u.m.Now(),
+		Color:   tasks.ColorNever,
+	}), "\n"), "\n")
+	lines = append(lines, bold(table[0]))
+
+	rows := table[1:]
+	height := u.listHeight()
+	if u.cursor < u.offset {
+		u.offset = u.cursor
+	}
+	if u.cursor >= u.offset+height {
+		u.offset = u.cursor - height + 1
+	}
+	u.offset = max(0, min(u.offset, len(rows)-height))
+	for i := u.offset; i < u.offset+height; i++ {
+		switch {
+		case i >= len(rows):
+			lines = append(lines, "")
+		case i == u.cursor:
+			lines = append(lines, reverse(pad(rows[i], u.width)))
+		case u.tasks[i].Status == tasks.Completed:
+			lines = append(lines, "\x1b[2m"+rows[i]+"\x1b[0m")
+		default:
+			lines = append(lines, rows[i])
+		}
+	}
+	if len(u.tasks) == 0 {
+		lines[3] = "No tasks found. Press 'a' to add one or '/' to change the filter."
+	}
+
+	if h := u.detailHeight(); h > 0 {
+		lines = append(lines, strings.Repeat("─", u.width))
+		lines = append(lines, u.detailLines()[:h-1]...)
+	}
+
+	footer := []string{u.message, "↑↓ move  / filter  a add  e edit  c complete  d delete  s sort  ⏎ details  ? help  q quit"}
+	if u.isError {
+		footer[0] = "\x1b[31m" + u.message + "\x1b[0m"
+	}
+	return u.fit(lines, footer)
+}
+
+// fit pads or trims body so that body plus footer fill the screen exactly.
+func (u *uiModel) fit(body []string, footer []string) []string {
+	n := max(0, u.height-len(footer))
+	if len(body) > n {
+		body = body[:n]
+	}
+	for len(body) < n {
+		body = append(body, "")
+	}
+	lines := append(body, footer...)
+	for i, line := range lines {
+		if !strings.Contains(line, "\x1b[") {
+			lines[i] = truncateDisplay(line, u.width)
+		}
+	}
+	return lines[:min(len(lines), u.height)]
+}
+
+func truncateDisplay(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
+
+func pad(s string, width int) string {
+	s = truncateDisplay(s, width)
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func reverse(s string) string {
+	return "\x1b[7m" + s + "\x1b[0m"
+}
+
+func bold(s string) string {
+	return "\x1b[1m" + s + "\x1b[0m"
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+var uiTestTasks = []tasks.Task{
+	{Id: 1, Title: "Call dentist", Priority: tasks.High, Category: "health", Status: tasks.Pending, Description: "Book a cleaning"},
+	{Id: 2, Title: "Buy milk", Priority: tasks.Low, Status: tasks.Completed, Tags: []string{"errand"}},
+	{Id: 3, Title: "Amend taxes", Priority: tasks.Medium, Status: tasks.Pending},
+}
+
+func newTestUIModel(m *mockManager) *uiModel {
+	m.reset()
+	m.listNextOk = slices.Clone(uiTestTasks)
+	m.searchNextOk = slices.Clone(uiTestTasks[:1])
+	m.updateNextOk = &uiTestTasks[1]
+	model := newUIModel(m, "", "")
+	model.width, model.height = 80, 20
+	return model
+}
+
+func typeKeys(model *uiModel, keys ...key) {
+	for _, k := range keys {
+		model.handleKey(k)
+	}
+}
+
+func typeText(model *uiModel, s string) {
+	for _, r := range s {
+		model.handleKey(key(string(r)))
+	}
+}
+
+func TestUIModelNavigation(t *testing.T) {
+	m := newMockManager(testTime)
+	model := newTestUIModel(&m)
+
+	screen := model.view()
+	if len(screen) != model.height {
+		t.Fatalf("expected %d lines, got %d", model.height, len(screen))
+	}
+	if !strings.Contains(screen[0], "3 task(s)") {
+		t.Fatalf("expected header with task count, got %q", screen[0])
+	}
+	if !strings.Contains(screen[3], "\x1b[7m") || !strings.Contains(screen[3], "Call dentist") {
+		t.Fatalf("expected first task to be selected, got %q", screen[3])
+	}
+	if !strings.Contains(strings.Join(screen, "\n"), "Book a cleaning") {
+		t.Fatalf("expected detail pane with description, got:\n%v", strings.Join(screen, "\n"))
+	}
+
+	typeKeys(model, "j", keyDown, keyDown)
+	if model.selectedId() != 3 {
+		t.Fatalf("expected cursor to stop on the last task, got %d", model.selectedId())
+	}
+	typeKeys(model, "g")
+	if model.selectedId() != 1 {
+		t.Fatalf("expected cursor on first task, got %d", model.selectedId())
+	}
+
+	typeKeys(model, keyEnter)
+	if strings.Contains(strings.Join(model.view(), "\n"), "Book a cleaning") {
+		t.Fatalf("expected detail pane to be hidden")
+	}
+
+	// sorting by title keeps the selection on the same task
+	typeKeys(model, "s", "s")
+	if model.sortKey().String() != "title" || model.tasks[0].Title != "Amend taxes" {
+		t.Fatalf("expected tasks sorted by title, got %v", model.tasks)
+	}
+	if model.selectedId() != 1 {
+		t.Fatalf("expected selection to follow task 1, got %d", model.selectedId())
+	}
+	typeKeys(model, "S")
+	if model.sortKey().String() != "-title" || model.tasks[0].Title != "Call dentist" {
+		t.Fatalf("expected tasks reverse sorted by title, got %v", model.tasks)
+	}
+
+	typeKeys(model, "q")
+	if !model.quit {
+		t.Fatalf("expected q to quit")
+	}
+}
+
+func TestUIModelActions(t *testing.T) {
+	m := newMockManager(testTime)
+
+	t.Run("complete", func(t *testing.T) {
+		model := newTestUIModel(&m)
+		typeKeys(model, " ")
+		if !slices.Contains(m.completeCalls, completeCall{id: 1}) {
+			t.Fatalf("expected task 1 to be completed, got %v", m.completeCalls)
+		}
+		if !strings.Contains(model.message, "Task completed successfully") {
+			t.Fatalf("expected result message, got %q", model.message)
+		}
+	})
+
+	t.Run("reopen", func(t *testing.T) {
+		model := newTestUIModel(&m)
+		typeKeys(model, "j", "c")
+		if len(m.updateCalls) != 1 || m.updateCalls[0].id != 2 || *m.updateCalls[0].patch.Status != tasks.Pending {
+			t.Fatalf("expected task 2 to be reopened, got %v", m.updateCalls)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		model := newTestUIModel(&m)
+		typeKeys(model, "d", "n")
+		if len(m.deleteCalls) != 0 {
+			t.Fatalf("expected delete to be cancelled, got %v", m.deleteCalls)
+		}
+		typeKeys(model, "d")
+		if !strings.Contains(model.view()[1], "Delete task 1? (y/N)") {
+			t.Fatalf("expected confirmation prompt, got %q", model.view()[1])
+		}
+		typeKeys(model, "y")
+		if !slices.Contains(m.deleteCalls, deleteCall{id: 1}) {
+			t.Fatalf("expected task 1 to be deleted, got %v", m.deleteCalls)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		model := newTestUIModel(&m)
+		typeKeys(model, "/")
+		typeText(model, "-s maybe")
+		typeKeys(model, keyEnter)
+		if model.mode != uiFilter || !model.isError {
+			t.Fatalf("expected invalid filter to keep the prompt open with an error, got mode %v, message %q", model.mode, model.message)
+		}
+		typeKeys(model, keyCtrlU)
+		typeText(model, "-s pending dentist")
+		typeKeys(model, keyEnter)
+		want := searchCall{query: "dentist", status: &[]tasks.Status{tasks.Pending}[0]}
+		if len(m.searchCalls) != 1 || m.searchCalls[0].query != want.query || *m.searchCalls[0].status != tasks.Pending {
+			t.Fatalf("expected search call %v, got %v", want, m.searchCalls)
+		}
+		if model.mode != uiBrowse || len(model.tasks) != 1 || !strings.Contains(model.view()[0], "filter: -s pending dentist") {
+			t.Fatalf("expected filtered list, got %v", model.tasks)
+		}
+	})
+
+	t.Run("add", func(t *testing.T) {
+		model := newTestUIModel(&m)
+		m.addNextOk = &uiTestTasks[2]
+		typeKeys(model, "a")
+		typeText(model, "'Buy oat milk' -p high -c errands")
+		typeKeys(model, keyEnter)
+		if len(m.addCalls) != 1 || m.addCalls[0].title != "Buy oat milk" || m.addCalls[0].priority != tasks.High || m.addCalls[0].category != "errands" {
+			t.Fatalf("unexpected add calls: %v", m.addCalls)
+		}
+		if model.mode != uiBrowse || model.selectedId() != 3 {
+			t.Fatalf("expected newest task to be selected, got mode %v, id %d", model.mode, model.selectedId())
+		}
+	})
+
+	t.Run("edit", func(t *testing.T) {
+		model := newTestUIModel(&m)
+		typeKeys(model, "e")
+		if got := model.input.String(); got != "--title 'Call dentist' -p high -d 0001-01-01 -c health -t '' --description 'Book a cleaning'" {
+			t.Fatalf("unexpected edit line: %q", got)
+		}
+		typeKeys(model, keyCtrlU)
+		typeText(model, "-p low --title Rebook")
+		typeKeys(model, keyEnter)
+		if len(m.updateCalls) != 1 || m.updateCalls[0].id != 1 || *m.updateCalls[0].patch.Title != "Rebook" || *m.updateCalls[0].patch.Priority != tasks.Low {
+			t.Fatalf("unexpected update calls: %v", m.updateCalls)
+		}
+		if model.mode != uiBrowse {
+			t.Fatalf("expected to return to browsing, got mode %v", model.mode)
+		}
+	})
+
+	t.Run("escape cancels input", func(t *testing.T) {
+		model := newTestUIModel(&m)
+		typeKeys(model, "a")
+		typeText(model, "Half typed")
+		typeKeys(model, keyEsc)
+		if model.mode != uiBrowse || len(m.addCalls) != 0 {
+			t.Fatalf("expected add to be cancelled, got mode %v, calls %v", model.mode, m.addCalls)
+		}
+	})
+}
+
+func TestParseUITableDriven(t *testing.T) {
+	args := []string{"ui", "--filter", "-status pending", "-s", "-due"}
+	cmd, err := Parse(&args)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if want := (&UICommand{Filter: "-status pending", Sort: "-due"}); *cmd.(*UICommand) != *want {
+		t.Fatalf("unexpected command: wanted=%v, got=%v", want, cmd)
+	}
+
+	for _, invalid := range [][]string{
+		{"ui", "--filter", "-status maybe"},
+		{"ui", "--sort", "colour"},
+		{"ui", "extra"},
+	} {
+		if cmd, err := Parse(&invalid); err == nil {
+			t.Fatalf("expected err for %v, got %v", invalid, cmd)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+type ViewSaveCommand struct {
+	View tasks.View
+}
+
+type ViewListCommand struct{}
+
+type ViewDeleteCommand struct {
+	Name string
+}
+
+type ViewRunCommand struct {
+	Name  string
+	Table tasks.TableOptions
+}
+
+func (v *ViewSaveCommand) Execute(m tasks.Manager) (string, error) {
+	if err := m.SaveView(v.View); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Saved view '%s'", v.View.Name), nil
+}
+
+func (v *ViewListCommand) Execute(m tasks.Manager) (string, error) {
+	views, err := m.ListViews()
+	if err != nil {
+		return "", err
+	}
+	if len(views) == 0 {
+		return "No saved views", nil
+	}
+	return tasks.RenderViews(views), nil
+}
+
+func (v *ViewDeleteCommand) Execute(m tasks.Manager) (string, error) {
+	if err := m.DeleteView(v.Name); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Deleted view '%s'", v.Name), nil
+}
+
+func (v *ViewRunCommand) Execute(m tasks.Manager) (string, error) {
+	view, err := m.GetView(v.Name)
+	if err != nil {
+		return "", err
+	}
+	filter, err := tasks.ParseFilter(view.Query)
+	if err != nil {
+		return "", fmt.Errorf("view '%s': %v", view.Name, err)
+	}
+	sortKey, err := tasks.ParseSortKey(view.Sort)
+	if err != nil {
+		return "", fmt.Errorf("view '%s': %v", view.Name, err)
+	}
+	t, err := m.QueryTasks(filter)
+	if err != nil {
+		return "", err
+	}
+	tasks.SortTasks(t, sortKey)
+	opts := tableOptionsFor(m, v.Table)
+	if len(opts.Columns) == 0 {
+		opts.Columns = view.Columns
+	}
+	return renderTasks(t, view.Format, opts)
+}
+
+// renderTasks renders the tasks in format, or when it is empty in the
+// configured output format.
+func renderTasks(t []tasks.Task, format string, opts tasks.TableOptions) (string, error) {
+	switch outputFormat(format) {
+	case "table":
+		return tasks.RenderTableWith(t, opts), nil
+	case "json":
+		if t == nil {
+			t = []tasks.Task{}
+		}
+		return tasks.RenderJSON(t)
+	}
+	return "", fmt.Errorf("invalid output format '%s': must be 'table' or 'json'", format)
+}
+
+func outputFormat(format string) string {
+	return configDefault(format, configDefault(userConfig.Output, "table"))
+}
+
+// parseOutputFormat checks a -format value for task lists. An empty one
+// means the configured output format.
+func parseOutputFormat(s string) (string, error) {
+	format := strings.ToLower(s)
+	if format != "" && format != "table" && format != "json" {
+		return "", fmt.Errorf("invalid output format '%s': must be 'table' or 'json'", s)
+	}
+	return format, nil
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stevexciv/golang-todo-cli/tasks"
+)
+
+func TestViewSaveExecuteTableDriven(t *testing.T) {
+	m := newMockManager(testTime)
+	tests := []struct {
+		name    string
+		viewCmd ViewSaveCommand
+		want    string
+	}{
+		{
+			name: "save view",
+			viewCmd: ViewSaveCommand{
+				View: tasks.View{Name: "today", Query: "status:pending and due<=today", Sort: "priority"},
+			},
+			want: "Saved view 'today'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.reset()
+
+			got, err := tt.viewCmd.Execute(&m)
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(m.savedViews) != 1 || !reflect.DeepEqual(m.savedViews[0], tt.viewCmd.View) {
+				t.Fatalf("unexpected saved views: wanted=%v, got=%v", tt.viewCmd.View, m.savedViews)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Fatalf("expected output to contain '%v', got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestViewRunExecuteTableDriven(t *testing.T) {
+	m := newMockManager(testTime)
+	tests := []struct {
+		name       string
+		view       tasks.View
+		mockReturn []tasks.Task
+		wantCall   queryCall
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:       "run table view",
+			view:       tasks.View{Name: "today", Query: "status:pending", Sort: "priority"},
+			mockReturn: []tasks.Task{{Id: 1, Title: "Task 1", Priority: tasks.Low}, {Id: 2, Title: "Task 2", Priority: tasks.High}},
+			wantCall:   queryCall{filter: "status:pending"},
+			want:       []string{"Task 2", "Task 1"},
+		},
+		{
+			name:       "run view with columns",
+			view:       tasks.View{Name: "short", Query: "", Columns: []string{"id", "title"}},
+			mockReturn: []tasks.Task{{Id: 1, Title: "Task 1"}},
+			wantCall:   queryCall{filter: ""},
+			want:       []string{"Task 1"},
+			notWant:    []string{"Priority"},
+		},
+		{
+			name:       "run json view",
+			view:       tasks.View{Name: "json", Query: "priority>=medium", Format: "json"},
+			mockReturn: []tasks.Task{{Id: 1, Title: "Task 1"}},
+			wantCall:   queryCall{filter: "priority>=medium"},
+			want:       []string{`"title": "Task 1"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.reset()
+			m.getViewNextOk = &tt.view
+			m.queryNextOk = tt.mockReturn
+
+			got, err := (&ViewRunCommand{Name: tt.view.Name}).Execute(&m)
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !slices.Contains(m.queryCalls, tt.wantCall) {
+				t.Fatalf("missing expected call: wanted=%v, got=%v", tt.wantCall, m.queryCalls)
+			}
+			last := -1
+			for _, w := range tt.want {
+				i := strings.Index(got, w)
+				if i < 0 {
+					t.Fatalf("expected output to contain '%v', got: %v", w, got)
+				}
+				if i < last {
+					t.Fatalf("expected '%v' to appear in order, got: %v", w, got)
+				}
+				last = i
+			}
+			for _, nw := range tt.notWant {
+				if strings.Contains(got, nw) {
+					t.Fatalf("expected output not to contain '%v', got: %v", nw, got)
+				}
+			}
+		})
+	}
+}
+
+func TestViewListExecute(t *testing.T) {
+	m := newMockManager(testTime)
+	m.reset()
+
+	got, err := (&ViewListCommand{}).Execute(&m)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(got, "No saved views") {
+		t.Fatalf("expected empty message, got: %v", got)
+	}
+
+	m.listViewsNextOk = []tasks.View{{Name: "today", Query: "due<=today"}}
+	got, err = (&ViewListCommand{}).Execute(&m)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(got, "today") || !strings.Contains(got, "due<=today") {
+		t.Fatalf("expected view in output, got: %v", got)
+	}
+}
+
+func TestViewExecuteErrorsTableDriven(t *testing.T) {
+	m := newMockManager(testTime)
+	tests := []struct {
+		name    string
+		viewCmd Command
+		mockErr error
+		wantErr string
+	}{
+		{
+			name:    "save error",
+			viewCmd: &ViewSaveCommand{View: tasks.View{Name: "today"}},
+			mockErr: errors.New("failed to save view"),
+			wantErr: "failed to save view",
+		},
+		{
+			name:    "run unknown view",
+			viewCmd: &ViewRunCommand{Name: "missing"},
+			mockErr: errors.New("view 'missing' not found"),
+			wantErr: "not found",
+		},
+		{
+			name:    "delete unknown view",
+			viewCmd: &ViewDeleteCommand{Name: "missing"},
+			mockErr: errors.New("view 'missing' not found"),
+			wantErr: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.reset()
+			m.viewNextErr = tt.mockErr
+
+			_, err := tt.viewCmd.Execute(&m)
+
+			if err == nil {
+				t.Fatalf("expected error, got none")
+			}
+			if !strings.Contains(strings.ToLower(err.Error()), strings.ToLower(tt.wantErr)) {
+				t.Fatalf("expected error to contain '%v', got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
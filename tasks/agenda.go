@@ -0,0 +1,68 @@
+package tasks
+
+import (
+	"slices"
+	"time"
+)
+
+var AgendaBuckets = []string{"Overdue", "Today", "Tomorrow", "This week", "Later"}
+
+type AgendaBucket struct {
+	Name  string
+	Tasks []Task
+}
+
+// StartOfWeek returns the first day of the week containing t.
+func StartOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	d := startOfDay(t)
+	return d.AddDate(0, 0, -((int(d.Weekday()) - int(weekStart) + 7) % 7))
+}
+
+// Agenda groups pending tasks by due date into AgendaBuckets. "Overdue" holds
+// the tasks IsOverdue reports, and "This week" holds the days after tomorrow
+// up to the end of the current week. Every
+// bucket is returned, sorted by due date, then priority and id.
+func Agenda(t []Task, now time.Time, weekStart time.Weekday) []AgendaBucket {
+	today := startOfDay(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	endOfWeek := StartOfWeek(today, weekStart).AddDate(0, 0, 7)
+
+	buckets := make([]AgendaBucket, len(AgendaBuckets))
+	for i, name := range AgendaBuckets {
+		buckets[i].Name = name
+	}
+	for _, task := range t {
+		if task.Status == Completed {
+			continue
+		}
+		due := startOfDay(time.Time(task.DueDate))
+		var i int
+		switch {
+		case IsOverdue(&task, now):
+			i = 0
+		case due.Equal(today):
+			i = 1
+		case due.Equal(tomorrow):
+			i = 2
+		case due.Before(endOfWeek):
+			i = 3
+		default:
+			i = 4
+		}
+		buckets[i].Tasks = append(buckets[i].Tasks, task)
+	}
+	for i := range buckets {
+		slices.SortStableFunc(buckets[i].Tasks, compareByDay)
+	}
+	return buckets
+}
+
+func compareByDay(a, b Task) int {
+	if c := startOfDay(time.Time(a.DueDate)).Compare(startOfDay(time.Time(b.DueDate))); c != 0 {
+		return c
+	}
+	if a.Priority != b.Priority {
+		return int(b.Priority) - int(a.Priority)
+	}
+	return a.Id - b.Id
+}
@@ -0,0 +1,94 @@
+package tasks
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAgendaTableDriven(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 4, 10, 15, 30, 0, 0, time.UTC)
+	day := func(offset int) DueDate { return DueDate(now.AddDate(0, 0, offset)) }
+	all := []Task{
+		{Id: 1, Title: "Late", DueDate: day(-2), Status: Pending},
+		{Id: 2, Title: "Done late", DueDate: day(-1), Status: Completed},
+		{Id: 3, Title: "Today low", DueDate: day(0), Priority: Low, Status: Pending},
+		{Id: 4, Title: "Today high", DueDate: day(0), Priority: High, Status: Pending},
+		{Id: 5, Title: "Tomorrow", DueDate: day(1), Status: Pending},
+		{Id: 6, Title: "Saturday", DueDate: day(3), Status: Pending},
+		{Id: 7, Title: "Sunday", DueDate: day(4), Status: Pending},
+		{Id: 8, Title: "Next week", DueDate: day(6), Status: Pending},
+		{Id: 9, Title: "Earlier today", DueDate: DueDate(now.Add(-2 * time.Hour)), Status: Pending},
+	}
+	tests := []struct {
+		name      string
+		weekStart time.Weekday
+		want      map[string][]int
+	}{
+		{
+			name:      "week starting monday",
+			weekStart: time.Monday,
+			want: map[string][]int{
+				"Overdue":   {1, 9},
+				"Today":     {4, 3},
+				"Tomorrow":  {5},
+				"This week": {6, 7},
+				"Later":     {8},
+			},
+		},
+		{
+			name:      "week starting sunday",
+			weekStart: time.Sunday,
+			want: map[string][]int{
+				"Overdue":   {1, 9},
+				"Today":     {4, 3},
+				"Tomorrow":  {5},
+				"This week": {6},
+				"Later":     {7, 8},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := Agenda(all, now, tt.weekStart)
+			if len(buckets) != len(AgendaBuckets) {
+				t.Fatalf("expected %d buckets, got %d", len(AgendaBuckets), len(buckets))
+			}
+			for i, bucket := range buckets {
+				if bucket.Name != AgendaBuckets[i] {
+					t.Fatalf("expected bucket %q, got %q", AgendaBuckets[i], bucket.Name)
+				}
+				ids := []int{}
+				for _, task := range bucket.Tasks {
+					ids = append(ids, task.Id)
+				}
+				if !slices.Equal(ids, tt.want[bucket.Name]) {
+					t.Fatalf("expected %s to hold %v, got %v", bucket.Name, tt.want[bucket.Name], ids)
+				}
+			}
+		})
+	}
+}
+
+func TestStartOfWeekTableDriven(t *testing.T) {
+	tests := []struct {
+		day       time.Time
+		weekStart time.Weekday
+		want      time.Time
+	}{
+		{time.Date(2024, 4, 10, 9, 0, 0, 0, time.UTC), time.Monday, time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 4, 10, 9, 0, 0, 0, time.UTC), time.Sunday, time.Date(2024, 4, 7, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC), time.Monday, time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 4, 7, 0, 0, 0, 0, time.UTC), time.Monday, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.day.Format("Mon 2006-01-02")+" "+tt.weekStart.String(), func(t *testing.T) {
+			if got := StartOfWeek(tt.day, tt.weekStart); !got.Equal(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
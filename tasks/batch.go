@@ -0,0 +1,80 @@
+package tasks
+
+import "slices"
+
+// Batcher is implemented by managers that can hold changes in memory and
+// write them to disk all at once.
+type Batcher interface {
+	Begin()
+	Commit() error
+	Rollback()
+	Pending() int
+	InBatch() bool
+}
+
+type batch struct {
+	tasks   []Task
+	views   []View
+	nextId  int
+	changes int
+}
+
+// Begin starts a batch; until Commit or Rollback, changes are kept in memory
+// only. Calling Begin during a batch has no effect.
+func (m *manager) Begin() {
+	if m.batch != nil {
+		return
+	}
+	m.batch = &batch{
+		tasks:  slices.Clone(m.tasks),
+		views:  slices.Clone(m.views),
+		nextId: m.nextId,
+	}
+}
+
+// Commit writes the changes made since Begin and ends the batch. If writing
+// fails, the batch stays open so the caller can retry or roll back.
+func (m *manager) Commit() error {
+	b := m.batch
+	if b == nil {
+		return nil
+	}
+	m.batch = nil
+	if b.changes == 0 {
+		return nil
+	}
+	if err := m.saveToFile(); err != nil {
+		m.batch = b
+		return err
+	}
+	if err := m.saveViewsToFile(); err != nil {
+		m.batch = b
+		return err
+	}
+	return nil
+}
+
+// Rollback discards the changes made since Begin and ends the batch.
+func (m *manager) Rollback() {
+	if m.batch == nil {
+		return
+	}
+	m.tasks = m.batch.tasks
+	m.views = m.batch.views
+	m.nextId = m.batch.nextId
+	m.index = nil
+	m.batch = nil
+}
+
+// Pending returns the number of changes made since Begin.
+func (m *manager) Pending() int {
+	if m.batch == nil {
+		return 0
+	}
+	return m.batch.changes
+}
+
+// InBatch reports whether a batch is open.
+func (m *manager) InBatch() bool {
+	return m.batch != nil
+}
@@ -0,0 +1,107 @@
+package tasks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type CalendarOptions struct {
+	WeekStart time.Weekday
+	Width     int
+	Counts    bool
+	Now       time.Time
+}
+
+// calendarTitleLines is the number of task titles shown per day before
+// the rest are summarized as "+N more".
+const calendarTitleLines = 3
+
+// RenderCalendar draws the month containing month as a grid of weeks, with
+// the titles or number of tasks due on each day. Today is shown as "[19]".
+func RenderCalendar(t []Task, month time.Time, opts CalendarOptions) string {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	next := first.AddDate(0, 1, 0)
+	byDay := map[time.Time][]Task{}
+	for _, task := range t {
+		day := startOfDay(time.Time(task.DueDate))
+		if !day.Before(first) && day.Before(next) {
+			byDay[day] = append(byDay[day], task)
+		}
+	}
+	for _, day := range byDay {
+		slices.SortStableFunc(day, compareByDay)
+	}
+
+	cell := 16
+	if opts.Width > 0 {
+		cell = min(max((opts.Width-1)/7-1, 6), 24)
+	}
+	inner := cell - 2
+	lines := 1 + calendarTitleLines
+	if opts.Counts {
+		lines = 2
+	}
+	today := startOfDay(opts.Now)
+
+	sb := strings.Builder{}
+	border := "+" + strings.Repeat(strings.Repeat("-", cell)+"+", 7) + "\n"
+	title := first.Format("January 2006")
+	fmt.Fprintf(&sb, "%*s\n", (len(border)-1+len(title))/2, title)
+	sb.WriteString(border)
+	sb.WriteString("|")
+	for i := range 7 {
+		name := time.Weekday((int(opts.WeekStart) + i) % 7).String()[:3]
+		sb.WriteString(" " + padRight(name, inner) + " |")
+	}
+	sb.WriteString("\n" + border)
+
+	for week := StartOfWeek(first, opts.WeekStart); week.Before(next); week = week.AddDate(0, 0, 7) {
+		for line := range lines {
+			sb.WriteString("|")
+			for i := range 7 {
+				day := week.AddDate(0, 0, i)
+				content := ""
+				if day.Month() == first.Month() {
+					content = calendarCell(byDay[day], day, line, lines, today, opts.Counts, inner)
+				}
+				sb.WriteString(" " + padRight(truncate(content, inner), inner) + " |")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString(border)
+	}
+	return sb.String()
+}
+
+func calendarCell(t []Task, day time.Time, line int, lines int, today time.Time, counts bool, width int) string {
+	switch {
+	case line == 0 && day.Equal(today):
+		return fmt.Sprintf("[%d]", day.Day())
+	case line == 0:
+		return fmt.Sprint(day.Day())
+	case counts:
+		if len(t) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d due", len(t))
+	case line == lines-1 && len(t) > lines-1:
+		more := fmt.Sprintf("+%d more", len(t)-(lines-2))
+		if utf8.RuneCountInString(more) > width {
+			more = fmt.Sprintf("+%d", len(t)-(lines-2))
+		}
+		return more
+	case line-1 < len(t):
+		if t[line-1].Status == Completed {
+			return "✓ " + t[line-1].Title
+		}
+		return t[line-1].Title
+	}
+	return ""
+}
+
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+}
@@ -0,0 +1,73 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderCalendarTableDriven(t *testing.T) {
+	now := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	all := []Task{
+		{Id: 1, Title: "Call dentist", DueDate: DueDate(now), Status: Pending},
+		{Id: 2, Title: "Buy milk", DueDate: DueDate(now), Priority: High, Status: Pending},
+		{Id: 3, Title: "Pay rent", DueDate: DueDate(now.AddDate(0, 0, 1)), Status: Completed},
+		{Id: 4, Title: "Book flights", DueDate: DueDate(now.AddDate(0, 0, 1)), Status: Pending},
+		{Id: 5, Title: "Water plants", DueDate: DueDate(now.AddDate(0, 0, 1)), Status: Pending},
+		{Id: 6, Title: "Renew passport", DueDate: DueDate(now.AddDate(0, 0, 1)), Status: Pending},
+		{Id: 7, Title: "Next month", DueDate: DueDate(now.AddDate(0, 1, 0)), Status: Pending},
+	}
+	tests := []struct {
+		name     string
+		opts     CalendarOptions
+		want     []string
+		dontWant []string
+	}{
+		{
+			name: "titles",
+			opts: CalendarOptions{WeekStart: time.Monday, Now: now},
+			want: []string{
+				"April 2024",
+				"| Mon            | Tue ",
+				"| 1              | 2 ",
+				"| [10]           | 11 ",
+				"| Buy milk       | ✓ Pay rent ",
+				"| Call dentist   | Book flights ",
+				"|                | +2 more ",
+				"| 29             | 30             |                |",
+			},
+			dontWant: []string{"Next month", "| 31 "},
+		},
+		{
+			name: "counts starting sunday",
+			opts: CalendarOptions{WeekStart: time.Sunday, Counts: true, Now: now},
+			want: []string{
+				"| Sun            | Mon ",
+				"|                | 1              | 2 ",
+				"| 2 due          | 4 due ",
+			},
+			dontWant: []string{"Buy milk"},
+		},
+		{
+			name: "narrow",
+			opts: CalendarOptions{WeekStart: time.Monday, Width: 50, Now: now},
+			want: []string{"+------+------+", "| Cal… | Boo… |", "| +2   |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderCalendar(all, now, tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Fatalf("expected calendar to contain %q, got:\n%s", want, got)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(got, dontWant) {
+					t.Fatalf("expected calendar not to contain %q, got:\n%s", dontWant, got)
+				}
+			}
+		})
+	}
+}
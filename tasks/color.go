@@ -0,0 +1,135 @@
+package tasks
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type ColorMode int
+
+const (
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+func ParseColorMode(s string) (ColorMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	}
+	return ColorAuto, fmt.Errorf("invalid color mode '%s': must be 'always', 'never', or 'auto'", s)
+}
+
+const ansiReset = "\x1b[0m"
+
+var ansiAttributes = map[string]string{
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"reverse":   "7",
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"gray":      "90",
+}
+
+var themeKeys = []string{"header", "high", "medium", "low", "overdue", "today", "completed", "match"}
+
+type Theme map[string]string
+
+func DefaultTheme() Theme {
+	return Theme{
+		"header":    "bold",
+		"high":      "red",
+		"medium":    "yellow",
+		"low":       "green",
+		"overdue":   "bold+red",
+		"today":     "bold+cyan",
+		"completed": "dim",
+		"match":     "reverse",
+	}
+}
+
+func ParseTheme(spec string) (Theme, error) {
+	theme := DefaultTheme()
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.ToLower(strings.TrimSpace(value))
+		if !ok || !slices.Contains(themeKeys, key) {
+			return nil, fmt.Errorf("invalid theme entry '%s': expected key=style with key one of %s", entry, strings.Join(themeKeys, ", "))
+		}
+		if value != "none" && value != "" {
+			for _, attr := range strings.Split(value, "+") {
+				if _, ok := ansiAttributes[attr]; !ok {
+					return nil, fmt.Errorf("invalid theme style '%s': must be '+'-separated from %s, or 'none'", value, strings.Join(slices.Sorted(maps.Keys(ansiAttributes)), ", "))
+				}
+			}
+		}
+		theme[key] = value
+	}
+	return theme, nil
+}
+
+func (th Theme) seq(key string) string {
+	value := th[key]
+	if value == "" || value == "none" {
+		return ""
+	}
+	codes := make([]string, 0)
+	for _, attr := range strings.Split(value, "+") {
+		if code, ok := ansiAttributes[attr]; ok {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+func paint(s string, seq string) string {
+	if seq == "" {
+		return s
+	}
+	return seq + s + ansiReset
+}
+
+func highlight(s string, re *regexp.Regexp, matchSeq string, baseSeq string) string {
+	if re == nil || matchSeq == "" {
+		return paint(s, baseSeq)
+	}
+	sb := strings.Builder{}
+	sb.WriteString(baseSeq)
+	last := 0
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		sb.WriteString(s[last:loc[0]])
+		sb.WriteString(ansiReset + matchSeq + s[loc[0]:loc[1]] + ansiReset + baseSeq)
+		last = loc[1]
+	}
+	sb.WriteString(s[last:])
+	if baseSeq != "" || last > 0 {
+		sb.WriteString(ansiReset)
+	}
+	return sb.String()
+}
@@ -0,0 +1,128 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestParseThemeTableDriven(t *testing.T) {
+	invalid := []struct {
+		spec   string
+		errMsg string
+	}{
+		{"urgent=red", "invalid theme entry"},
+		{"high", "invalid theme entry"},
+		{"high=sparkly", "invalid theme style"},
+	}
+	for _, it := range invalid {
+		t.Run(it.spec, func(t *testing.T) {
+			_, err := ParseTheme(it.spec)
+			if err == nil || !strings.Contains(err.Error(), it.errMsg) {
+				t.Fatalf("expected error containing '%v', got %v", it.errMsg, err)
+			}
+		})
+	}
+
+	theme, err := ParseTheme("high=bold+magenta, match=none")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := theme.seq("high"); got != "\x1b[1;35m" {
+		t.Fatalf("expected bold magenta sequence, got %q", got)
+	}
+	if got := theme.seq("match"); got != "" {
+		t.Fatalf("expected no match sequence, got %q", got)
+	}
+	if got := theme.seq("low"); got != "\x1b[32m" {
+		t.Fatalf("expected default low sequence, got %q", got)
+	}
+}
+
+func TestParseColorModeTableDriven(t *testing.T) {
+	tests := []struct {
+		s    string
+		want ColorMode
+	}{
+		{"", ColorAuto},
+		{"auto", ColorAuto},
+		{"ALWAYS", ColorAlways},
+		{"never", ColorNever},
+	}
+	for _, tt := range tests {
+		got, err := ParseColorMode(tt.s)
+		if err != nil || got != tt.want {
+			t.Fatalf("%q: expected %v, got %v (err %v)", tt.s, tt.want, got, err)
+		}
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+}
+
+func TestRenderTableColor(t *testing.T) {
+	now := time.Date(2024, 4, 10, 9, 0, 0, 0, time.UTC)
+	rows := []Task{
+		{Id: 1, Title: "Overdue task", Priority: High, DueDate: DueDate(now.AddDate(0, 0, -2)), Status: Pending},
+		{Id: 2, Title: "Due today", Priority: Low, DueDate: DueDate(now.Add(8 * time.Hour)), Status: Pending},
+		{Id: 3, Title: "Done task", Priority: Medium, DueDate: DueDate(now.AddDate(0, 0, -2)), Status: Completed},
+	}
+
+	plain := RenderTableWith(rows, TableOptions{Now: now})
+	if strings.Contains(plain, "\x1b[") {
+		t.Fatalf("expected no escape sequences without color, got %q", plain)
+	}
+	if !strings.Contains(plain, "pending (OVERDUE)") {
+		t.Fatalf("expected overdue marker, got:\n%v", plain)
+	}
+	lines := strings.Split(strings.TrimSpace(plain), "\n")
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) != utf8.RuneCountInString(lines[0]) {
+			t.Fatalf("expected the marker to keep columns aligned, got:\n%v", plain)
+		}
+	}
+	if strings.Count(plain, "OVERDUE") != 1 {
+		t.Fatalf("expected only the pending overdue task to be marked, got:\n%v", plain)
+	}
+
+	colored := RenderTableWith(rows, TableOptions{Now: now, Color: ColorAlways})
+	for _, want := range []string{
+		"\x1b[1;31mOverdue task",
+		"\x1b[1;31m\x1b[31mHIGH",
+		"\x1b[1;36mDue today",
+		"\x1b[32mLOW",
+		"\x1b[2mDone task",
+		"\x1b[2mMEDIUM",
+		"\x1b[1mID",
+	} {
+		if !strings.Contains(colored, want) {
+			t.Fatalf("expected colored table to contain %q, got %q", want, colored)
+		}
+	}
+}
+
+func TestRenderTableHighlight(t *testing.T) {
+	rows := []Task{{Id: 1, Title: "Deploy release", Status: Pending}}
+	opts := TableOptions{
+		Columns:   []string{"title"},
+		Color:     ColorAlways,
+		Highlight: HighlightPattern("deploy", SearchFuzzy),
+	}
+
+	got := RenderTableWith(rows, opts)
+	if !strings.Contains(got, "\x1b[0m\x1b[7mDeploy\x1b[0m release") {
+		t.Fatalf("expected highlighted match, got %q", got)
+	}
+
+	opts.Columns = []string{"id", "title"}
+	opts.Highlight = HighlightPattern("1 deploy", SearchFuzzy)
+	if got := RenderTableWith(rows, opts); strings.Contains(got, "\x1b[7m1") || !strings.Contains(got, "\x1b[7mDeploy") {
+		t.Fatalf("expected only the title to be highlighted, got %q", got)
+	}
+
+	opts.Color = ColorNever
+	if got := RenderTableWith(rows, opts); strings.Contains(got, "\x1b[") {
+		t.Fatalf("expected no highlight without color, got %q", got)
+	}
+}
@@ -0,0 +1,30 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrAlreadyCompleted  = errors.New("already completed")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrStorage           = errors.New("storage error")
+	ErrUnavailable       = errors.New("task server unavailable")
+	ErrChanged           = errors.New("task has changed since it was read")
+	ErrRejected          = errors.New("rejected the change")
+)
+
+func checkTransition(id int, from Status, to Status) error {
+	if from == to && to == Completed {
+		return fmt.Errorf("task %d is %w", id, ErrAlreadyCompleted)
+	}
+	if _, ok := statusToString[to]; !ok || from == to {
+		return fmt.Errorf("task %d: %w from %s to %s", id, ErrInvalidTransition, from.String(), to.String())
+	}
+	return nil
+}
+
+func storageError(op string, path string, err error) error {
+	return fmt.Errorf("%w: %s %s: %w", ErrStorage, op, path, err)
+}
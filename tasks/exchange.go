@@ -0,0 +1,18 @@
+package tasks
+
+import "fmt"
+
+// Issue reports a record that was skipped, or imported or exported with
+// some of its data dropped.
+type Issue struct {
+	Line    int
+	Message string
+	Skipped bool
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
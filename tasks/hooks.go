@@ -0,0 +1,289 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultHookTimeout is how long a hook may run before it is stopped.
+const DefaultHookTimeout = 10 * time.Second
+
+// HookNames lists the hooks that are run, in the order they can happen.
+var HookNames = []string{"pre-add", "post-add", "pre-complete", "post-complete", "pre-delete", "post-delete"}
+
+// Hooks runs executables named after HookNames from Dir, in the style of git
+// hooks. Each gets the task as JSON on stdin. A pre-hook vetoes the change by
+// exiting non-zero, with its stderr as the reason, and may print the task as
+// JSON on stdout to change it; fields it leaves out keep their value. A
+// post-hook cannot undo the change, so its failures are only reported as
+// warnings on Stderr.
+type Hooks struct {
+	Dir     string
+	Timeout time.Duration
+	Stderr  io.Writer
+}
+
+type hookedManager struct {
+	Manager
+	hooks Hooks
+}
+
+type hookedBatcher struct {
+	*hookedManager
+	Batcher
+}
+
+// WithHooks returns a Manager that runs the hooks around adding, completing
+// and deleting tasks in m.
+func WithHooks(m Manager, hooks Hooks) Manager {
+	if hooks.Timeout <= 0 {
+		hooks.Timeout = DefaultHookTimeout
+	}
+	if hooks.Stderr == nil {
+		hooks.Stderr = io.Discard
+	}
+	h := &hookedManager{Manager: m, hooks: hooks}
+	if b, ok := m.(Batcher); ok {
+		return hookedBatcher{h, b}
+	}
+	return h
+}
+
+func (m *hookedManager) AddTask(title string, priority Priority, getDueDate func(time.Time) DueDate, category string, opts ...TaskOption) (*Task, error) {
+	now := m.Now()
+	preview := Task{Title: title, Priority: priority, DueDate: getDueDate(now), Category: category, Status: Pending, CreatedAt: now}
+	for _, opt := range opts {
+		opt(&preview)
+	}
+	rewritten, err := m.hooks.pre("pre-add", preview)
+	if err != nil {
+		return nil, err
+	}
+	if rewritten != nil {
+		preview = *rewritten
+		title, priority, category = preview.Title, preview.Priority, strings.ToLower(strings.TrimSpace(preview.Category))
+		opts = []TaskOption{
+			WithDescription(preview.Description),
+			WithNotes(preview.Notes...),
+			WithTags(preview.Tags...),
+			WithUID(preview.UID),
+			WithRecurrence(preview.Recurrence),
+			WithCreatedAt(preview.CreatedAt),
+		}
+		if preview.Status == Completed {
+			opts = append(opts, WithCompletion(preview.CompletedAt))
+		}
+	}
+	t, err := m.Manager.AddTask(title, priority, func(time.Time) DueDate { return preview.DueDate }, category, opts...)
+	if err != nil {
+		return nil, err
+	}
+	m.hooks.post("post-add", t)
+	return t, nil
+}
+
+func (m *hookedManager) CompleteTask(id int) error {
+	current, err := m.GetTask(id)
+	if err != nil {
+		return err
+	}
+	if current.Status == Completed {
+		return m.Manager.CompleteTask(id)
+	}
+	patch, err := m.preComplete(*current, TaskPatch{})
+	if err != nil {
+		return err
+	}
+	if patch == (TaskPatch{}) {
+		err = m.Manager.CompleteTask(id)
+	} else {
+		completed := Completed
+		patch.Status = &completed
+		_, err = m.Manager.UpdateTask(id, patch)
+	}
+	if err != nil {
+		return err
+	}
+	if t, err := m.GetTask(id); err == nil {
+		m.hooks.post("post-complete", t)
+	}
+	return nil
+}
+
+// UpdateTask runs the complete hooks when the patch completes a pending task,
+// as 'edit -status completed' does.
+func (m *hookedManager) UpdateTask(id int, patch TaskPatch) (*Task, error) {
+	if patch.Status == nil || *patch.Status != Completed {
+		return m.Manager.UpdateTask(id, patch)
+	}
+	current, err := m.GetTask(id)
+	if err != nil || current.Status == Completed {
+		return m.Manager.UpdateTask(id, patch)
+	}
+	if patch, err = m.preComplete(*current, patch); err != nil {
+		return nil, err
+	}
+	t, err := m.Manager.UpdateTask(id, patch)
+	if err != nil {
+		return nil, err
+	}
+	m.hooks.post("post-complete", t)
+	return t, nil
+}
+
+// preComplete runs the pre-complete hook on the task as it will be once
+// completed, and adds the fields the hook changed to the patch.
+func (m *hookedManager) preComplete(current Task, patch TaskPatch) (TaskPatch, error) {
+	preview := current
+	patch.apply(&preview)
+	preview.Status = Completed
+	if patch.CompletedAt == nil {
+		preview.CompletedAt = m.Now()
+	}
+	rewritten, err := m.hooks.pre("pre-complete", preview)
+	if err != nil || rewritten == nil {
+		return patch, err
+	}
+	return rewritePatch(patch, preview, *rewritten), nil
+}
+
+func (m *hookedManager) DeleteTask(id int) error {
+	t, err := m.GetTask(id)
+	if err != nil {
+		return err
+	}
+	// the task is going away, so a changed task printed by the hook is ignored
+	if _, err := m.hooks.pre("pre-delete", *t); err != nil {
+		return err
+	}
+	if err := m.Manager.DeleteTask(id); err != nil {
+		return err
+	}
+	m.hooks.post("post-delete", t)
+	return nil
+}
+
+// rewritePatch adds the fields a pre-complete hook changed to the patch.
+func rewritePatch(patch TaskPatch, before Task, after Task) TaskPatch {
+	if after.Title != before.Title {
+		patch.Title = &after.Title
+	}
+	if after.Priority != before.Priority {
+		patch.Priority = &after.Priority
+	}
+	if !time.Time(after.DueDate).Equal(time.Time(before.DueDate)) {
+		patch.DueDate = &after.DueDate
+	}
+	if after.Category != before.Category {
+		category := strings.ToLower(strings.TrimSpace(after.Category))
+		patch.Category = &category
+	}
+	if after.Description != before.Description {
+		patch.Description = &after.Description
+	}
+	if !slices.Equal(after.Notes, before.Notes) {
+		patch.Notes = &after.Notes
+	}
+	if !slices.Equal(after.Tags, before.Tags) {
+		patch.Tags = &after.Tags
+	}
+	if after.Recurrence != before.Recurrence {
+		patch.Recurrence = &after.Recurrence
+	}
+	if !after.CompletedAt.Equal(before.CompletedAt) {
+		patch.CompletedAt = &after.CompletedAt
+	}
+	return patch
+}
+
+// pre runs a pre-hook. It returns the task the hook printed, or nil if it
+// printed nothing.
+func (h Hooks) pre(name string, t Task) (*Task, error) {
+	stdout, err := h.run(name, &t)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(stdout)) == 0 {
+		return nil, nil
+	}
+	// decoding reuses slices, so give the copy its own
+	rewritten := t
+	rewritten.Notes, rewritten.Tags = slices.Clone(t.Notes), slices.Clone(t.Tags)
+	if err := json.Unmarshal(stdout, &rewritten); err != nil {
+		return nil, fmt.Errorf("%s hook printed an invalid task: %v", name, err)
+	}
+	if strings.TrimSpace(rewritten.Title) == "" {
+		return nil, fmt.Errorf("%s hook printed a task with an empty title", name)
+	}
+	rewritten.Title = strings.TrimSpace(rewritten.Title)
+	return &rewritten, nil
+}
+
+// post runs a post-hook, reporting failures as warnings.
+func (h Hooks) post(name string, t *Task) {
+	if _, err := h.run(name, t); err != nil {
+		fmt.Fprintf(h.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// run runs a hook if it exists and returns what it printed on stdout. The
+// hook's stderr is passed on when it succeeds and becomes the error message
+// when it fails.
+func (h Hooks) run(name string, t *Task) ([]byte, error) {
+	path := filepath.Join(h.Dir, name)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return nil, nil
+	}
+	if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+		fmt.Fprintf(h.Stderr, "Warning: %s hook was skipped because it is not executable (chmod +x %s)\n", name, path)
+		return nil, nil
+	}
+	input, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(os.Environ(), "TODO_HOOK="+name)
+	cmd.Stdin = bytes.NewReader(input)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	// do not wait for background processes the hook left holding its output
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	reason := strings.TrimSpace(stderr.String())
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, fmt.Errorf("%s hook timed out after %v", name, h.Timeout)
+	case errors.As(err, &exitErr):
+		if reason == "" {
+			reason = exitErr.String()
+		}
+		if strings.HasPrefix(name, "pre-") {
+			return nil, fmt.Errorf("%s hook %w: %s", name, ErrRejected, reason)
+		}
+		return nil, fmt.Errorf("%s hook failed: %s", name, reason)
+	case err != nil:
+		return nil, fmt.Errorf("%s hook could not be run: %v", name, err)
+	}
+	if reason != "" {
+		fmt.Fprintln(h.Stderr, reason)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,221 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeHook(t *testing.T, dir string, name string, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func newHookedManager(t *testing.T, hooks map[string]string) (Manager, *bytes.Buffer) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	dir := t.TempDir()
+	for name, script := range hooks {
+		writeHook(t, dir, name, script)
+	}
+	now := time.Date(2024, 4, 10, 9, 0, 0, 0, time.UTC)
+	m, _ := newManagerInternal("", func() time.Time { return now }, []Task{
+		{Id: 1, Title: "Call dentist", Priority: High, DueDate: DueDate(now), Category: "health", Status: Pending},
+		{Id: 2, Title: "Buy milk", Priority: Low, DueDate: DueDate(now), Status: Completed},
+	})
+	stderr := &bytes.Buffer{}
+	return WithHooks(m, Hooks{Dir: dir, Timeout: time.Second, Stderr: stderr}), stderr
+}
+
+func today(time.Time) DueDate {
+	return DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC))
+}
+
+func TestHooksAdd(t *testing.T) {
+	tests := []struct {
+		name       string
+		hooks      map[string]string
+		category   string
+		wantErr    string
+		wantTask   func(t *Task) bool
+		wantStderr string
+	}{
+		{
+			name:     "no hooks",
+			category: "work",
+			wantTask: func(t *Task) bool { return t.Id == 3 && t.Category == "work" },
+		},
+		{
+			name:     "veto from stdin",
+			hooks:    map[string]string{"pre-add": `case "$(cat)" in *'"category":"work"'*) echo "work tasks need a due date" >&2; exit 1;; esac`},
+			category: "work",
+			wantErr:  "pre-add hook rejected the change: work tasks need a due date",
+		},
+		{
+			name:     "allowed",
+			hooks:    map[string]string{"pre-add": `case "$(cat)" in *'"category":"work"'*) exit 1;; esac`},
+			category: "home",
+			wantTask: func(t *Task) bool { return t.Category == "home" },
+		},
+		{
+			name:    "veto without a message",
+			hooks:   map[string]string{"pre-add": `exit 3`},
+			wantErr: "pre-add hook rejected the change: exit status 3",
+		},
+		{
+			name:  "rewrite",
+			hooks: map[string]string{"pre-add": `cat > /dev/null; echo '{"category": "Work", "tags": ["triage"], "priority": "HIGH"}'`},
+			wantTask: func(t *Task) bool {
+				return t.Title == "Water plants" && t.Category == "work" && t.Priority == High && reflect.DeepEqual(t.Tags, []string{"triage"})
+			},
+		},
+		{
+			name:    "invalid rewrite",
+			hooks:   map[string]string{"pre-add": `echo 'not json'`},
+			wantErr: "pre-add hook printed an invalid task",
+		},
+		{
+			name:    "empty title",
+			hooks:   map[string]string{"pre-add": `echo '{"title": " "}'`},
+			wantErr: "pre-add hook printed a task with an empty title",
+		},
+		{
+			name:    "timeout",
+			hooks:   map[string]string{"pre-add": `exec sleep 10`},
+			wantErr: "pre-add hook timed out after 1s",
+		},
+		{
+			name:       "post-add failure is a warning",
+			hooks:      map[string]string{"post-add": `echo "could not notify" >&2; exit 1`},
+			wantTask:   func(t *Task) bool { return t.Id == 3 },
+			wantStderr: "Warning: post-add hook failed: could not notify",
+		},
+		{
+			name:       "post-add sees the id",
+			hooks:      map[string]string{"post-add": `grep -o '"id":3' >&2`},
+			wantTask:   func(t *Task) bool { return t.Id == 3 },
+			wantStderr: `"id":3`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, stderr := newHookedManager(t, tt.hooks)
+			got, err := m.AddTask("Water plants", Medium, today, tt.category, WithTags("garden"))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				if all, _ := m.ListTasks(nil, nil, "", false); len(all) != 2 {
+					t.Fatalf("expected no task to be added, got %d tasks", len(all))
+				}
+				return
+			}
+			if err != nil || !tt.wantTask(got) {
+				t.Fatalf("unexpected task %+v (%v)", got, err)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Fatalf("expected stderr to contain %q, got %q", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestHooksComplete(t *testing.T) {
+	m, _ := newHookedManager(t, map[string]string{
+		"pre-complete": `case "$(cat)" in *'"status":"completed"'*) echo '{"notes": ["checked by hook"]}';; *) exit 1;; esac`,
+	})
+	if err := m.CompleteTask(1); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	got, _ := m.GetTask(1)
+	if got.Status != Completed || got.CompletedAt.IsZero() || !reflect.DeepEqual(got.Notes, []string{"checked by hook"}) {
+		t.Fatalf("unexpected completed task %+v", got)
+	}
+	if err := m.CompleteTask(2); !errors.Is(err, ErrAlreadyCompleted) {
+		t.Fatalf("expected already completed, got %v", err)
+	}
+
+	// completing through an update runs the same hooks
+	m, _ = newHookedManager(t, map[string]string{"pre-complete": `echo "not today" >&2; exit 1`})
+	completed := Completed
+	if _, err := m.UpdateTask(1, TaskPatch{Status: &completed}); !errors.Is(err, ErrRejected) {
+		t.Fatalf("expected a veto, got %v", err)
+	}
+	title := "Call the dentist"
+	if _, err := m.UpdateTask(1, TaskPatch{Title: &title}); err != nil {
+		t.Fatalf("expected other updates to skip the hook, got %v", err)
+	}
+	if err := m.CompleteTask(1); err == nil || err.Error() != "pre-complete hook rejected the change: not today" {
+		t.Fatalf("expected a veto, got %v", err)
+	}
+}
+
+func TestHooksDelete(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "deleted.json")
+	m, _ := newHookedManager(t, map[string]string{
+		"pre-delete":  `case "$(cat)" in *'"category":"health"'*) echo "health tasks are kept" >&2; exit 1;; esac`,
+		"post-delete": `cat > ` + log,
+	})
+	if err := m.DeleteTask(1); err == nil || !strings.Contains(err.Error(), "health tasks are kept") {
+		t.Fatalf("expected a veto, got %v", err)
+	}
+	if err := m.DeleteTask(2); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if b, _ := os.ReadFile(log); !strings.Contains(string(b), `"title":"Buy milk"`) {
+		t.Fatalf("expected post-delete to get the deleted task, got %q", b)
+	}
+	if err := m.DeleteTask(9); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestHooksSkipped(t *testing.T) {
+	m, stderr := newHookedManager(t, nil)
+	hooks := m.(hookedBatcher).hooks
+	os.WriteFile(filepath.Join(hooks.Dir, "pre-delete"), []byte("#!/bin/sh\nexit 1\n"), 0644)
+	if err := m.DeleteTask(1); err != nil {
+		t.Fatalf("expected the hook to be skipped, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "pre-delete hook was skipped because it is not executable") {
+		t.Fatalf("expected a warning, got %q", stderr.String())
+	}
+
+	// batching still works through the hooks
+	if _, ok := m.(Batcher); !ok {
+		t.Fatalf("expected the hooked manager to keep batching")
+	}
+}
+
+func TestHooksServed(t *testing.T) {
+	m, _ := newHookedManager(t, map[string]string{"pre-add": `echo "no new tasks on Fridays" >&2; exit 1`})
+	srv := httptest.NewServer(NewHandler(m))
+	defer srv.Close()
+	resp := doRequest(t, srv, "POST", "/tasks", `{"title": "Water plants"}`, nil)
+	var apiErr APIError
+	json.NewDecoder(resp.Body).Decode(&apiErr)
+	if resp.StatusCode != http.StatusUnprocessableEntity || apiErr.Code != "rejected" {
+		t.Fatalf("expected a 422 rejection, got %d %+v", resp.StatusCode, apiErr)
+	}
+
+	// the remote client reports the veto like a local one
+	remote, _ := NewRemoteManager(srv.URL, srv.Client())
+	if _, err := remote.AddTask("Water plants", Medium, today, ""); !errors.Is(err, ErrRejected) ||
+		err.Error() != "pre-add hook rejected the change: no new tasks on Fridays" {
+		t.Fatalf("expected the veto, got %v", err)
+	}
+}
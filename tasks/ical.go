@@ -0,0 +1,328 @@
+package tasks
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icsDateLayout     = "20060102"
+	icsDateTimeLayout = "20060102T150405Z"
+	icsCategoryProp   = "X-TODO-CATEGORY"
+)
+
+var icsPriority = map[Priority]int{High: 1, Medium: 5, Low: 9}
+
+// ExportICS writes tasks as an RFC 5545 calendar of VTODO components.
+func ExportICS(w io.Writer, t []Task, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//golang-todo-cli//EN")
+	for _, task := range t {
+		line("BEGIN", "VTODO")
+		line("UID", icsEscape(TaskUID(&task)))
+		line("DTSTAMP", now.UTC().Format(icsDateTimeLayout))
+		if !task.CreatedAt.IsZero() {
+			line("CREATED", task.CreatedAt.UTC().Format(icsDateTimeLayout))
+		}
+		line("SUMMARY", icsEscape(task.Title))
+		if task.Description != "" {
+			line("DESCRIPTION", icsEscape(task.Description))
+		}
+		for _, note := range task.Notes {
+			line("COMMENT", icsEscape(note))
+		}
+		line("PRIORITY", strconv.Itoa(icsPriority[task.Priority]))
+		line("DUE;VALUE=DATE", time.Time(task.DueDate).Format(icsDateLayout))
+		// other apps only read CATEGORIES, so the category is listed there too
+		line(icsCategoryProp, icsEscape(task.Category))
+		categories := task.Tags
+		if task.Category != "" {
+			categories = append([]string{task.Category}, task.Tags...)
+		}
+		if len(categories) > 0 {
+			escaped := make([]string, 0, len(categories))
+			for _, c := range categories {
+				escaped = append(escaped, icsEscape(c))
+			}
+			line("CATEGORIES", strings.Join(escaped, ","))
+		}
+		if task.Status == Completed {
+			line("STATUS", "COMPLETED")
+			if !task.CompletedAt.IsZero() {
+				line("COMPLETED", task.CompletedAt.UTC().Format(icsDateTimeLayout))
+			}
+		} else {
+			line("STATUS", "NEEDS-ACTION")
+		}
+		if task.Recurrence != "" {
+			line("RRULE", task.Recurrence)
+		}
+		line("END", "VTODO")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// writeFolded ends a content line with CRLF, folding it so no line is
+// longer than 75 octets without splitting a UTF-8 sequence.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74
+	}
+	w.WriteString(s + "\r\n")
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "")
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+func icsUnescape(s string) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteByte(s[i])
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// splitICSList splits a comma-separated value, keeping escaped commas.
+func splitICSList(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, icsUnescape(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, icsUnescape(s[start:]))
+}
+
+type icsProperty struct {
+	line   int
+	name   string
+	params map[string]string
+	value  string
+}
+
+// readICSLines unfolds content lines and splits them into properties.
+func readICSLines(r io.Reader) ([]icsProperty, error) {
+	var props []icsProperty
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		text := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(props) > 0 {
+			props[len(props)-1].value += text[1:]
+			continue
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		props = append(props, icsProperty{line: n, value: text})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	for i := range props {
+		props[i].parse()
+	}
+	return props, nil
+}
+
+// parse splits "NAME;PARAM=x:value", where quoted parameter values may
+// contain ':' and ';'.
+func (p *icsProperty) parse() {
+	raw := p.value
+	quoted := false
+	split := -1
+	for i := 0; i < len(raw) && split < 0; i++ {
+		switch raw[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				split = i
+			}
+		}
+	}
+	if split < 0 {
+		p.name, p.value = strings.ToUpper(raw), ""
+		return
+	}
+	head := strings.Split(raw[:split], ";")
+	p.name = strings.ToUpper(head[0])
+	p.params = map[string]string{}
+	for _, param := range head[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	p.value = raw[split+1:]
+}
+
+func parseICSTime(s string) (time.Time, error) {
+	for _, layout := range []string{icsDateTimeLayout, "20060102T150405", icsDateLayout} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s'", s)
+}
+
+// ImportICS reads the VTODO components of a calendar. Tasks without a due
+// date are due on now; other components such as events are ignored.
+func ImportICS(r io.Reader, now time.Time) ([]Task, []Issue, error) {
+	props, err := readICSLines(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var result []Task
+	var issues []Issue
+	var task *Task
+	var start int
+	var categories []string
+	category := ""
+	hasCategory, hasDue, skip := false, false, false
+	nested := 0
+	for _, p := range props {
+		if p.name == "BEGIN" && strings.EqualFold(p.value, "VTODO") {
+			task = &Task{Priority: Medium, Status: Pending}
+			start, categories, category = p.line, nil, ""
+			hasCategory, hasDue, skip, nested = false, false, false, 0
+			continue
+		}
+		if task == nil {
+			continue
+		}
+		warn := func(format string, a ...any) {
+			issues = append(issues, Issue{Line: p.line, Message: fmt.Sprintf(format, a...)})
+		}
+		// components inside a VTODO, such as alarms, are not kept
+		switch {
+		case p.name == "BEGIN":
+			if nested == 0 {
+				warn("%s dropped", strings.ToUpper(p.value))
+			}
+			nested++
+			continue
+		case p.name == "END" && nested > 0:
+			nested--
+			continue
+		case nested > 0:
+			continue
+		}
+		switch p.name {
+		case "END":
+			if !strings.EqualFold(p.value, "VTODO") {
+				continue
+			}
+			switch {
+			case skip:
+			case strings.TrimSpace(task.Title) == "":
+				issues = append(issues, Issue{Line: start, Message: "task has no SUMMARY", Skipped: true})
+			default:
+				if !hasDue {
+					task.DueDate = DueDate(startOfDay(now))
+				}
+				if hasCategory {
+					task.Category = category
+					if len(categories) > 0 && categories[0] == category {
+						categories = categories[1:]
+					}
+				} else if len(categories) > 0 {
+					task.Category, categories = categories[0], categories[1:]
+				}
+				task.Category = strings.ToLower(task.Category)
+				task.Tags = categories
+				result = append(result, *task)
+			}
+			task = nil
+		case "UID":
+			task.UID = icsUnescape(p.value)
+		case "SUMMARY":
+			task.Title = strings.TrimSpace(icsUnescape(p.value))
+		case "DESCRIPTION":
+			task.Description = icsUnescape(p.value)
+		case "COMMENT":
+			task.Notes = append(task.Notes, icsUnescape(p.value))
+		case "PRIORITY":
+			n, err := strconv.Atoi(p.value)
+			switch {
+			case err != nil || n < 0 || n > 9:
+				warn("invalid PRIORITY '%s', using MEDIUM", p.value)
+			case n >= 1 && n <= 4:
+				task.Priority = High
+			case n >= 6:
+				task.Priority = Low
+			}
+		case "DUE":
+			due, err := parseICSTime(p.value)
+			if err != nil {
+				warn("%v, using today", err)
+				continue
+			}
+			task.DueDate, hasDue = DueDate(startOfDay(due)), true
+		case "CREATED":
+			if at, err := parseICSTime(p.value); err == nil {
+				task.CreatedAt = at
+			}
+		case "COMPLETED":
+			if at, err := parseICSTime(p.value); err == nil {
+				task.CompletedAt = at
+			}
+		case "CATEGORIES":
+			categories = append(categories, splitICSList(p.value)...)
+		case icsCategoryProp:
+			category, hasCategory = icsUnescape(p.value), true
+		case "STATUS":
+			switch strings.ToUpper(p.value) {
+			case "COMPLETED":
+				task.Status = Completed
+			case "CANCELLED":
+				issues = append(issues, Issue{Line: p.line, Message: "cancelled task skipped", Skipped: true})
+				skip = true
+			}
+		case "RRULE":
+			task.Recurrence = p.value
+		}
+	}
+	if task != nil {
+		issues = append(issues, Issue{Line: start, Message: "VTODO is missing END:VTODO", Skipped: true})
+	}
+	for i := range result {
+		if result[i].Status != Completed {
+			result[i].CompletedAt = time.Time{}
+		}
+	}
+	return result, issues, nil
+}
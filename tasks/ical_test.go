@@ -0,0 +1,143 @@
+package tasks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestICSRoundTrip(t *testing.T) {
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+	want := []Task{
+		{
+			Id:          1,
+			Title:       "Call dentist, then pharmacy; bring card",
+			Priority:    High,
+			DueDate:     DueDate(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)),
+			Category:    "health",
+			Status:      Pending,
+			Description: strings.Repeat("A long description that has to be folded ", 3) + "✓\nwith two lines",
+			Notes:       []string{"Morning only", `Ask about x-rays\`},
+			Tags:        []string{"phone", "a,b"},
+			CreatedAt:   time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC),
+			Recurrence:  "FREQ=MONTHLY;BYMONTHDAY=12",
+		},
+		{
+			Id:          2,
+			Title:       "Buy milk",
+			Priority:    Low,
+			DueDate:     DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)),
+			Status:      Completed,
+			Tags:        []string{"errand"},
+			CompletedAt: time.Date(2024, 4, 10, 7, 0, 0, 0, time.UTC),
+			UID:         "milk@example.com",
+		},
+	}
+
+	sb := strings.Builder{}
+	if err := ExportICS(&sb, want, now); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	out := sb.String()
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("expected folded lines of at most 75 octets, got %q", line)
+		}
+	}
+	for _, s := range []string{"BEGIN:VTODO\r\n", "PRIORITY:1\r\n", "DUE;VALUE=DATE:20240412\r\n", "STATUS:COMPLETED\r\n", "CATEGORIES:health,phone,a\\,b\r\n", "RRULE:FREQ=MONTHLY;BYMONTHDAY=12\r\n", "UID:milk@example.com\r\n"} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("expected output to contain %q, got:\n%s", s, out)
+		}
+	}
+
+	got, issues, err := ImportICS(strings.NewReader(out), now)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("unexpected err: %v %v", err, issues)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d tasks, got %d", len(want), len(got))
+	}
+	for i := range want {
+		want[i].UID = TaskUID(&want[i])
+		want[i].Id = 0
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Fatalf("expected\n%+v\ngot\n%+v", want[i], got[i])
+		}
+	}
+}
+
+func TestImportICSTableDriven(t *testing.T) {
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		ics        string
+		want       []Task
+		wantIssues []string
+	}{
+		{
+			name: "other calendar apps",
+			ics: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Not a task\nEND:VEVENT\n" +
+				"BEGIN:VTODO\nUID:abc\nsummary;LANGUAGE=en:Water\n  plants\nPRIORITY:3\nDUE;TZID=\"Europe/Paris:x\":20240415T090000\n" +
+				"CATEGORIES:Home,garden\nBEGIN:VALARM\nDESCRIPTION:Reminder\nEND:VALARM\nEND:VTODO\nEND:VCALENDAR\n",
+			want:       []Task{{Title: "Water plants", Priority: High, DueDate: DueDate(time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)), Category: "home", Tags: []string{"garden"}, Status: Pending, UID: "abc"}},
+			wantIssues: []string{"line 12: VALARM dropped"},
+		},
+		{
+			name: "defaults",
+			ics:  "BEGIN:VTODO\r\nSUMMARY:No due date\r\nPRIORITY:7\r\nEND:VTODO\r\n",
+			want: []Task{{Title: "No due date", Priority: Low, DueDate: DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)), Status: Pending}},
+		},
+		{
+			name: "skipped and invalid",
+			ics: "BEGIN:VTODO\nSUMMARY:Cancelled\nSTATUS:CANCELLED\nEND:VTODO\n" +
+				"BEGIN:VTODO\nDUE:20240401\nEND:VTODO\n" +
+				"BEGIN:VTODO\nSUMMARY:Odd\nPRIORITY:high\nDUE:soon\nEND:VTODO\n" +
+				"BEGIN:VTODO\nSUMMARY:Unfinished\n",
+			want: []Task{{Title: "Odd", Priority: Medium, DueDate: DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)), Status: Pending}},
+			wantIssues: []string{
+				"line 3: cancelled task skipped",
+				"line 5: task has no SUMMARY",
+				"line 10: invalid PRIORITY 'high', using MEDIUM",
+				"line 11: invalid date 'soon', using today",
+				"line 13: VTODO is missing END:VTODO",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, issues, err := ImportICS(strings.NewReader(tt.ics), now)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected\n%+v\ngot\n%+v", tt.want, got)
+			}
+			gotIssues := []string{}
+			for _, issue := range issues {
+				gotIssues = append(gotIssues, issue.String())
+			}
+			if strings.Join(gotIssues, "\n") != strings.Join(tt.wantIssues, "\n") {
+				t.Fatalf("expected issues %q, got %q", tt.wantIssues, gotIssues)
+			}
+		})
+	}
+}
+
+func TestTaskUID(t *testing.T) {
+	created := time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC)
+	a := TaskUID(&Task{Id: 1, CreatedAt: created})
+	if a != TaskUID(&Task{Id: 1, CreatedAt: created, Title: "Renamed"}) {
+		t.Fatalf("expected the UID to depend only on the id and creation time")
+	}
+	if a == TaskUID(&Task{Id: 2, CreatedAt: created}) {
+		t.Fatalf("expected different tasks to get different UIDs")
+	}
+	if len(a) != 36 || a[14] != '5' {
+		t.Fatalf("expected a version 5 UUID, got %q", a)
+	}
+	if got := TaskUID(&Task{Id: 1, UID: "abc"}); got != "abc" {
+		t.Fatalf("expected a stored UID to win, got %q", got)
+	}
+}
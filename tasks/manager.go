@@ -10,23 +10,39 @@ import (
 )
 
 type Manager interface {
-	AddTask(title string, priority Priority, getDueDate func(time.Time) DueDate, category string) (*Task, error)
+	AddTask(title string, priority Priority, getDueDate func(time.Time) DueDate, category string, opts ...TaskOption) (*Task, error)
 	ListTasks(status *Status, priority *Priority, category string, overdueOnly bool) ([]Task, error)
-	SearchTasks(query string) ([]Task, error)
+	SearchTasks(query string, opts SearchOptions) ([]Task, error)
+	GetTask(id int) (*Task, error)
+	UpdateTask(id int, patch TaskPatch) (*Task, error)
 	CompleteTask(id int) error
 	DeleteTask(id int) error
+	QueryTasks(filter Filter) ([]Task, error)
+	SaveView(view View) error
+	GetView(name string) (*View, error)
+	ListViews() ([]View, error)
+	DeleteView(name string) error
+	Now() time.Time
 }
 
 type manager struct {
-	filename string
-	now      func() time.Time
-	nextId   int
-	tasks    []Task
+	filename      string
+	viewsFilename string
+	now           func() time.Time
+	nextId        int
+	tasks         []Task
+	views         []View
+	index         *searchIndex
+	batch         *batch
 }
 
 func NewManager() (Manager, error) {
+	return NewManagerWithFile("tasks.db.json")
+}
+
+func NewManagerWithFile(filename string) (Manager, error) {
 	return newManagerInternal(
-		"tasks.db.json",
+		filename,
 		time.Now,
 		[]Task{},
 	)
@@ -41,14 +57,19 @@ func newManagerInternal(
 		now = time.Now
 	}
 	m := &manager{
-		filename: filename,
-		now:      now,
-		tasks:    tasks,
+		filename:      filename,
+		viewsFilename: viewsFilenameFor(filename),
+		now:           now,
+		tasks:         tasks,
+		views:         []View{},
 	}
 	err := m.loadFromFile()
 	if err != nil {
 		return nil, err
 	}
+	if err := m.loadViewsFromFile(); err != nil {
+		return nil, err
+	}
 	nextId := 1
 	for _, task := range m.tasks {
 		if task.Id >= nextId {
@@ -59,16 +80,21 @@ func newManagerInternal(
 	return m, nil
 }
 
-func (m *manager) AddTask(title string, priority Priority, getDueDate func(time.Time) DueDate, category string) (*Task, error) {
+func (m *manager) AddTask(title string, priority Priority, getDueDate func(time.Time) DueDate, category string, opts ...TaskOption) (*Task, error) {
 	newTask := Task{
-		Id:       m.nextId,
-		Title:    title,
-		Priority: priority,
-		DueDate:  getDueDate(m.now()),
-		Category: category,
-		Status:   Pending,
+		Id:        m.nextId,
+		Title:     title,
+		Priority:  priority,
+		DueDate:   getDueDate(m.now()),
+		Category:  category,
+		Status:    Pending,
+		CreatedAt: m.now(),
+	}
+	for _, opt := range opts {
+		opt(&newTask)
 	}
 	m.tasks = append(m.tasks, newTask)
+	m.index = nil
 	m.nextId++
 	if err := m.saveToFile(); err != nil {
 		return nil, err
@@ -78,6 +104,16 @@ func (m *manager) AddTask(title string, priority Priority, getDueDate func(time.
 
 func (m *manager) ListTasks(status *Status, priority *Priority, category string, overdueOnly bool) ([]Task, error) {
 	tasks := make([]Task, 0)
+	matches := m.listFilter(status, priority, category, overdueOnly)
+	for _, task := range m.tasks {
+		if matches(&task) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *manager) listFilter(status *Status, priority *Priority, category string, overdueOnly bool) func(t *Task) bool {
 	statusFilter := func(s Status) bool { return true }
 	if status != nil {
 		statusFilter = func(s Status) bool { return s == *status }
@@ -92,63 +128,89 @@ func (m *manager) ListTasks(status *Status, priority *Priority, category string,
 	}
 	overdueFilter := func(t *Task) bool { return true }
 	if overdueOnly {
-		overdueFilter = func(t *Task) bool {
-			if t.Status == Completed {
-				return false
-			}
-			return time.Time(t.DueDate).Before(m.now())
-		}
+		now := m.now()
+		overdueFilter = func(t *Task) bool { return IsOverdue(t, now) }
 	}
-	for _, task := range m.tasks {
-		if !statusFilter(task.Status) {
-			continue
-		}
-		if !priorityFilter(task.Priority) {
-			continue
-		}
-		if !categoryFilter(task.Category) {
-			continue
-		}
-		if !overdueFilter(&task) {
-			continue
-		}
-		tasks = append(tasks, task)
+	return func(t *Task) bool {
+		return statusFilter(t.Status) &&
+			priorityFilter(t.Priority) &&
+			categoryFilter(t.Category) &&
+			overdueFilter(t)
 	}
-	return tasks, nil
 }
 
-func (m *manager) SearchTasks(query string) ([]Task, error) {
+func (m *manager) QueryTasks(filter Filter) ([]Task, error) {
 	tasks := make([]Task, 0)
-	queryLower := strings.ToLower(query)
+	now := m.now()
 	for _, task := range m.tasks {
-		if strings.Contains(strings.ToLower(task.Title), queryLower) {
+		if filter.Matches(&task, now) {
 			tasks = append(tasks, task)
 		}
 	}
 	return tasks, nil
 }
 
+func (m *manager) Now() time.Time {
+	return m.now()
+}
+
+func (m *manager) GetTask(id int) (*Task, error) {
+	for _, task := range m.tasks {
+		if task.Id == id {
+			return &task, nil
+		}
+	}
+	return nil, fmt.Errorf("task %d %w", id, ErrNotFound)
+}
+
+func (m *manager) UpdateTask(id int, patch TaskPatch) (*Task, error) {
+	for i := range m.tasks {
+		if m.tasks[i].Id != id {
+			continue
+		}
+		if patch.Status != nil && *patch.Status != m.tasks[i].Status {
+			if err := checkTransition(id, m.tasks[i].Status, *patch.Status); err != nil {
+				return nil, err
+			}
+			m.tasks[i].CompletedAt = time.Time{}
+			if *patch.Status == Completed {
+				m.tasks[i].CompletedAt = m.now()
+			}
+		}
+		patch.apply(&m.tasks[i])
+		m.index = nil
+		if err := m.saveToFile(); err != nil {
+			return nil, err
+		}
+		task := m.tasks[i]
+		return &task, nil
+	}
+	return nil, fmt.Errorf("task %d %w", id, ErrNotFound)
+}
+
 func (m *manager) CompleteTask(id int) error {
 	for i := range m.tasks {
 		if m.tasks[i].Id == id {
-			if m.tasks[i].Status == Completed {
-				return fmt.Errorf("task %d is already completed", id)
+			if err := checkTransition(id, m.tasks[i].Status, Completed); err != nil {
+				return err
 			}
 			m.tasks[i].Status = Completed
+			m.tasks[i].CompletedAt = m.now()
 			return m.saveToFile()
 		}
 	}
-	return fmt.Errorf("task %d not found", id)
+	return fmt.Errorf("task %d %w", id, ErrNotFound)
 }
 
 func (m *manager) DeleteTask(id int) error {
 	for i := range m.tasks {
 		if m.tasks[i].Id == id {
 			m.tasks = slices.Delete(m.tasks, i, i+1)
+			m.index = nil
 			return m.saveToFile()
 		}
 	}
-	return fmt.Errorf("task %d not found", id)
+	return fmt.Errorf("task %d %w", id, ErrNotFound)
 }
 
 func (m *manager) loadFromFile() error {
@@ -160,18 +222,28 @@ func (m *manager) loadFromFile() error {
 	}
 	file, err := os.ReadFile(m.filename)
 	if err != nil {
-		return err
+		return storageError("read", m.filename, err)
+	}
+	if err := json.Unmarshal(file, &m.tasks); err != nil {
+		return storageError("decode", m.filename, err)
 	}
-	return json.Unmarshal(file, &m.tasks)
+	return nil
 }
 
 func (m *manager) saveToFile() error {
+	if m.batch != nil {
+		m.batch.changes++
+		return nil
+	}
 	if strings.TrimSpace(m.filename) == "" {
 		return nil
 	}
 	file, err := json.Marshal(m.tasks)
 	if err != nil {
-		return err
+		return storageError("encode", m.filename, err)
+	}
+	if err := os.WriteFile(m.filename, file, 0644); err != nil {
+		return storageError("write", m.filename, err)
 	}
-	return os.WriteFile(m.filename, file, 0644)
+	return nil
 }
@@ -1,15 +1,18 @@
 package tasks
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
-	"slices"
 	"strings"
 	"testing"
 	"time"
 )
 
 func TestAddTask(t *testing.T) {
-	m, _ := newManagerInternal("", time.Now, []Task{})
+	now := time.Date(2024, 4, 9, 14, 30, 0, 0, time.UTC)
+	m, _ := newManagerInternal("", func() time.Time { return now }, []Task{})
 
 	task, err := m.AddTask(
 		"Test Task",
@@ -32,11 +35,12 @@ func TestAddTask(t *testing.T) {
 		t.Fatalf("expected 1 task, got %d", len(tasks))
 	}
 	expectedTask := Task{
-		Id:       1,
-		Title:    "Test Task",
-		Priority: Medium,
-		DueDate:  DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)),
-		Status:   Pending,
+		Id:        1,
+		Title:     "Test Task",
+		Priority:  Medium,
+		DueDate:   DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)),
+		Status:    Pending,
+		CreatedAt: now,
 	}
 	if !reflect.DeepEqual(task, &expectedTask) {
 		t.Fatalf("expected %v, got %v", expectedTask, task)
@@ -89,7 +93,7 @@ func TestListTasksNoFilter(t *testing.T) {
 			Status:   Pending,
 		},
 	}
-	if !slices.Equal(tasks, expectedTasks) {
+	if !reflect.DeepEqual(tasks, expectedTasks) {
 		t.Fatalf("expected %v, got %v", expectedTasks, tasks)
 	}
 }
@@ -135,7 +139,7 @@ func TestListTasksWithFilters(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if len(tasksByStatus) != 1 || tasksByStatus[0] != task2 {
+	if len(tasksByStatus) != 1 || !reflect.DeepEqual(tasksByStatus[0], task2) {
 		t.Fatalf("expected %v, got %v", task2, tasksByStatus)
 	}
 
@@ -145,7 +149,7 @@ func TestListTasksWithFilters(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if len(tasksByPriority) != 1 || tasksByPriority[0] != task3 {
+	if len(tasksByPriority) != 1 || !reflect.DeepEqual(tasksByPriority[0], task3) {
 		t.Fatalf("expected %v, got %v", task3, tasksByPriority)
 	}
 
@@ -155,7 +159,7 @@ func TestListTasksWithFilters(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if len(tasksByCategory) != 1 || tasksByCategory[0] != task1 {
+	if len(tasksByCategory) != 1 || !reflect.DeepEqual(tasksByCategory[0], task1) {
 		t.Fatalf("expected %v, got %v", task1, tasksByCategory)
 	}
 
@@ -165,7 +169,7 @@ func TestListTasksWithFilters(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if len(tasksByCategory) != 1 || tasksByCategory[0] != task2 {
+	if len(tasksByCategory) != 1 || !reflect.DeepEqual(tasksByCategory[0], task2) {
 		t.Fatalf("expected %v, got %v", task2, tasksByCategory)
 	}
 
@@ -174,9 +178,20 @@ func TestListTasksWithFilters(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if len(tasksOverdue) != 1 || tasksOverdue[0] != task1 {
+	if len(tasksOverdue) != 1 || !reflect.DeepEqual(tasksOverdue[0], task1) {
 		t.Fatalf("expected %v, got %v", task1, tasksOverdue)
 	}
+
+	// a task due earlier today is overdue, in lists and queries alike
+	m.(*manager).now = func() time.Time { return time.Date(2024, 4, 12, 9, 0, 0, 0, time.UTC) }
+	tasksOverdue, _ = m.ListTasks(nil, nil, "", true)
+	if len(tasksOverdue) != 2 || tasksOverdue[1].Id != 3 {
+		t.Fatalf("expected tasks 1 and 3 to be overdue, got %v", tasksOverdue)
+	}
+	filter, _ := ParseFilter("is:overdue")
+	if queried, _ := m.QueryTasks(filter); len(queried) != 2 || queried[1].Id != 3 {
+		t.Fatalf("expected tasks 1 and 3 to match is:overdue, got %v", queried)
+	}
 }
 
 func TestSearchTasksTableDriven(t *testing.T) {
@@ -224,11 +239,11 @@ func TestSearchTasksTableDriven(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.query, func(t *testing.T) {
-			actual, err := m.SearchTasks(tt.query)
+			actual, err := m.SearchTasks(tt.query, SearchOptions{})
 			if err != nil {
 				t.Fatalf("expected no error, got %v", err)
 			}
-			if !slices.Equal(actual, tt.expected) {
+			if !reflect.DeepEqual(actual, tt.expected) {
 				t.Fatalf("expected %v, got %v", tt.expected, actual)
 			}
 		})
@@ -283,6 +298,9 @@ func TestCompleteTask(t *testing.T) {
 	if task1Updated.Status != Completed {
 		t.Fatalf("expected task 1 to be completed, got %v", task1Updated.Status)
 	}
+	if task1Updated.CompletedAt.IsZero() {
+		t.Fatalf("expected task 1 to have a completion time")
+	}
 
 	// complete already completed task
 	err = m.CompleteTask(2)
@@ -292,6 +310,76 @@ func TestCompleteTask(t *testing.T) {
 	if !strings.Contains(err.Error(), "already completed") {
 		t.Fatalf("expected 'already completed' error, got %v", err)
 	}
+	if !errors.Is(err, ErrAlreadyCompleted) {
+		t.Fatalf("expected ErrAlreadyCompleted, got %v", err)
+	}
+
+	// complete unknown task
+	if err := m.CompleteTask(999); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateTask(t *testing.T) {
+	m, _ := newManagerInternal(
+		"",
+		nil,
+		[]Task{
+			{Id: 1, Title: "Call dentist", Priority: Medium, Category: "health", Status: Pending, Tags: []string{"phone"}},
+			{Id: 2, Title: "Buy milk", Priority: Low, Status: Completed},
+		},
+	)
+
+	title := "Call the dentist"
+	priority := High
+	due := DueDate(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC))
+	tags := []string{}
+	notes := []string{"Morning only"}
+	task, err := m.UpdateTask(1, TaskPatch{Title: &title, Priority: &priority, DueDate: &due, Tags: &tags, Notes: &notes})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := Task{Id: 1, Title: title, Priority: High, DueDate: due, Category: "health", Status: Pending, Tags: []string{}, Notes: notes}
+	if !reflect.DeepEqual(task, &want) {
+		t.Fatalf("expected %v, got %v", want, task)
+	}
+	got, err := m.GetTask(1)
+	if err != nil || !reflect.DeepEqual(got, &want) {
+		t.Fatalf("expected stored task %v, got %v (err %v)", want, got, err)
+	}
+
+	// the search index sees the new title
+	found, err := m.SearchTasks("the dentist", SearchOptions{Mode: SearchExact})
+	if err != nil || len(found) != 1 {
+		t.Fatalf("expected updated task to be searchable, got %v (err %v)", found, err)
+	}
+
+	// completing through a patch records the time, reopening clears it
+	completed := Completed
+	if task, err := m.UpdateTask(2, TaskPatch{Status: &completed}); err != nil || !task.CompletedAt.IsZero() {
+		t.Fatalf("expected an unchanged status to keep the completion time, got %v (err %v)", task, err)
+	}
+	pending := Pending
+	if task, err := m.UpdateTask(2, TaskPatch{Status: &pending}); err != nil || task.Status != Pending {
+		t.Fatalf("expected task 2 to be reopened, got %v (err %v)", task, err)
+	}
+	if task, err := m.UpdateTask(2, TaskPatch{Status: &completed}); err != nil || task.CompletedAt.IsZero() {
+		t.Fatalf("expected task 2 to have a completion time, got %v (err %v)", task, err)
+	}
+	if task, err := m.UpdateTask(2, TaskPatch{Status: &pending}); err != nil || !task.CompletedAt.IsZero() {
+		t.Fatalf("expected reopening to clear the completion time, got %v (err %v)", task, err)
+	}
+
+	invalid := Status(42)
+	if _, err := m.UpdateTask(1, TaskPatch{Status: &invalid}); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+	if _, err := m.UpdateTask(999, TaskPatch{Title: &title}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := m.GetTask(999); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
 }
 
 func TestDeleteTask(t *testing.T) {
@@ -346,4 +434,185 @@ func TestDeleteTask(t *testing.T) {
 	if !strings.Contains(err.Error(), "not found") {
 		t.Fatalf("expected 'not found' error, got %v", err)
 	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestQueryTasks(t *testing.T) {
+	task1 := Task{
+		Id:       1,
+		Title:    "Call dentist",
+		Priority: Medium,
+		DueDate:  DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)),
+		Category: "Health",
+		Status:   Pending,
+	}
+	task2 := Task{
+		Id:       2,
+		Title:    "File taxes",
+		Priority: High,
+		DueDate:  DueDate(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)),
+		Category: "Finance",
+		Status:   Pending,
+	}
+	nowFunc := func() time.Time { return time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC) }
+	m, _ := newManagerInternal("", nowFunc, []Task{task1, task2})
+
+	filter, err := ParseFilter("status:pending and due<=today")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	tasks, err := m.QueryTasks(filter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(tasks) != 1 || !reflect.DeepEqual(tasks[0], task1) {
+		t.Fatalf("expected %v, got %v", task1, tasks)
+	}
+}
+
+func TestViews(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tasks.db.json")
+	m, err := newManagerInternal(filename, nil, []Task{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	today := View{Name: "today", Query: "status:pending and due<=today", Sort: "priority"}
+	if err := m.SaveView(today); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := m.SaveView(View{Name: "all", Query: ""}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	today.Columns = []string{"id", "title"}
+	if err := m.SaveView(today); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// views persist alongside the task data
+	m, err = newManagerInternal(filename, nil, []Task{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	views, err := m.ListViews()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(views) != 2 || views[0].Name != "all" || views[1].Name != "today" {
+		t.Fatalf("expected views [all today], got %v", views)
+	}
+	view, err := m.GetView("today")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(view, &today) {
+		t.Fatalf("expected %v, got %v", today, view)
+	}
+
+	// delete view
+	if err := m.DeleteView("all"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := m.GetView("all"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected 'not found' error, got %v", err)
+	}
+	if err := m.DeleteView("all"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStorageErrors(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tasks.db.json")
+	if err := os.WriteFile(filename, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := newManagerInternal(filename, nil, []Task{}); !errors.Is(err, ErrStorage) {
+		t.Fatalf("expected ErrStorage for corrupt file, got %v", err)
+	}
+
+	m, err := newManagerInternal(filepath.Join(t.TempDir(), "missing", "tasks.db.json"), nil, []Task{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, err = m.AddTask("Call dentist", Medium, func(now time.Time) DueDate { return DueDate(now) }, "")
+	if !errors.Is(err, ErrStorage) || !strings.Contains(err.Error(), "write") {
+		t.Fatalf("expected ErrStorage write error, got %v", err)
+	}
+}
+
+func TestCheckTransitionTableDriven(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		want error
+	}{
+		{Pending, Completed, nil},
+		{Completed, Pending, nil},
+		{Completed, Completed, ErrAlreadyCompleted},
+		{Pending, Pending, ErrInvalidTransition},
+		{Pending, Status(42), ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		err := checkTransition(1, tt.from, tt.to)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Fatalf("%v -> %v: expected %v, got %v", tt.from.String(), tt.to.String(), tt.want, err)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tasks.db.json")
+	m, err := newManagerInternal(filename, nil, []Task{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	due := func(now time.Time) DueDate { return DueDate(now) }
+	if _, err := m.AddTask("Call dentist", Medium, due, ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	b := m.(Batcher)
+	b.Begin()
+	if _, err := m.AddTask("Buy milk", Low, due, ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := m.CompleteTask(1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := m.SaveView(View{Name: "today", Query: "due=today"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if b.Pending() != 3 {
+		t.Fatalf("expected 3 pending changes, got %d", b.Pending())
+	}
+	onDisk, _ := newManagerInternal(filename, nil, []Task{})
+	if tasks, _ := onDisk.ListTasks(nil, nil, "", false); len(tasks) != 1 || tasks[0].Status != Pending {
+		t.Fatalf("expected batched changes not to be written, got %v", tasks)
+	}
+
+	b.Rollback()
+	if tasks, _ := m.ListTasks(nil, nil, "", false); len(tasks) != 1 || tasks[0].Status != Pending {
+		t.Fatalf("expected rollback to restore tasks, got %v", tasks)
+	}
+	if views, _ := m.ListViews(); len(views) != 0 {
+		t.Fatalf("expected rollback to restore views, got %v", views)
+	}
+
+	b.Begin()
+	task, err := m.AddTask("Buy milk", Low, due, "")
+	if err != nil || task.Id != 2 {
+		t.Fatalf("expected rollback to restore the next id, got %v, %v", task, err)
+	}
+	if err := b.Commit(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if b.Pending() != 0 {
+		t.Fatalf("expected no pending changes after commit, got %d", b.Pending())
+	}
+	onDisk, _ = newManagerInternal(filename, nil, []Task{})
+	if tasks, _ := onDisk.ListTasks(nil, nil, "", false); len(tasks) != 2 {
+		t.Fatalf("expected committed changes to be written, got %v", tasks)
+	}
 }
@@ -0,0 +1,104 @@
+package tasks
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	orgPriority     = map[Priority]string{High: "A", Medium: "B", Low: "C"}
+	orgTagCharRegex = regexp.MustCompile(`[^\pL\pN_@#%]`)
+	orgRepeaterUnit = map[string]string{"DAILY": "d", "WEEKLY": "w", "MONTHLY": "m", "YEARLY": "y"}
+)
+
+// ExportOrg writes one Org-mode headline per task, such as
+// "* TODO [#A] Call dentist :phone:", followed by a DEADLINE line. The
+// category and UID go in a property drawer, and the description and notes
+// in the body.
+func ExportOrg(w io.Writer, t []Task, _ time.Time) ([]Issue, error) {
+	var issues []Issue
+	bw := bufio.NewWriter(w)
+	for _, task := range t {
+		keyword := "TODO"
+		if task.Status == Completed {
+			keyword = "DONE"
+		}
+		headline := fmt.Sprintf("* %s [#%s] %s", keyword, orgPriority[task.Priority], strings.Join(strings.Fields(task.Title), " "))
+		var tags []string
+		for _, tag := range task.Tags {
+			orgTag := orgTagCharRegex.ReplaceAllString(tag, "_")
+			if orgTag != tag {
+				issues = append(issues, Issue{Message: fmt.Sprintf("task %d: tag '%s' written as :%s:", task.Id, tag, orgTag)})
+			}
+			tags = append(tags, orgTag)
+		}
+		if len(tags) > 0 {
+			headline += " :" + strings.Join(tags, ":") + ":"
+		}
+		bw.WriteString(headline + "\n")
+
+		repeater, ok := orgRepeater(task.Recurrence)
+		if !ok {
+			issues = append(issues, Issue{Message: fmt.Sprintf("task %d: recurrence '%s' has no Org repeater and was dropped", task.Id, task.Recurrence)})
+		}
+		planning := fmt.Sprintf("DEADLINE: <%s%s>", time.Time(task.DueDate).Format("2006-01-02 Mon"), repeater)
+		if task.Status == Completed && !task.CompletedAt.IsZero() {
+			planning = fmt.Sprintf("CLOSED: [%s] %s", task.CompletedAt.Format("2006-01-02 Mon 15:04"), planning)
+		}
+		bw.WriteString("  " + planning + "\n")
+
+		bw.WriteString("  :PROPERTIES:\n")
+		bw.WriteString("  :ID:       " + TaskUID(&task) + "\n")
+		if task.Category != "" {
+			bw.WriteString("  :CATEGORY: " + task.Category + "\n")
+		}
+		if !task.CreatedAt.IsZero() {
+			bw.WriteString("  :CREATED:  [" + task.CreatedAt.Format("2006-01-02 Mon 15:04") + "]\n")
+		}
+		bw.WriteString("  :END:\n")
+
+		// body lines are indented so none can start a new headline
+		for _, line := range strings.Split(strings.TrimSpace(task.Description), "\n") {
+			if line = strings.TrimRight(line, " \t"); line != "" {
+				bw.WriteString("  " + line + "\n")
+			}
+		}
+		for _, note := range task.Notes {
+			bw.WriteString("  - " + strings.Join(strings.Fields(note), " ") + "\n")
+		}
+	}
+	return issues, bw.Flush()
+}
+
+// orgRepeater turns a simple RRULE such as "FREQ=WEEKLY;INTERVAL=2" into an
+// Org repeater such as " +2w". It reports false for rules Org cannot express.
+func orgRepeater(rule string) (string, bool) {
+	if rule == "" {
+		return "", true
+	}
+	unit, interval := "", 1
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			unit = orgRepeaterUnit[strings.ToUpper(value)]
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return "", false
+			}
+			interval = n
+		default:
+			return "", false
+		}
+	}
+	if unit == "" {
+		return "", false
+	}
+	return fmt.Sprintf(" +%d%s", interval, unit), true
+}
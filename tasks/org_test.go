@@ -0,0 +1,56 @@
+package tasks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportOrg(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC) }
+	in := []Task{
+		{Id: 1, Title: "Call dentist", Priority: High, DueDate: DueDate(day(12)), Category: "health", Status: Pending,
+			Tags: []string{"phone", "q-2"}, Description: "Ask about\n* the bill", Notes: []string{"Morning only"},
+			CreatedAt: day(1), UID: "uid-1", Recurrence: "FREQ=MONTHLY;INTERVAL=6"},
+		{Id: 2, Title: "File taxes", Priority: Low, DueDate: DueDate(day(9)), Status: Completed,
+			CompletedAt: time.Date(2024, 4, 8, 17, 30, 0, 0, time.UTC), UID: "uid-2", Recurrence: "FREQ=WEEKLY;BYDAY=MO"},
+	}
+
+	sb := strings.Builder{}
+	issues, err := ExportOrg(&sb, in, day(10))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := `* TODO [#A] Call dentist :phone:q_2:
+  DEADLINE: <2024-04-12 Fri +6m>
+  :PROPERTIES:
+  :ID:       uid-1
+  :CATEGORY: health
+  :CREATED:  [2024-04-01 Mon 00:00]
+  :END:
+  Ask about
+  * the bill
+  - Morning only
+* DONE [#C] File taxes
+  CLOSED: [2024-04-08 Mon 17:30] DEADLINE: <2024-04-09 Tue>
+  :PROPERTIES:
+  :ID:       uid-2
+  :END:
+`
+	if sb.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, sb.String())
+	}
+
+	got := []string{}
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	wantIssues := []string{
+		"task 1: tag 'q-2' written as :q_2:",
+		"task 2: recurrence 'FREQ=WEEKLY;BYDAY=MO' has no Org repeater and was dropped",
+	}
+	if !reflect.DeepEqual(got, wantIssues) {
+		t.Errorf("expected issues %q, got %q", wantIssues, got)
+	}
+}
@@ -0,0 +1,260 @@
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Filter struct {
+	expr       string
+	conditions []condition
+}
+
+type condition func(t *Task, now time.Time) bool
+
+var conditionRegex = regexp.MustCompile(`^([a-zA-Z]+)(<=|>=|!=|<|>|=|:)(.*)$`)
+
+func ParseFilter(expr string) (Filter, error) {
+	f := Filter{expr: strings.TrimSpace(expr)}
+	tokens, err := splitQuery(f.expr)
+	if err != nil {
+		return Filter{}, err
+	}
+	for _, token := range tokens {
+		if strings.EqualFold(token, "and") {
+			continue
+		}
+		match := conditionRegex.FindStringSubmatch(token)
+		if match == nil {
+			return Filter{}, fmt.Errorf("invalid query term %q: expected field:value or field<op>value", token)
+		}
+		c, err := parseCondition(strings.ToLower(match[1]), match[2], match[3])
+		if err != nil {
+			return Filter{}, err
+		}
+		f.conditions = append(f.conditions, c)
+	}
+	return f, nil
+}
+
+func (f Filter) String() string {
+	return f.expr
+}
+
+func (f Filter) Matches(t *Task, now time.Time) bool {
+	for _, c := range f.conditions {
+		if !c(t, now) {
+			return false
+		}
+	}
+	return true
+}
+
+func splitQuery(expr string) ([]string, error) {
+	tokens := make([]string, 0)
+	sb := strings.Builder{}
+	inQuotes := false
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ' ' && !inQuotes:
+			if sb.Len() > 0 {
+				tokens = append(tokens, sb.String())
+				sb.Reset()
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("invalid query: unterminated quote")
+	}
+	if sb.Len() > 0 {
+		tokens = append(tokens, sb.String())
+	}
+	return tokens, nil
+}
+
+func parseCondition(field string, op string, value string) (condition, error) {
+	switch field {
+	case "id":
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id in query: %q", value)
+		}
+		return compareOp(op, func(t *Task, _ time.Time) int { return t.Id - id })
+	case "title":
+		v := strings.ToLower(value)
+		switch op {
+		case ":":
+			return func(t *Task, _ time.Time) bool { return strings.Contains(strings.ToLower(t.Title), v) }, nil
+		case "=":
+			return func(t *Task, _ time.Time) bool { return strings.EqualFold(t.Title, value) }, nil
+		case "!=":
+			return func(t *Task, _ time.Time) bool { return !strings.EqualFold(t.Title, value) }, nil
+		}
+	case "category":
+		switch op {
+		case ":", "=":
+			return func(t *Task, _ time.Time) bool { return strings.EqualFold(t.Category, value) }, nil
+		case "!=":
+			return func(t *Task, _ time.Time) bool { return !strings.EqualFold(t.Category, value) }, nil
+		}
+	case "status":
+		s, ok := stringToStatus[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("invalid status in query: %q", value)
+		}
+		switch op {
+		case ":", "=":
+			return func(t *Task, _ time.Time) bool { return t.Status == s }, nil
+		case "!=":
+			return func(t *Task, _ time.Time) bool { return t.Status != s }, nil
+		}
+	case "priority":
+		p, ok := stringToPriority[strings.ToUpper(value)]
+		if !ok {
+			return nil, fmt.Errorf("invalid priority in query: %q", value)
+		}
+		return compareOp(op, func(t *Task, _ time.Time) int { return int(t.Priority) - int(p) })
+	case "due":
+		resolve, err := parseQueryDate(value)
+		if err != nil {
+			return nil, err
+		}
+		return compareOp(op, func(t *Task, now time.Time) int {
+			return startOfDay(time.Time(t.DueDate)).Compare(resolve(now))
+		})
+	case "is":
+		switch strings.ToLower(value) {
+		case "overdue":
+			return func(t *Task, now time.Time) bool { return IsOverdue(t, now) }, nil
+		case "pending":
+			return func(t *Task, _ time.Time) bool { return t.Status == Pending }, nil
+		case "completed":
+			return func(t *Task, _ time.Time) bool { return t.Status == Completed }, nil
+		}
+		return nil, fmt.Errorf("invalid query term is:%s: must be 'overdue', 'pending', or 'completed'", value)
+	default:
+		return nil, fmt.Errorf("invalid query field %q", field)
+	}
+	return nil, fmt.Errorf("invalid query operator %q for field %q", op, field)
+}
+
+func compareOp(op string, cmp func(t *Task, now time.Time) int) (condition, error) {
+	switch op {
+	case ":", "=":
+		return func(t *Task, now time.Time) bool { return cmp(t, now) == 0 }, nil
+	case "!=":
+		return func(t *Task, now time.Time) bool { return cmp(t, now) != 0 }, nil
+	case "<":
+		return func(t *Task, now time.Time) bool { return cmp(t, now) < 0 }, nil
+	case "<=":
+		return func(t *Task, now time.Time) bool { return cmp(t, now) <= 0 }, nil
+	case ">":
+		return func(t *Task, now time.Time) bool { return cmp(t, now) > 0 }, nil
+	case ">=":
+		return func(t *Task, now time.Time) bool { return cmp(t, now) >= 0 }, nil
+	}
+	return nil, fmt.Errorf("invalid query operator %q", op)
+}
+
+var queryDaysRegex = regexp.MustCompile(`^([+-]\d+)d$`)
+
+func parseQueryDate(value string) (func(time.Time) time.Time, error) {
+	offset := func(days int) func(time.Time) time.Time {
+		return func(now time.Time) time.Time { return startOfDay(now).AddDate(0, 0, days) }
+	}
+	switch {
+	case value == "today":
+		return offset(0), nil
+	case value == "tomorrow":
+		return offset(1), nil
+	case value == "yesterday":
+		return offset(-1), nil
+	case queryDaysRegex.MatchString(value):
+		days, err := strconv.Atoi(queryDaysRegex.FindStringSubmatch(value)[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date in query: %v", err)
+		}
+		return offset(days), nil
+	}
+	at, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date in query %q: use today, tomorrow, yesterday, +Xd, -Xd, or yyyy-MM-dd", value)
+	}
+	return func(time.Time) time.Time { return at }, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// IsOverdue reports whether t is pending and its due time has passed. It is
+// the one rule for list -overdue, is:overdue, the overdue marker, agenda and
+// stats.
+func IsOverdue(t *Task, now time.Time) bool {
+	return t.Status != Completed && time.Time(t.DueDate).Before(now)
+}
+
+type SortKey struct {
+	field string
+	desc  bool
+}
+
+var SortFields = []string{"id", "title", "priority", "due", "category", "status"}
+
+func ParseSortKey(s string) (SortKey, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return SortKey{}, nil
+	}
+	k := SortKey{field: strings.TrimPrefix(s, "-"), desc: strings.HasPrefix(s, "-")}
+	if !slices.Contains(SortFields, k.field) {
+		return SortKey{}, fmt.Errorf("invalid sort key %q: must be one of %s", s, strings.Join(SortFields, ", "))
+	}
+	return k, nil
+}
+
+func (k SortKey) String() string {
+	if k.desc {
+		return "-" + k.field
+	}
+	return k.field
+}
+
+func SortTasks(t []Task, k SortKey) {
+	var cmp func(a, b *Task) int
+	switch k.field {
+	case "id":
+		cmp = func(a, b *Task) int { return a.Id - b.Id }
+	case "title":
+		cmp = func(a, b *Task) int { return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)) }
+	case "priority":
+		cmp = func(a, b *Task) int { return int(b.Priority) - int(a.Priority) }
+	case "due":
+		cmp = func(a, b *Task) int { return time.Time(a.DueDate).Compare(time.Time(b.DueDate)) }
+	case "category":
+		cmp = func(a, b *Task) int { return strings.Compare(strings.ToLower(a.Category), strings.ToLower(b.Category)) }
+	case "status":
+		cmp = func(a, b *Task) int { return int(a.Status) - int(b.Status) }
+	default:
+		return
+	}
+	slices.SortStableFunc(t, func(a, b Task) int {
+		c := cmp(&a, &b)
+		if k.desc {
+			c = -c
+		}
+		if c == 0 {
+			return a.Id - b.Id
+		}
+		return c
+	})
+}
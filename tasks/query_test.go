@@ -0,0 +1,146 @@
+package tasks
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFilterInvalidTableDriven(t *testing.T) {
+	tests := []struct {
+		expr   string
+		errMsg string
+	}{
+		{"pending", "invalid query term"},
+		{"mood:happy", "invalid query field"},
+		{"status:maybe", "invalid status"},
+		{"priority:urgent", "invalid priority"},
+		{"due<=someday", "invalid date"},
+		{"status<pending", "invalid query operator"},
+		{`category:"pet stuff`, "unterminated quote"},
+		{"is:late", "invalid query term"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseFilter(tt.expr)
+			if err == nil {
+				t.Fatalf("expected error, got none")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("expected error to contain '%v', got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestFilterMatchesTableDriven(t *testing.T) {
+	task1 := Task{
+		Id:       1,
+		Title:    "Call dentist",
+		Priority: Medium,
+		DueDate:  DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)),
+		Category: "Health",
+		Status:   Pending,
+	}
+	task2 := Task{
+		Id:       2,
+		Title:    "Buy milk",
+		Priority: Low,
+		DueDate:  DueDate(time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC)),
+		Category: "Groceries",
+		Status:   Completed,
+	}
+	task3 := Task{
+		Id:       3,
+		Title:    "File taxes",
+		Priority: High,
+		DueDate:  DueDate(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)),
+		Category: "pet stuff",
+		Status:   Pending,
+	}
+	all := []Task{task1, task2, task3}
+	now := time.Date(2024, 4, 11, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		expr     string
+		expected []int
+	}{
+		{"", []int{1, 2, 3}},
+		{"status:pending", []int{1, 3}},
+		{"status!=pending", []int{2}},
+		{"status:pending and due<=today", []int{1}},
+		{"status:pending AND due<=today", []int{1}},
+		{"due=today", []int{2}},
+		{"due>=tomorrow", []int{3}},
+		{"due<+1d", []int{1, 2}},
+		{"due>2024-04-10", []int{2, 3}},
+		{"priority>=medium", []int{1, 3}},
+		{"priority<high priority>low", []int{1}},
+		{"category:health", []int{1}},
+		{`category:"pet stuff"`, []int{3}},
+		{"title:milk", []int{2}},
+		{"id>1", []int{2, 3}},
+		{"is:overdue", []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			actual := make([]int, 0)
+			for _, task := range all {
+				if f.Matches(&task, now) {
+					actual = append(actual, task.Id)
+				}
+			}
+			if !slices.Equal(actual, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSortTasksTableDriven(t *testing.T) {
+	tasks := []Task{
+		{Id: 1, Title: "b", Priority: Low, DueDate: DueDate(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC))},
+		{Id: 2, Title: "c", Priority: High, DueDate: DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC))},
+		{Id: 3, Title: "a", Priority: Medium, DueDate: DueDate(time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC))},
+		{Id: 4, Title: "d", Priority: High, DueDate: DueDate(time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC))},
+	}
+	tests := []struct {
+		key      string
+		expected []int
+	}{
+		{"", []int{1, 2, 3, 4}},
+		{"priority", []int{2, 4, 3, 1}},
+		{"-priority", []int{1, 3, 2, 4}},
+		{"due", []int{2, 3, 4, 1}},
+		{"title", []int{3, 1, 2, 4}},
+		{"-id", []int{4, 3, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			k, err := ParseSortKey(tt.key)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			sorted := slices.Clone(tasks)
+			SortTasks(sorted, k)
+			actual := make([]int, 0)
+			for _, task := range sorted {
+				actual = append(actual, task.Id)
+			}
+			if !slices.Equal(actual, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+
+	if _, err := ParseSortKey("colour"); err == nil {
+		t.Fatalf("expected error for invalid sort key, got none")
+	}
+}
@@ -0,0 +1,307 @@
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const remoteTimeout = 10 * time.Second
+
+type remoteManager struct {
+	base   string
+	client *http.Client
+
+	// etags holds the ETag of each task as last read, sent back in If-Match
+	// so that a change based on a stale read is refused.
+	mu    sync.Mutex
+	etags map[int]string
+
+	clock     sync.Once
+	skew      time.Duration
+	serverLoc *time.Location
+}
+
+// NewRemoteManager returns a Manager backed by the task server at baseURL,
+// as started by 'todo serve'. A nil client uses one with a short timeout.
+// Nothing is sent until the first call, which fails with ErrUnavailable if
+// the server cannot be reached.
+func NewRemoteManager(baseURL string, client *http.Client) (Manager, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid remote URL '%s': must be like http://host:port", baseURL)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: remoteTimeout}
+	}
+	return &remoteManager{base: strings.TrimSuffix(u.String(), "/"), client: client, etags: map[int]string{}}, nil
+}
+
+// remoteError carries the message of an error reply, so it reads the same
+// as the error a local manager would return, and the matching sentinel.
+type remoteError struct {
+	msg  string
+	kind error
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.kind
+}
+
+var errorCodes = map[string]error{
+	"not_found":   ErrNotFound,
+	"conflict":    ErrInvalidTransition,
+	"storage":     ErrStorage,
+	"unavailable": ErrUnavailable,
+	"rejected":    ErrRejected,
+
+	"precondition_failed": ErrChanged,
+}
+
+// do sends a request and decodes the reply into out, if it is not nil.
+func (m *remoteManager) do(method string, path string, query url.Values, body any, out any) error {
+	_, err := m.send(method, path, query, body, out, "")
+	return err
+}
+
+// send is do with an If-Match header, when ifMatch is not empty. It returns
+// the ETag of the reply.
+func (m *remoteManager) send(method string, path string, query url.Values, body any, out any, ifMatch string) (string, error) {
+	target := m.base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return "", err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return "", err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("%w at %s: %v", ErrUnavailable, m.base, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiErr APIError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+			return "", fmt.Errorf("unexpected reply from %s: %s (is it a task server?)", m.base, resp.Status)
+		}
+		if apiErr.Code == "conflict" && strings.Contains(apiErr.Error, ErrAlreadyCompleted.Error()) {
+			return "", &remoteError{apiErr.Error, ErrAlreadyCompleted}
+		}
+		return "", &remoteError{apiErr.Error, errorCodes[apiErr.Code]}
+	}
+	etag := resp.Header.Get("ETag")
+	if out == nil {
+		return etag, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("invalid reply from %s: %v", m.base, err)
+	}
+	return etag, nil
+}
+
+func (m *remoteManager) AddTask(title string, priority Priority, getDueDate func(time.Time) DueDate, category string, opts ...TaskOption) (*Task, error) {
+	var t Task
+	for _, opt := range opts {
+		opt(&t)
+	}
+	due := getDueDate(m.Now())
+	n := NewTask{
+		Title:       title,
+		Priority:    &priority,
+		DueDate:     &due,
+		Category:    category,
+		Status:      t.Status,
+		Description: t.Description,
+		Notes:       t.Notes,
+		Tags:        t.Tags,
+		CreatedAt:   t.CreatedAt,
+		CompletedAt: t.CompletedAt,
+		UID:         t.UID,
+		Recurrence:  t.Recurrence,
+	}
+	var added Task
+	if err := m.do("POST", "/tasks", nil, n, &added); err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+func (m *remoteManager) ListTasks(status *Status, priority *Priority, category string, overdueOnly bool) ([]Task, error) {
+	var t []Task
+	err := m.do("GET", "/tasks", filterQuery(status, priority, category, overdueOnly), nil, &t)
+	return t, err
+}
+
+func (m *remoteManager) SearchTasks(query string, opts SearchOptions) ([]Task, error) {
+	q := filterQuery(opts.Status, opts.Priority, opts.Category, opts.OverdueOnly)
+	q.Set("q", query)
+	switch opts.Mode {
+	case SearchExact:
+		q.Set("mode", "exact")
+	case SearchRegex:
+		q.Set("mode", "regex")
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	var t []Task
+	err := m.do("GET", "/tasks/search", q, nil, &t)
+	return t, err
+}
+
+func filterQuery(status *Status, priority *Priority, category string, overdueOnly bool) url.Values {
+	q := url.Values{}
+	if status != nil {
+		q.Set("status", status.String())
+	}
+	if priority != nil {
+		q.Set("priority", strings.ToLower(priority.String()))
+	}
+	if category != "" {
+		q.Set("category", category)
+	}
+	if overdueOnly {
+		q.Set("overdue", "true")
+	}
+	return q
+}
+
+func (m *remoteManager) GetTask(id int) (*Task, error) {
+	var t Task
+	etag, err := m.send("GET", fmt.Sprintf("/tasks/%d", id), nil, nil, &t, "")
+	if err != nil {
+		return nil, err
+	}
+	m.setETag(id, etag)
+	return &t, nil
+}
+
+// UpdateTask, CompleteTask and DeleteTask fail with ErrChanged if the task
+// was read with GetTask and someone else has changed it since.
+func (m *remoteManager) UpdateTask(id int, patch TaskPatch) (*Task, error) {
+	var t Task
+	etag, err := m.send("PATCH", fmt.Sprintf("/tasks/%d", id), nil, patch, &t, m.etag(id))
+	m.setETag(id, etag)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (m *remoteManager) CompleteTask(id int) error {
+	etag, err := m.send("POST", fmt.Sprintf("/tasks/%d/complete", id), nil, nil, nil, m.etag(id))
+	m.setETag(id, etag)
+	return err
+}
+
+func (m *remoteManager) DeleteTask(id int) error {
+	_, err := m.send("DELETE", fmt.Sprintf("/tasks/%d", id), nil, nil, nil, m.etag(id))
+	m.setETag(id, "")
+	return err
+}
+
+func (m *remoteManager) etag(id int) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.etags[id]
+}
+
+// setETag records the ETag of a reply. An empty one, as after an error,
+// forgets the task's version, so the next change is not checked.
+func (m *remoteManager) setETag(id int, etag string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if etag == "" {
+		delete(m.etags, id)
+		return
+	}
+	m.etags[id] = etag
+}
+
+// QueryTasks sends the filter's expression, so relative dates such as
+// 'today' are resolved by the server's clock.
+func (m *remoteManager) QueryTasks(filter Filter) ([]Task, error) {
+	var t []Task
+	err := m.do("GET", "/tasks/query", url.Values{"q": {filter.String()}}, nil, &t)
+	return t, err
+}
+
+func (m *remoteManager) SaveView(view View) error {
+	if strings.TrimSpace(view.Name) == "" {
+		return fmt.Errorf("view name cannot be empty")
+	}
+	return m.do("PUT", "/views/"+url.PathEscape(view.Name), nil, view, nil)
+}
+
+func (m *remoteManager) GetView(name string) (*View, error) {
+	var v View
+	if err := m.do("GET", "/views/"+url.PathEscape(name), nil, nil, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (m *remoteManager) ListViews() ([]View, error) {
+	var v []View
+	err := m.do("GET", "/views", nil, nil, &v)
+	return v, err
+}
+
+func (m *remoteManager) DeleteView(name string) error {
+	return m.do("DELETE", "/views/"+url.PathEscape(name), nil, nil, nil)
+}
+
+// Now returns the server's time in its time zone, so that 'today' is the
+// same day for the client as for the server. The server is asked once and the
+// local clock is used with the offset found; if it cannot be asked, the local
+// clock is used as is.
+func (m *remoteManager) Now() time.Time {
+	m.clock.Do(func() {
+		var st ServerTime
+		sent := time.Now()
+		if err := m.do("GET", "/now", nil, nil, &st); err != nil || st.Now.IsZero() {
+			return
+		}
+		// assume the server read its clock halfway through the request
+		m.skew = st.Now.Sub(sent.Add(time.Since(sent) / 2))
+		m.serverLoc = st.Now.Location()
+	})
+	if m.serverLoc == nil {
+		return time.Now()
+	}
+	return time.Now().Add(m.skew).In(m.serverLoc)
+}
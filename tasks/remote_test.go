@@ -0,0 +1,212 @@
+package tasks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRemote(t *testing.T) Manager {
+	t.Helper()
+	srv := newTestServer(t)
+	m, err := NewRemoteManager(srv.URL+"/", srv.Client())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	return m
+}
+
+func taskIds(t []Task) []int {
+	ids := []int{}
+	for _, task := range t {
+		ids = append(ids, task.Id)
+	}
+	return ids
+}
+
+func TestNewRemoteManagerRejectsBadURLs(t *testing.T) {
+	for _, u := range []string{"localhost:8080", "ftp://host", "http://", "http://host/?x=1", "%"} {
+		if _, err := NewRemoteManager(u, nil); err == nil || !strings.Contains(err.Error(), "invalid remote URL") {
+			t.Errorf("%q: expected an invalid URL error, got %v", u, err)
+		}
+	}
+}
+
+func TestRemoteManagerTasks(t *testing.T) {
+	m := newTestRemote(t)
+	pending, high := Pending, High
+
+	listed, err := m.ListTasks(&pending, nil, "", false)
+	if err != nil || !reflect.DeepEqual(taskIds(listed), []int{1, 2}) {
+		t.Fatalf("unexpected list %v (%v)", taskIds(listed), err)
+	}
+	listed, _ = m.ListTasks(nil, &high, "health", false)
+	if !reflect.DeepEqual(taskIds(listed), []int{1}) {
+		t.Fatalf("unexpected filtered list %v", taskIds(listed))
+	}
+	listed, _ = m.ListTasks(nil, nil, "", true)
+	if !reflect.DeepEqual(taskIds(listed), []int{2}) {
+		t.Fatalf("unexpected overdue list %v", taskIds(listed))
+	}
+	found, err := m.SearchTasks("rent", SearchOptions{Mode: SearchExact, Limit: 1})
+	if err != nil || !reflect.DeepEqual(taskIds(found), []int{2}) {
+		t.Fatalf("unexpected search %v (%v)", taskIds(found), err)
+	}
+	filter, _ := ParseFilter("status:completed")
+	queried, err := m.QueryTasks(filter)
+	if err != nil || !reflect.DeepEqual(taskIds(queried), []int{3}) {
+		t.Fatalf("unexpected query %v (%v)", taskIds(queried), err)
+	}
+
+	due := DueDate(time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC))
+	added, err := m.AddTask("Water plants", Low, func(time.Time) DueDate { return due }, "home",
+		WithTags("garden"), WithNotes("twice"), WithCompletion(time.Time{}))
+	if err != nil || added.Id != 4 || added.Priority != Low || added.Status != Completed ||
+		!reflect.DeepEqual(added.Tags, []string{"garden"}) || time.Time(added.DueDate).Format("2006-01-02") != "2024-04-20" {
+		t.Fatalf("unexpected added task %+v (%v)", added, err)
+	}
+
+	title := "Call the dentist"
+	updated, err := m.UpdateTask(1, TaskPatch{Title: &title})
+	if err != nil || updated.Title != title {
+		t.Fatalf("unexpected update %+v (%v)", updated, err)
+	}
+	if err := m.CompleteTask(1); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got, _ := m.GetTask(1); got.Status != Completed {
+		t.Fatalf("expected task 1 to be completed, got %+v", got)
+	}
+	if err := m.DeleteTask(2); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestRemoteManagerErrors(t *testing.T) {
+	m := newTestRemote(t)
+	tests := []struct {
+		name string
+		call func() error
+		want error
+		msg  string
+	}{
+		{name: "get missing", call: func() error { _, err := m.GetTask(9); return err }, want: ErrNotFound, msg: "task 9 not found"},
+		{name: "delete missing", call: func() error { return m.DeleteTask(9) }, want: ErrNotFound},
+		{name: "complete twice", call: func() error { return m.CompleteTask(3) }, want: ErrAlreadyCompleted, msg: "task 3 is already completed"},
+		{name: "bad regex", call: func() error { _, err := m.SearchTasks("(", SearchOptions{Mode: SearchRegex}); return err }, msg: "invalid regular expression"},
+		{name: "missing view", call: func() error { _, err := m.GetView("nope"); return err }, want: ErrNotFound, msg: "view 'nope' not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) || !strings.Contains(err.Error(), tt.msg) {
+				t.Fatalf("expected %v with %q, got %v", tt.want, tt.msg, err)
+			}
+		})
+	}
+}
+
+func TestRemoteManagerViews(t *testing.T) {
+	m := newTestRemote(t)
+	view := View{Name: "work week", Query: "category:work", Sort: "due", Columns: []string{"id", "title"}}
+	if err := m.SaveView(view); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	got, err := m.GetView("work week")
+	if err != nil || !reflect.DeepEqual(*got, view) {
+		t.Fatalf("expected %+v, got %+v (%v)", view, got, err)
+	}
+	views, err := m.ListViews()
+	if err != nil || len(views) != 1 {
+		t.Fatalf("unexpected views %+v (%v)", views, err)
+	}
+	if err := m.DeleteView("work week"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if err := m.DeleteView("work week"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestRemoteManagerUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	m, _ := NewRemoteManager(srv.URL, nil)
+	if _, err := m.GetTask(1); err == nil || !strings.Contains(err.Error(), "is it a task server?") {
+		t.Fatalf("expected an unexpected reply error, got %v", err)
+	}
+
+	srv.Close()
+	_, err := m.ListTasks(nil, nil, "", false)
+	if !errors.Is(err, ErrUnavailable) || !strings.HasPrefix(err.Error(), "task server unavailable at "+srv.URL+": ") {
+		t.Fatalf("expected an unavailable error, got %v", err)
+	}
+}
+
+func TestRemoteManagerStaleChanges(t *testing.T) {
+	srv := newTestServer(t)
+	a, _ := NewRemoteManager(srv.URL, srv.Client())
+	b, _ := NewRemoteManager(srv.URL, srv.Client())
+
+	// a change based on a read that someone else has since overtaken is refused
+	a.GetTask(1)
+	title := "Call the dentist"
+	if _, err := b.UpdateTask(1, TaskPatch{Title: &title}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	high := High
+	if _, err := a.UpdateTask(1, TaskPatch{Priority: &high}); !errors.Is(err, ErrChanged) || err.Error() != "task 1: task has changed since it was read" {
+		t.Fatalf("expected a stale update to be refused, got %v", err)
+	}
+	if _, err := a.GetTask(1); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := a.UpdateTask(1, TaskPatch{Priority: &high}); err != nil {
+		t.Fatalf("expected an update after a fresh read, got %v", err)
+	}
+	// the reply to a change is the new version
+	if err := a.CompleteTask(1); err != nil {
+		t.Fatalf("expected a change after a change to be allowed, got %v", err)
+	}
+
+	a.GetTask(2)
+	if err := b.CompleteTask(2); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if err := a.DeleteTask(2); !errors.Is(err, ErrChanged) {
+		t.Fatalf("expected a stale delete to be refused, got %v", err)
+	}
+	// without a read, there is nothing to check against
+	if err := a.DeleteTask(2); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestRemoteManagerNow(t *testing.T) {
+	// late on the 10th in UTC is already the 11th for the server
+	zone := time.FixedZone("UTC+14", 14*60*60)
+	serverNow := time.Date(2024, 4, 10, 23, 0, 0, 0, time.UTC).In(zone)
+	m, _ := newManagerInternal("", func() time.Time { return serverNow }, []Task{})
+	srv := httptest.NewServer(NewHandler(m))
+	defer srv.Close()
+
+	remote, _ := NewRemoteManager(srv.URL, srv.Client())
+	got := remote.Now()
+	if d := got.Sub(serverNow); d < 0 || d > time.Second || got.Day() != 11 {
+		t.Fatalf("expected the server's time %v, got %v", serverNow, got)
+	}
+	if _, offset := got.Zone(); offset != 14*60*60 {
+		t.Fatalf("expected the server's time zone, got %v", got)
+	}
+
+	// servers without /now leave the local clock
+	old := httptest.NewServer(http.NotFoundHandler())
+	defer old.Close()
+	remote, _ = NewRemoteManager(old.URL, old.Client())
+	if d := time.Since(remote.Now()); d < 0 || d > time.Second {
+		t.Fatalf("expected the local time, got %v", remote.Now())
+	}
+}
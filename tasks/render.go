@@ -1,42 +1,344 @@
 package tasks
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 	"text/tabwriter"
 	"time"
+	"unicode/utf8"
 )
 
+type column struct {
+	header string
+	value  func(t *Task, opts *TableOptions) string
+}
+
+var columns = map[string]column{
+	"id":       {"ID", func(t *Task, _ *TableOptions) string { return fmt.Sprint(t.Id) }},
+	"title":    {"Title", func(t *Task, _ *TableOptions) string { return t.Title }},
+	"priority": {"Priority", func(t *Task, _ *TableOptions) string { return t.Priority.String() }},
+	"due": {"Due Date", func(t *Task, opts *TableOptions) string {
+		if opts.RelativeDue {
+			return RelativeDue(t.DueDate, opts.Now)
+		}
+		if opts.DateFormat != "" {
+			return time.Time(t.DueDate).Format(opts.DateFormat)
+		}
+		return time.Time(t.DueDate).Format("2006-01-02")
+	}},
+	"category": {"Category", func(t *Task, _ *TableOptions) string {
+		if strings.TrimSpace(t.Category) == "" {
+			return " - "
+		}
+		return t.Category
+	}},
+	"status": {"Status", func(t *Task, opts *TableOptions) string {
+		if !opts.Now.IsZero() && IsOverdue(t, opts.Now) {
+			// plain text, as columns are measured in runes
+			return t.Status.String() + " (OVERDUE)"
+		}
+		return t.Status.String()
+	}},
+	"tags": {"Tags", func(t *Task, _ *TableOptions) string {
+		if len(t.Tags) == 0 {
+			return " - "
+		}
+		return strings.Join(t.Tags, ",")
+	}},
+}
+
+var DefaultColumns = []string{"id", "title", "priority", "due", "category", "status"}
+
+var AllColumns = []string{"id", "title", "priority", "due", "category", "status", "tags"}
+
+type TableStyle int
+
+const (
+	StyleDefault TableStyle = iota
+	StyleCompact
+	StyleBorderless
+)
+
+var tableStyleNames = map[string]TableStyle{
+	"default":    StyleDefault,
+	"compact":    StyleCompact,
+	"borderless": StyleBorderless,
+}
+
+func ParseTableStyle(s string) (TableStyle, error) {
+	if strings.TrimSpace(s) == "" {
+		return StyleDefault, nil
+	}
+	if style, ok := tableStyleNames[strings.ToLower(s)]; ok {
+		return style, nil
+	}
+	return StyleDefault, fmt.Errorf("invalid table style '%s': must be 'default', 'compact', or 'borderless'", s)
+}
+
+const minTitleWidth = 10
+
+type TableOptions struct {
+	Columns     []string
+	Style       TableStyle
+	Width       int
+	Wrap        bool
+	RelativeDue bool
+	DateFormat  string
+	Now         time.Time
+	Color       ColorMode
+	Theme       Theme
+	Highlight   *regexp.Regexp
+}
+
+func ParseColumns(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	cols := make([]string, 0)
+	for _, c := range strings.Split(s, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("invalid column '%s': must be one of %s", c, strings.Join(AllColumns, ", "))
+		}
+		if !slices.Contains(cols, c) {
+			cols = append(cols, c)
+		}
+	}
+	return cols, nil
+}
+
+func RelativeDue(d DueDate, now time.Time) string {
+	days := int(startOfDay(time.Time(d)).Sub(startOfDay(now)).Hours() / 24)
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 1:
+		return fmt.Sprintf("in %dd", days)
+	}
+	return fmt.Sprintf("%dd ago", -days)
+}
+
 func RenderTable(t []Task) string {
+	return RenderTableWith(t, TableOptions{})
+}
+
+func RenderTableWith(t []Task, opts TableOptions) string {
+	cols := opts.Columns
+	if len(cols) == 0 {
+		cols = DefaultColumns
+	}
+	rows := make([][]string, 0, len(t))
+	widths := make([]int, len(cols))
+	for i, c := range cols {
+		widths[i] = utf8.RuneCountInString(columns[c].header)
+	}
+	for _, task := range t {
+		cells := make([]string, 0, len(cols))
+		for i, c := range cols {
+			cell := columns[c].value(&task, &opts)
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+			cells = append(cells, cell)
+		}
+		rows = append(rows, cells)
+	}
+
+	titleCol := slices.Index(cols, "title")
+	if opts.Width > 0 && titleCol >= 0 {
+		total := tableOverhead(opts.Style, len(cols))
+		for _, w := range widths {
+			total += w
+		}
+		if total > opts.Width {
+			widths[titleCol] = min(widths[titleCol], max(minTitleWidth, widths[titleCol]-(total-opts.Width)))
+		}
+	}
+
+	var theme Theme
+	if opts.Color == ColorAlways {
+		theme = opts.Theme
+		if theme == nil {
+			theme = DefaultTheme()
+		}
+	}
+
+	sb := strings.Builder{}
+	headers := make([]string, 0, len(cols))
+	rules := make([]string, 0, len(cols))
+	for i, c := range cols {
+		headers = append(headers, columns[c].header)
+		rules = append(rules, strings.Repeat("-", widths[i]))
+	}
+	writeRow(&sb, headers, widths, opts.Style, func(_ int, s string) string {
+		return paint(s, theme.seq("header"))
+	})
+	if opts.Style != StyleCompact {
+		writeRow(&sb, rules, widths, opts.Style, nil)
+	}
+	for r, cells := range rows {
+		var painter func(int, string) string
+		if theme != nil {
+			painter = rowPainter(&t[r], cols, theme, &opts)
+		}
+		if titleCol < 0 || utf8.RuneCountInString(cells[titleCol]) <= widths[titleCol] {
+			writeRow(&sb, cells, widths, opts.Style, painter)
+			continue
+		}
+		if !opts.Wrap {
+			cells[titleCol] = truncate(cells[titleCol], widths[titleCol])
+			writeRow(&sb, cells, widths, opts.Style, painter)
+			continue
+		}
+		for i, line := range wrapText(cells[titleCol], widths[titleCol]) {
+			lineCells := make([]string, len(cells))
+			if i == 0 {
+				copy(lineCells, cells)
+			}
+			lineCells[titleCol] = line
+			writeRow(&sb, lineCells, widths, opts.Style, painter)
+		}
+	}
+	return sb.String()
+}
+
+func rowPainter(t *Task, cols []string, theme Theme, opts *TableOptions) func(int, string) string {
+	rowSeq := ""
+	switch {
+	case t.Status == Completed:
+		rowSeq = theme.seq("completed")
+	case !opts.Now.IsZero() && IsOverdue(t, opts.Now):
+		rowSeq = theme.seq("overdue")
+	case !opts.Now.IsZero() && startOfDay(time.Time(t.DueDate)).Equal(startOfDay(opts.Now)):
+		rowSeq = theme.seq("today")
+	}
+	prioritySeq := rowSeq
+	if t.Status != Completed {
+		prioritySeq += theme.seq(strings.ToLower(t.Priority.String()))
+	}
+	matchSeq := theme.seq("match")
+	return func(col int, s string) string {
+		switch cols[col] {
+		case "priority":
+			return paint(s, prioritySeq)
+		case "title", "category", "tags":
+			// only the text that search looks in
+			return highlight(s, opts.Highlight, matchSeq, rowSeq)
+		}
+		return paint(s, rowSeq)
+	}
+}
+
+func tableOverhead(style TableStyle, n int) int {
+	switch style {
+	case StyleCompact:
+		return n - 1
+	case StyleBorderless:
+		return 2 * (n - 1)
+	}
+	return 3 * n
+}
+
+func writeRow(sb *strings.Builder, cells []string, widths []int, style TableStyle, painter func(int, string) string) {
+	line := strings.Builder{}
+	for i, cell := range cells {
+		padded := cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		if style != StyleDefault && i == len(cells)-1 {
+			padded = strings.TrimRight(cell, " ")
+		}
+		if painter != nil && strings.TrimSpace(padded) != "" {
+			padded = painter(i, padded)
+		}
+		switch style {
+		case StyleCompact:
+			if i > 0 {
+				line.WriteString(" ")
+			}
+			line.WriteString(padded)
+		case StyleBorderless:
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(padded)
+		default:
+			line.WriteString(padded + "  |")
+		}
+	}
+	sb.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return strings.TrimRight(string(r[:width-1]), " ") + "…"
+}
+
+func wrapText(s string, width int) []string {
+	lines := make([]string, 0)
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			r := []rune(word)
+			lines = append(lines, string(r[:width]))
+			word = string(r[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func RenderJSON(t []Task) (string, error) {
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func RenderViews(v []View) string {
 	sb := strings.Builder{}
 	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.Debug)
-	_, _ = fmt.Fprintln(w, "ID\tTitle\tPriority\tDue Date\tCategory\tStatus\t")
-	_, _ = fmt.Fprintln(w, "----\t-----\t--------\t----------\t--------\t-------\t")
-	for _, task := range t {
-		var priority string
-		switch task.Priority {
-		case Low:
-			priority = "LOW"
-		case Medium:
-			priority = "MEDIUM"
-		case High:
-			priority = "HIGH"
-		}
-		dueDate := time.Time(task.DueDate).Format("2006-01-02")
-		var category string
-		if strings.TrimSpace(task.Category) == "" {
-			category = " - "
-		} else {
-			category = task.Category
-		}
-		var status string
-		switch task.Status {
-		case Pending:
-			status = "pending"
-		case Completed:
-			status = "completed"
-		}
-		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t\n", task.Id, task.Title, priority, dueDate, category, status)
+	_, _ = fmt.Fprintln(w, "Name\tQuery\tSort\tFormat\tColumns\t")
+	_, _ = fmt.Fprintln(w, "----\t-----\t----\t------\t-------\t")
+	for _, view := range v {
+		sort := view.Sort
+		if sort == "" {
+			sort = " - "
+		}
+		format := view.Format
+		if format == "" {
+			format = " - "
+		}
+		cols := strings.Join(view.Columns, ",")
+		if cols == "" {
+			cols = " - "
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", view.Name, view.Query, sort, format, cols)
 	}
 	_ = w.Flush()
 	return sb.String()
@@ -0,0 +1,153 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+var renderTestTasks = []Task{
+	{
+		Id:       1,
+		Title:    "Call dentist",
+		Priority: High,
+		DueDate:  DueDate(time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)),
+		Category: "health",
+		Status:   Pending,
+		Tags:     []string{"phone"},
+	},
+	{
+		Id:       2,
+		Title:    "Write the quarterly planning document for the whole team",
+		Priority: Low,
+		DueDate:  DueDate(time.Date(2024, 4, 7, 0, 0, 0, 0, time.UTC)),
+		Status:   Completed,
+	},
+}
+
+func TestRenderTableDefault(t *testing.T) {
+	got := RenderTable(renderTestTasks)
+	lines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d: %v", len(lines), got)
+	}
+	for _, want := range []string{"ID  |Title", "Priority", "Due Date", "Category", "Status", "2024-04-12", "HIGH", " - "} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected table to contain '%v', got:\n%v", want, got)
+		}
+	}
+	width := utf8.RuneCountInString(lines[0])
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) != width {
+			t.Fatalf("expected aligned rows, got:\n%v", got)
+		}
+	}
+}
+
+func TestRenderTableShortTitleNarrowWidth(t *testing.T) {
+	short := []Task{{Id: 1, Title: "Tea", Category: "kitchen"}}
+	opts := TableOptions{Columns: []string{"id", "title", "category"}}
+	want := RenderTableWith(short, opts)
+	opts.Width = 10
+	if got := RenderTableWith(short, opts); got != want {
+		t.Fatalf("expected a short title column to keep its width, got:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestRenderTableOptionsTableDriven(t *testing.T) {
+	now := time.Date(2024, 4, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		opts    TableOptions
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "columns",
+			opts:    TableOptions{Columns: []string{"id", "title", "tags"}},
+			want:    []string{"Tags", "phone"},
+			notWant: []string{"Priority", "Status"},
+		},
+		{
+			name:    "truncate title to width",
+			opts:    TableOptions{Columns: []string{"id", "title"}, Width: 30},
+			want:    []string{"Write the quarterly p…"},
+			notWant: []string{"whole team"},
+		},
+		{
+			name: "wrap title to width",
+			opts: TableOptions{Columns: []string{"id", "title"}, Width: 30, Wrap: true},
+			want: []string{"Write the quarterly", "planning document for", "the whole team"},
+		},
+		{
+			name:    "relative due",
+			opts:    TableOptions{Columns: []string{"id", "due"}, RelativeDue: true, Now: now},
+			want:    []string{"in 2d", "3d ago"},
+			notWant: []string{"2024-04-12"},
+		},
+		{
+			name:    "date format",
+			opts:    TableOptions{Columns: []string{"id", "due"}, DateFormat: "Jan 2"},
+			want:    []string{"Apr 12"},
+			notWant: []string{"2024-04-12"},
+		},
+		{
+			name:    "compact style",
+			opts:    TableOptions{Columns: []string{"id", "title"}, Style: StyleCompact},
+			want:    []string{"ID Title\n1  Call dentist\n"},
+			notWant: []string{"|", "--"},
+		},
+		{
+			name:    "borderless style",
+			opts:    TableOptions{Columns: []string{"id", "title"}, Style: StyleBorderless},
+			want:    []string{"ID  Title\n--  ---", "1   Call dentist\n"},
+			notWant: []string{"|"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderTableWith(renderTestTasks, tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Fatalf("expected table to contain '%v', got:\n%v", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Fatalf("expected table not to contain '%v', got:\n%v", notWant, got)
+				}
+			}
+			if tt.opts.Width > 0 {
+				for _, line := range strings.Split(got, "\n") {
+					if utf8.RuneCountInString(line) > tt.opts.Width {
+						t.Fatalf("expected lines within %d columns, got:\n%v", tt.opts.Width, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRelativeDueTableDriven(t *testing.T) {
+	now := time.Date(2024, 4, 10, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		due  time.Time
+		want string
+	}{
+		{time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), "today"},
+		{time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC), "tomorrow"},
+		{time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC), "yesterday"},
+		{time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC), "in 10d"},
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), "10d ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := RelativeDue(DueDate(tt.due), now); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,534 @@
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSON-RPC 2.0 error codes. Those from -32000 down are this server's own and
+// match the codes of the REST API's error bodies.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcError          = -32000
+	rpcNotFound       = -32001
+	rpcConflict       = -32002
+	rpcStorage        = -32003
+	rpcUnavailable    = -32004
+	rpcRejected       = -32005
+)
+
+var rpcErrorCodes = map[string]int{
+	"bad_request": rpcInvalidParams,
+	"not_found":   rpcNotFound,
+	"conflict":    rpcConflict,
+	"storage":     rpcStorage,
+	"unavailable": rpcUnavailable,
+	"rejected":    rpcRejected,
+
+	"precondition_failed": rpcConflict,
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcErrorObject `json:"error,omitempty"`
+}
+
+type rpcErrorObject struct {
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    rpcErrorData `json:"data"`
+}
+
+// rpcErrorData names the error like the REST API does, e.g. "not_found".
+type rpcErrorData struct {
+	Code string `json:"code"`
+}
+
+type rpcNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// TaskChange is sent as a taskChanged notification after a task is added,
+// updated, completed or deleted. Task is omitted for deletions.
+type TaskChange struct {
+	Change string `json:"change"`
+	ID     int    `json:"id"`
+	Task   *Task  `json:"task,omitempty"`
+}
+
+// ViewChange is sent as a viewChanged notification after a view is saved or
+// deleted. View is omitted for deletions.
+type ViewChange struct {
+	Change string `json:"change"`
+	Name   string `json:"name"`
+	View   *View  `json:"view,omitempty"`
+}
+
+type rpcSession struct {
+	m       Manager
+	w       *bufio.Writer
+	pending []rpcNotification
+}
+
+// rpcMethods holds the methods served by ServeRPC, one per Manager method.
+var rpcMethods = map[string]func(s *rpcSession, params json.RawMessage) (any, error){
+	"addTask":      (*rpcSession).addTask,
+	"listTasks":    (*rpcSession).listTasks,
+	"searchTasks":  (*rpcSession).searchTasks,
+	"getTask":      (*rpcSession).getTask,
+	"updateTask":   (*rpcSession).updateTask,
+	"completeTask": (*rpcSession).completeTask,
+	"deleteTask":   (*rpcSession).deleteTask,
+	"queryTasks":   (*rpcSession).queryTasks,
+	"saveView":     (*rpcSession).saveView,
+	"getView":      (*rpcSession).getView,
+	"listViews":    (*rpcSession).listViews,
+	"deleteView":   (*rpcSession).deleteView,
+	"now":          (*rpcSession).now,
+}
+
+// ServeRPC answers JSON-RPC 2.0 requests read from r, framed with
+// Content-Length headers as in the Language Server Protocol, until r ends.
+// Changes made through it are also sent as taskChanged and viewChanged
+// notifications.
+func ServeRPC(m Manager, r io.Reader, w io.Writer) error {
+	s := &rpcSession{m: m, w: bufio.NewWriter(w)}
+	br := bufio.NewReader(r)
+	for {
+		body, err := readRPCFrame(br)
+		if err == io.EOF {
+			return nil
+		}
+		if errors.Is(err, errFrameTooLarge) {
+			if err := s.write(rpcFailure(nil, rpcInvalidRequest, err.Error())); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.handleFrame(body); err != nil {
+			return err
+		}
+	}
+}
+
+var errFrameTooLarge = fmt.Errorf("message is larger than %d bytes", maxRequestBytes)
+
+// readRPCFrame reads the headers and body of one message. It returns io.EOF
+// only when r ends between messages.
+func readRPCFrame(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for first := true; ; first = false {
+		line, err := r.ReadString('\n')
+		if err == io.EOF && first && line == "" {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading message header: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid message header '%s'", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil || length < 0 {
+				return nil, fmt.Errorf("invalid Content-Length '%s'", strings.TrimSpace(value))
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("message has no Content-Length header")
+	}
+	if length > maxRequestBytes {
+		if _, err := io.CopyN(io.Discard, r, int64(length)); err != nil {
+			return nil, fmt.Errorf("reading message body: %w", err)
+		}
+		return nil, errFrameTooLarge
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("reading message body: %w", err)
+	}
+	return body, nil
+}
+
+// handleFrame answers one message, which may hold a batch of requests, and
+// then sends the notifications for the changes it made.
+func (s *rpcSession) handleFrame(body []byte) error {
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		return s.write(rpcFailure(nil, rpcParseError, "invalid JSON"))
+	}
+	if body[0] == '[' {
+		var batch []json.RawMessage
+		json.Unmarshal(body, &batch)
+		if len(batch) == 0 {
+			return s.write(rpcFailure(nil, rpcInvalidRequest, "empty batch"))
+		}
+		responses := []*rpcResponse{}
+		for _, raw := range batch {
+			if resp := s.handle(raw); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) > 0 {
+			if err := s.write(responses); err != nil {
+				return err
+			}
+		}
+	} else if resp := s.handle(body); resp != nil {
+		if err := s.write(resp); err != nil {
+			return err
+		}
+	}
+	for _, n := range s.pending {
+		if err := s.write(n); err != nil {
+			return err
+		}
+	}
+	s.pending = s.pending[:0]
+	return nil
+}
+
+// handle runs one request. It returns nil for notifications, which get no
+// response.
+func (s *rpcSession) handle(raw json.RawMessage) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return rpcFailure(nil, rpcInvalidRequest, "request must be an object")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcFailure(req.ID, rpcInvalidRequest, `request must have "jsonrpc": "2.0" and a method`)
+	}
+	method, ok := rpcMethods[req.Method]
+	if !ok {
+		if req.ID == nil {
+			return nil
+		}
+		return rpcFailure(req.ID, rpcMethodNotFound, fmt.Sprintf("unknown method '%s'", req.Method))
+	}
+	result, err := method(s, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+	if err != nil {
+		_, code := errorCode(err)
+		number, ok := rpcErrorCodes[code]
+		if !ok {
+			number = rpcError
+		}
+		resp := rpcFailure(req.ID, number, err.Error())
+		resp.Error.Data.Code = code
+		return resp
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return rpcFailure(req.ID, rpcError, err.Error())
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: b}
+}
+
+func rpcFailure(id json.RawMessage, code int, message string) *rpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	data := rpcErrorData{Code: "bad_request"}
+	if code == rpcError {
+		data.Code = "error"
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcErrorObject{Code: code, Message: message, Data: data}}
+}
+
+func (s *rpcSession) write(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n", len(b))
+	s.w.Write(b)
+	return s.w.Flush()
+}
+
+func (s *rpcSession) notify(method string, params any) {
+	s.pending = append(s.pending, rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// decodeParams reads named params, rejecting unknown fields like the REST
+// API does.
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		params = json.RawMessage("{}")
+	}
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest("invalid params: %v", err)
+	}
+	return nil
+}
+
+type rpcFilterParams struct {
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+	Category string `json:"category"`
+	Overdue  bool   `json:"overdue"`
+}
+
+func (p rpcFilterParams) options() (opts SearchOptions, err error) {
+	if opts.Status, err = parseStatusParam(p.Status); err != nil {
+		return opts, err
+	}
+	if opts.Priority, err = parsePriorityParam(p.Priority); err != nil {
+		return opts, err
+	}
+	opts.Category = p.Category
+	opts.OverdueOnly = p.Overdue
+	return opts, nil
+}
+
+type rpcIDParams struct {
+	ID *int `json:"id"`
+}
+
+func (s *rpcSession) taskID(params json.RawMessage) (int, error) {
+	var p rpcIDParams
+	if err := decodeParams(params, &p); err != nil {
+		return 0, err
+	}
+	if p.ID == nil {
+		return 0, badRequest("missing task id")
+	}
+	return *p.ID, nil
+}
+
+func (s *rpcSession) addTask(params json.RawMessage) (any, error) {
+	var n NewTask
+	if err := decodeParams(params, &n); err != nil {
+		return nil, err
+	}
+	if err := n.normalize(); err != nil {
+		return nil, err
+	}
+	t, err := n.Add(s.m)
+	if err != nil {
+		return nil, err
+	}
+	s.notify("taskChanged", TaskChange{Change: "added", ID: t.Id, Task: t})
+	return t, nil
+}
+
+func (s *rpcSession) listTasks(params json.RawMessage) (any, error) {
+	var p rpcFilterParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	opts, err := p.options()
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(s.m.ListTasks(opts.Status, opts.Priority, opts.Category, opts.OverdueOnly))
+}
+
+func (s *rpcSession) searchTasks(params json.RawMessage) (any, error) {
+	var p struct {
+		rpcFilterParams
+		Query string `json:"query"`
+		Mode  string `json:"mode"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	opts, err := p.options()
+	if err != nil {
+		return nil, err
+	}
+	query := strings.TrimSpace(p.Query)
+	if query == "" {
+		return nil, badRequest("missing search query")
+	}
+	if opts.Mode, err = parseSearchMode(p.Mode, query); err != nil {
+		return nil, err
+	}
+	if p.Limit < 0 {
+		return nil, badRequest("invalid limit %d", p.Limit)
+	}
+	opts.Limit = p.Limit
+	return nonNil(s.m.SearchTasks(query, opts))
+}
+
+func (s *rpcSession) getTask(params json.RawMessage) (any, error) {
+	id, err := s.taskID(params)
+	if err != nil {
+		return nil, err
+	}
+	return s.m.GetTask(id)
+}
+
+func (s *rpcSession) updateTask(params json.RawMessage) (any, error) {
+	var p struct {
+		ID    *int      `json:"id"`
+		Patch TaskPatch `json:"patch"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.ID == nil {
+		return nil, badRequest("missing task id")
+	}
+	if p.Patch.Title != nil && strings.TrimSpace(*p.Patch.Title) == "" {
+		return nil, badRequest("title cannot be empty")
+	}
+	t, err := s.m.UpdateTask(*p.ID, p.Patch)
+	if err != nil {
+		return nil, err
+	}
+	s.notify("taskChanged", TaskChange{Change: "updated", ID: t.Id, Task: t})
+	return t, nil
+}
+
+func (s *rpcSession) completeTask(params json.RawMessage) (any, error) {
+	id, err := s.taskID(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.m.CompleteTask(id); err != nil {
+		return nil, err
+	}
+	t, err := s.m.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	s.notify("taskChanged", TaskChange{Change: "completed", ID: id, Task: t})
+	return t, nil
+}
+
+func (s *rpcSession) deleteTask(params json.RawMessage) (any, error) {
+	id, err := s.taskID(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.m.DeleteTask(id); err != nil {
+		return nil, err
+	}
+	s.notify("taskChanged", TaskChange{Change: "deleted", ID: id})
+	return nil, nil
+}
+
+func (s *rpcSession) queryTasks(params json.RawMessage) (any, error) {
+	var p struct {
+		Query string `json:"query"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	filter, err := ParseFilter(p.Query)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	return nonNil(s.m.QueryTasks(filter))
+}
+
+func (s *rpcSession) saveView(params json.RawMessage) (any, error) {
+	var v View
+	if err := decodeParams(params, &v); err != nil {
+		return nil, err
+	}
+	if err := checkView(v); err != nil {
+		return nil, err
+	}
+	if err := s.m.SaveView(v); err != nil {
+		return nil, err
+	}
+	s.notify("viewChanged", ViewChange{Change: "saved", Name: v.Name, View: &v})
+	return v, nil
+}
+
+func (s *rpcSession) viewName(params json.RawMessage) (string, error) {
+	var p struct {
+		Name string `json:"name"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return "", err
+	}
+	if p.Name == "" {
+		return "", badRequest("missing view name")
+	}
+	return p.Name, nil
+}
+
+func (s *rpcSession) getView(params json.RawMessage) (any, error) {
+	name, err := s.viewName(params)
+	if err != nil {
+		return nil, err
+	}
+	return s.m.GetView(name)
+}
+
+func (s *rpcSession) listViews(params json.RawMessage) (any, error) {
+	if err := decodeParams(params, &struct{}{}); err != nil {
+		return nil, err
+	}
+	v, err := s.m.ListViews()
+	if v == nil {
+		v = []View{}
+	}
+	return v, err
+}
+
+func (s *rpcSession) deleteView(params json.RawMessage) (any, error) {
+	name, err := s.viewName(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.m.DeleteView(name); err != nil {
+		return nil, err
+	}
+	s.notify("viewChanged", ViewChange{Change: "deleted", Name: name})
+	return nil, nil
+}
+
+func (s *rpcSession) now(params json.RawMessage) (any, error) {
+	if err := decodeParams(params, &struct{}{}); err != nil {
+		return nil, err
+	}
+	return s.m.Now().Format(time.RFC3339), nil
+}
+
+// nonNil makes empty results encode as [] rather than null.
+func nonNil(t []Task, err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		t = []Task{}
+	}
+	return t, nil
+}
@@ -0,0 +1,200 @@
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func rpcFrames(messages ...string) string {
+	sb := strings.Builder{}
+	for _, msg := range messages {
+		fmt.Fprintf(&sb, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+	return sb.String()
+}
+
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcErrorObject `json:"error"`
+}
+
+// runRPC serves the messages against the test tasks and returns every
+// message written back, in order.
+func runRPC(t *testing.T, messages ...string) []rpcMessage {
+	t.Helper()
+	now := time.Date(2024, 4, 10, 9, 0, 0, 0, time.UTC)
+	m, _ := newManagerInternal("", func() time.Time { return now }, []Task{
+		{Id: 1, Title: "Call dentist", Priority: High, DueDate: DueDate(now), Category: "health", Status: Pending},
+		{Id: 2, Title: "Buy milk", Priority: Low, DueDate: DueDate(now), Status: Completed},
+	})
+	out := &bytes.Buffer{}
+	if err := ServeRPC(m, strings.NewReader(rpcFrames(messages...)), out); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	var got []rpcMessage
+	r := bufio.NewReader(out)
+	for {
+		body, err := readRPCFrame(r)
+		if err == io.EOF {
+			return got
+		}
+		if err != nil {
+			t.Fatalf("invalid output frame: %v", err)
+		}
+		if body[0] == '[' {
+			var batch []rpcMessage
+			json.Unmarshal(body, &batch)
+			got = append(got, batch...)
+			continue
+		}
+		var msg rpcMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatalf("invalid output %s: %v", body, err)
+		}
+		got = append(got, msg)
+	}
+}
+
+func TestServeRPCMethodsTableDriven(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    string
+		wantResult string
+		wantCode   int
+	}{
+		{name: "list", request: `{"jsonrpc": "2.0", "id": 1, "method": "listTasks", "params": {"status": "pending"}}`, wantResult: `"Call dentist"`},
+		{name: "list without params", request: `{"jsonrpc": "2.0", "id": 1, "method": "listTasks"}`, wantResult: `"Buy milk"`},
+		{name: "list empty", request: `{"jsonrpc": "2.0", "id": 1, "method": "listTasks", "params": {"category": "work"}}`, wantResult: `[]`},
+		{name: "list bad priority", request: `{"jsonrpc": "2.0", "id": 1, "method": "listTasks", "params": {"priority": "urgent"}}`, wantCode: rpcInvalidParams},
+		{name: "search", request: `{"jsonrpc": "2.0", "id": 1, "method": "searchTasks", "params": {"query": "milk", "mode": "exact"}}`, wantResult: `"Buy milk"`},
+		{name: "search without query", request: `{"jsonrpc": "2.0", "id": 1, "method": "searchTasks", "params": {}}`, wantCode: rpcInvalidParams},
+		{name: "query", request: `{"jsonrpc": "2.0", "id": 1, "method": "queryTasks", "params": {"query": "priority:high"}}`, wantResult: `"Call dentist"`},
+		{name: "get", request: `{"jsonrpc": "2.0", "id": "a", "method": "getTask", "params": {"id": 2}}`, wantResult: `"Buy milk"`},
+		{name: "get missing", request: `{"jsonrpc": "2.0", "id": 1, "method": "getTask", "params": {"id": 9}}`, wantCode: rpcNotFound},
+		{name: "get without id", request: `{"jsonrpc": "2.0", "id": 1, "method": "getTask", "params": {}}`, wantCode: rpcInvalidParams},
+		{name: "unknown param", request: `{"jsonrpc": "2.0", "id": 1, "method": "getTask", "params": {"id": 1, "force": true}}`, wantCode: rpcInvalidParams},
+		{name: "positional params", request: `{"jsonrpc": "2.0", "id": 1, "method": "getTask", "params": [1]}`, wantCode: rpcInvalidParams},
+		{name: "complete twice", request: `{"jsonrpc": "2.0", "id": 1, "method": "completeTask", "params": {"id": 2}}`, wantCode: rpcConflict},
+		{name: "now", request: `{"jsonrpc": "2.0", "id": 1, "method": "now"}`, wantResult: `"2024-04-10T09:00:00Z"`},
+		{name: "list views", request: `{"jsonrpc": "2.0", "id": 1, "method": "listViews"}`, wantResult: `[]`},
+		{name: "unknown method", request: `{"jsonrpc": "2.0", "id": 1, "method": "frobnicate"}`, wantCode: rpcMethodNotFound},
+		{name: "wrong version", request: `{"jsonrpc": "1.0", "id": 1, "method": "now"}`, wantCode: rpcInvalidRequest},
+		{name: "not json", request: `{"jsonrpc": `, wantCode: rpcParseError},
+		{name: "empty batch", request: `[]`, wantCode: rpcInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runRPC(t, tt.request)
+			if len(got) != 1 {
+				t.Fatalf("expected one response, got %+v", got)
+			}
+			if tt.wantCode != 0 {
+				if got[0].Error == nil || got[0].Error.Code != tt.wantCode || got[0].Error.Message == "" {
+					t.Fatalf("expected error %d, got %+v", tt.wantCode, got[0])
+				}
+				return
+			}
+			if got[0].Error != nil || !strings.Contains(string(got[0].Result), tt.wantResult) {
+				t.Fatalf("expected a result containing %s, got %s (%+v)", tt.wantResult, got[0].Result, got[0].Error)
+			}
+		})
+	}
+}
+
+func TestServeRPCNotifiesChanges(t *testing.T) {
+	got := runRPC(t,
+		`{"jsonrpc": "2.0", "id": 1, "method": "addTask", "params": {"title": "Water plants", "category": "Home", "tags": ["garden"]}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "updateTask", "params": {"id": 3, "patch": {"priority": "HIGH"}}}`,
+		`{"jsonrpc": "2.0", "method": "completeTask", "params": {"id": 1}}`,
+		`[{"jsonrpc": "2.0", "id": 3, "method": "deleteTask", "params": {"id": 3}}, {"jsonrpc": "2.0", "id": 4, "method": "saveView", "params": {"name": "home", "query": "category:home"}}]`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "getTask", "params": {"id": 1}}`,
+	)
+
+	var summary []string
+	for _, msg := range got {
+		switch {
+		case msg.Method != "":
+			summary = append(summary, msg.Method+" "+string(msg.Params))
+		case msg.Error != nil:
+			summary = append(summary, fmt.Sprintf("error %s", msg.Error.Message))
+		default:
+			summary = append(summary, "response "+string(msg.ID))
+		}
+	}
+	want := []string{
+		"response 1",
+		`taskChanged {"change":"added","id":3,"task":`,
+		"response 2",
+		`taskChanged {"change":"updated","id":3,"task":`,
+		// the notification request gets no response, but its change is announced
+		`taskChanged {"change":"completed","id":1,"task":`,
+		"response 3",
+		"response 4",
+		`taskChanged {"change":"deleted","id":3}`,
+		`viewChanged {"change":"saved","name":"home","view":`,
+		"response 5",
+	}
+	if len(summary) != len(want) {
+		t.Fatalf("expected %d messages, got:\n%s", len(want), strings.Join(summary, "\n"))
+	}
+	for i := range want {
+		if !strings.HasPrefix(summary[i], want[i]) {
+			t.Fatalf("message %d: expected %q, got:\n%s", i, want[i], strings.Join(summary, "\n"))
+		}
+	}
+
+	var added Task
+	json.Unmarshal(got[0].Result, &added)
+	if added.Id != 3 || added.Category != "home" || added.Tags[0] != "garden" {
+		t.Fatalf("unexpected added task %+v", added)
+	}
+	var completed Task
+	json.Unmarshal(got[len(got)-1].Result, &completed)
+	if completed.Status != Completed {
+		t.Fatalf("expected task 1 to be completed, got %+v", completed)
+	}
+}
+
+func TestServeRPCFraming(t *testing.T) {
+	m, _ := newManagerInternal("", nil, []Task{})
+	tests := []struct {
+		name    string
+		input   string
+		wantOut string
+		wantErr string
+	}{
+		{name: "empty input", input: ""},
+		{name: "extra headers", input: "Content-Type: application/vscode-jsonrpc; charset=utf-8\r\ncontent-length: 40\r\n\r\n" + `{"jsonrpc":"2.0","id":1,"method":"now"} `, wantOut: `"id":1`},
+		{name: "bare newlines", input: "Content-Length: 2\n\n{}", wantOut: `"code":-32600`},
+		{name: "too large", input: fmt.Sprintf("Content-Length: %d\r\n\r\n%s", maxRequestBytes+1, strings.Repeat(" ", maxRequestBytes+1)), wantOut: "message is larger than"},
+		{name: "no length", input: "Content-Type: x\r\n\r\n{}", wantErr: "message has no Content-Length header"},
+		{name: "bad length", input: "Content-Length: ten\r\n\r\n", wantErr: "invalid Content-Length 'ten'"},
+		{name: "bad header", input: "{}\r\n\r\n", wantErr: "invalid message header '{}'"},
+		{name: "truncated body", input: "Content-Length: 10\r\n\r\n{}", wantErr: "reading message body: unexpected EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := ServeRPC(m, strings.NewReader(tt.input), out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || !strings.Contains(out.String(), tt.wantOut) {
+				t.Fatalf("expected output containing %q, got %q (%v)", tt.wantOut, out.String(), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package tasks
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type SearchMode int
+
+const (
+	SearchFuzzy SearchMode = iota
+	SearchExact
+	SearchRegex
+)
+
+type SearchOptions struct {
+	Mode        SearchMode
+	Status      *Status
+	Priority    *Priority
+	Category    string
+	OverdueOnly bool
+	Limit       int
+}
+
+var searchFields = map[string]float64{
+	"title":       3,
+	"tags":        2,
+	"category":    2,
+	"description": 1,
+	"notes":       1,
+}
+
+var searchFieldAliases = map[string]string{
+	"title":       "title",
+	"tag":         "tags",
+	"tags":        "tags",
+	"category":    "category",
+	"description": "description",
+	"desc":        "description",
+	"note":        "notes",
+	"notes":       "notes",
+}
+
+const (
+	exactMatch     = 1.0
+	prefixMatch    = 0.8
+	substringMatch = 0.6
+	fuzzyMatch     = 0.4
+)
+
+type posting struct {
+	task  int
+	field string
+	count int
+}
+
+type searchIndex struct {
+	postings map[string][]posting
+	terms    []string
+	// byLength holds the sorted terms of each length in runes
+	byLength [][]string
+	size     int
+}
+
+type searchClause struct {
+	field string
+	term  string
+}
+
+func (m *manager) SearchTasks(query string, opts SearchOptions) ([]Task, error) {
+	var scores map[int]float64
+	if opts.Mode == SearchRegex {
+		var err error
+		if scores, err = m.searchRegex(query); err != nil {
+			return nil, err
+		}
+	} else {
+		clauses, err := parseSearchQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		if m.index == nil {
+			m.index = buildSearchIndex(m.tasks)
+		}
+		scores = m.index.score(clauses, opts.Mode)
+	}
+	matches := m.listFilter(opts.Status, opts.Priority, opts.Category, opts.OverdueOnly)
+	for i := range scores {
+		if !matches(&m.tasks[i]) {
+			delete(scores, i)
+		}
+	}
+	tasks := rankTasks(m.tasks, scores)
+	if opts.Limit > 0 && len(tasks) > opts.Limit {
+		tasks = tasks[:opts.Limit]
+	}
+	return tasks, nil
+}
+
+// ParseSearchRegex splits an optional field prefix such as 'title:' off a
+// regex query and compiles the rest, as regex search does.
+func ParseSearchRegex(query string) (string, *regexp.Regexp, error) {
+	field := ""
+	if f, rest, ok := strings.Cut(query, ":"); ok {
+		if alias, ok := searchFieldAliases[strings.ToLower(f)]; ok {
+			field, query = alias, rest
+		}
+	}
+	re, err := regexp.Compile("(?i)" + query)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid search regex: %v", err)
+	}
+	return field, re, nil
+}
+
+func (m *manager) searchRegex(query string) (map[int]float64, error) {
+	field, re, err := ParseSearchRegex(query)
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[int]float64)
+	for i, task := range m.tasks {
+		for f, values := range searchFieldValues(&task) {
+			if field != "" && f != field {
+				continue
+			}
+			for _, v := range values {
+				if re.MatchString(v) {
+					scores[i] += searchFields[f]
+				}
+			}
+		}
+	}
+	return scores, nil
+}
+
+func HighlightPattern(query string, mode SearchMode) *regexp.Regexp {
+	if mode == SearchRegex {
+		_, re, err := ParseSearchRegex(query)
+		if err != nil {
+			return nil
+		}
+		return re
+	}
+	clauses, err := parseSearchQuery(query)
+	if err != nil {
+		return nil
+	}
+	terms := make([]string, 0, len(clauses))
+	for _, c := range clauses {
+		terms = append(terms, regexp.QuoteMeta(c.term))
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+}
+
+func parseSearchQuery(query string) ([]searchClause, error) {
+	words, err := splitQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	clauses := make([]searchClause, 0)
+	for _, word := range words {
+		field := ""
+		if f, rest, ok := strings.Cut(word, ":"); ok {
+			alias, ok := searchFieldAliases[strings.ToLower(f)]
+			if !ok {
+				return nil, fmt.Errorf("invalid search field '%s'", f)
+			}
+			field, word = alias, rest
+		}
+		for _, term := range tokenize(word) {
+			clauses = append(clauses, searchClause{field: field, term: term})
+		}
+	}
+	if len(clauses) == 0 {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+	return clauses, nil
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func searchFieldValues(t *Task) map[string][]string {
+	return map[string][]string{
+		"title":       {t.Title},
+		"tags":        t.Tags,
+		"category":    {t.Category},
+		"description": {t.Description},
+		"notes":       t.Notes,
+	}
+}
+
+func buildSearchIndex(t []Task) *searchIndex {
+	idx := &searchIndex{
+		postings: make(map[string][]posting),
+		size:     len(t),
+	}
+	for i, task := range t {
+		for field, values := range searchFieldValues(&task) {
+			counts := make(map[string]int)
+			for _, v := range values {
+				for _, term := range tokenize(v) {
+					counts[term]++
+				}
+			}
+			for term, count := range counts {
+				idx.postings[term] = append(idx.postings[term], posting{task: i, field: field, count: count})
+			}
+		}
+	}
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	slices.Sort(idx.terms)
+	for _, term := range idx.terms {
+		n := utf8.RuneCountInString(term)
+		for len(idx.byLength) <= n {
+			idx.byLength = append(idx.byLength, nil)
+		}
+		idx.byLength[n] = append(idx.byLength[n], term)
+	}
+	return idx
+}
+
+func (idx *searchIndex) score(clauses []searchClause, mode SearchMode) map[int]float64 {
+	var scores map[int]float64
+	for _, c := range clauses {
+		clauseScores := make(map[int]float64)
+		for term, quality := range idx.expand(c.term, mode) {
+			idf := math.Log(1 + float64(idx.size)/float64(len(idx.postings[term])))
+			for _, p := range idx.postings[term] {
+				if c.field != "" && p.field != c.field {
+					continue
+				}
+				s := searchFields[p.field] * quality * idf * (1 + math.Log(float64(p.count)))
+				clauseScores[p.task] += s
+			}
+		}
+		if scores == nil {
+			scores = clauseScores
+			continue
+		}
+		for task := range scores {
+			if s, ok := clauseScores[task]; ok {
+				scores[task] += s
+			} else {
+				delete(scores, task)
+			}
+		}
+	}
+	return scores
+}
+
+func (idx *searchIndex) expand(term string, mode SearchMode) map[string]float64 {
+	matches := make(map[string]float64)
+	if _, ok := idx.postings[term]; ok {
+		matches[term] = exactMatch
+	}
+	if mode == SearchExact {
+		return matches
+	}
+	start, _ := slices.BinarySearch(idx.terms, term)
+	for _, t := range idx.terms[start:] {
+		if !strings.HasPrefix(t, term) {
+			break
+		}
+		if _, ok := matches[t]; !ok {
+			matches[t] = prefixMatch
+		}
+	}
+	n := utf8.RuneCountInString(term)
+	maxEdits := 0
+	switch {
+	case n >= 8:
+		maxEdits = 2
+	case n >= 4:
+		maxEdits = 1
+	}
+	// only longer terms can contain term, and only terms within maxEdits of
+	// its length can be a fuzzy match, so shorter terms are never scanned
+	for length := max(n-maxEdits, 1); length < len(idx.byLength); length++ {
+		fuzzy := maxEdits > 0 && length <= n+maxEdits
+		for _, t := range idx.byLength[length] {
+			if _, ok := matches[t]; ok {
+				continue
+			}
+			if length > n && strings.Contains(t, term) {
+				matches[t] = substringMatch
+			} else if fuzzy && editDistance(term, t, maxEdits) <= maxEdits {
+				matches[t] = fuzzyMatch
+			}
+		}
+	}
+	return matches
+}
+
+func editDistance(a string, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+	return prev[len(rb)]
+}
+
+func rankTasks(t []Task, scores map[int]float64) []Task {
+	positions := make([]int, 0, len(scores))
+	for i := range scores {
+		positions = append(positions, i)
+	}
+	slices.SortFunc(positions, func(a, b int) int {
+		if scores[a] != scores[b] {
+			if scores[a] > scores[b] {
+				return -1
+			}
+			return 1
+		}
+		return t[a].Id - t[b].Id
+	})
+	tasks := make([]Task, 0, len(positions))
+	for _, i := range positions {
+		tasks = append(tasks, t[i])
+	}
+	return tasks
+}
@@ -0,0 +1,178 @@
+package tasks
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func searchTestManager() Manager {
+	m, _ := newManagerInternal(
+		"",
+		nil,
+		[]Task{
+			{
+				Id:       1,
+				Title:    "Deploy release to staging",
+				Category: "work",
+				Tags:     []string{"ops"},
+			},
+			{
+				Id:          2,
+				Title:       "Write release notes",
+				Category:    "work",
+				Description: "Summarise the deploy changes",
+				Priority:    High,
+				Tags:        []string{"docs"},
+			},
+			{
+				Id:       3,
+				Title:    "Buy milk",
+				Category: "groceries",
+				Notes:    []string{"oat milk, not dairy"},
+			},
+			{
+				Id:       4,
+				Title:    "Call dentist",
+				Category: "health",
+				Status:   Completed,
+				Tags:     []string{"deploy"},
+			},
+		},
+	)
+	return m
+}
+
+func TestSearchTasksModesTableDriven(t *testing.T) {
+	m := searchTestManager()
+	tests := []struct {
+		name     string
+		query    string
+		opts     SearchOptions
+		expected []int
+	}{
+		{"title ranks above other fields", "deploy", SearchOptions{}, []int{1, 4, 2}},
+		{"description", "summarise", SearchOptions{}, []int{2}},
+		{"notes", "dairy", SearchOptions{}, []int{3}},
+		{"tags", "docs", SearchOptions{}, []int{2}},
+		{"category", "groceries", SearchOptions{}, []int{3}},
+		{"all terms must match", "release notes", SearchOptions{}, []int{2}},
+		{"prefix", "rel", SearchOptions{}, []int{1, 2}},
+		{"fuzzy typo", "dpeloy", SearchOptions{}, []int{1, 4, 2}},
+		{"fuzzy typo long word", "stagnig", SearchOptions{}, []int{1}},
+		{"field scoped title", "title:deploy", SearchOptions{}, []int{1}},
+		{"field scoped tag", "tag:deploy", SearchOptions{}, []int{4}},
+		{"field scoped description", "description:deploy", SearchOptions{}, []int{2}},
+		{"exact disables fuzzy", "dpeloy", SearchOptions{Mode: SearchExact}, []int{}},
+		{"exact disables prefix", "rel", SearchOptions{Mode: SearchExact}, []int{}},
+		{"exact whole word", "milk", SearchOptions{Mode: SearchExact}, []int{3}},
+		{"regex", "^(buy|call) ", SearchOptions{Mode: SearchRegex}, []int{3, 4}},
+		{"regex field scoped", "notes:oat.*dairy", SearchOptions{Mode: SearchRegex}, []int{3}},
+		{"no results", "xyz", SearchOptions{}, []int{}},
+		{"category filter", "deploy", SearchOptions{Category: "WORK"}, []int{1, 2}},
+		{"status filter", "deploy", SearchOptions{Status: &[]Status{Completed}[0]}, []int{4}},
+		{"priority filter", "deploy", SearchOptions{Priority: &[]Priority{High}[0]}, []int{2}},
+		{"limit", "deploy", SearchOptions{Limit: 2}, []int{1, 4}},
+		{"regex with filter", "^(buy|call) ", SearchOptions{Mode: SearchRegex, Category: "health"}, []int{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := m.SearchTasks(tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			ids := make([]int, 0)
+			for _, task := range actual {
+				ids = append(ids, task.Id)
+			}
+			if !slices.Equal(ids, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestSearchTasksErrorsTableDriven(t *testing.T) {
+	m := searchTestManager()
+	tests := []struct {
+		name   string
+		query  string
+		opts   SearchOptions
+		errMsg string
+	}{
+		{"unknown field", "colour:red", SearchOptions{}, "invalid search field"},
+		{"empty query", "  ", SearchOptions{}, "cannot be empty"},
+		{"invalid regex", "deploy(", SearchOptions{Mode: SearchRegex}, "invalid search regex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.SearchTasks(tt.query, tt.opts)
+			if err == nil {
+				t.Fatalf("expected error, got none")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("expected error to contain '%v', got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestSearchIndexUpdatesAfterChanges(t *testing.T) {
+	m := searchTestManager()
+	if res, _ := m.SearchTasks("dentist", SearchOptions{}); len(res) != 1 {
+		t.Fatalf("expected 1 result, got %v", res)
+	}
+
+	_, err := m.AddTask("Book dentist follow-up", Low, func(now time.Time) DueDate { return DueDate(now) }, "", WithTags("health"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res, _ := m.SearchTasks("dentist", SearchOptions{}); len(res) != 2 {
+		t.Fatalf("expected 2 results after add, got %v", res)
+	}
+
+	if err := m.DeleteTask(4); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	res, _ := m.SearchTasks("dentist", SearchOptions{})
+	if len(res) != 1 || res[0].Id != 5 {
+		t.Fatalf("expected only task 5 after delete, got %v", res)
+	}
+}
+
+func TestSearchIndexExpand(t *testing.T) {
+	idx := buildSearchIndex([]Task{{Title: "dep deploy deployment redeploy deplyo deploys"}})
+	got := idx.expand("deploy", SearchFuzzy)
+	want := map[string]float64{
+		"deploy":     exactMatch,
+		"deployment": prefixMatch,
+		"deploys":    prefixMatch,
+		"redeploy":   substringMatch,
+		"deplyo":     fuzzyMatch,
+	}
+	if !maps.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func BenchmarkSearchTasks(b *testing.B) {
+	words := []string{"deploy", "release", "review", "milk", "dentist", "taxes", "notes", "report", "meeting", "budget"}
+	tasks := make([]Task, 0, 5000)
+	for i := range 5000 {
+		tasks = append(tasks, Task{
+			Id:          i + 1,
+			Title:       fmt.Sprintf("%s %s %d", words[i%len(words)], words[(i*7)%len(words)], i),
+			Description: words[(i*3)%len(words)],
+		})
+	}
+	m, _ := newManagerInternal("", nil, tasks)
+	b.ResetTimer()
+	for range b.N {
+		_, _ = m.SearchTasks("relase budget", SearchOptions{})
+	}
+}
@@ -0,0 +1,487 @@
+package tasks
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+const maxRequestBytes = 1 << 20
+
+// NewTask is the body of POST /tasks. An omitted priority is medium and an
+// omitted due date is today.
+type NewTask struct {
+	Title       string    `json:"title"`
+	Priority    *Priority `json:"priority,omitempty"`
+	DueDate     *DueDate  `json:"dueDate,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	Status      Status    `json:"status,omitzero"`
+	Description string    `json:"description,omitempty"`
+	Notes       []string  `json:"notes,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"createdAt,omitzero"`
+	CompletedAt time.Time `json:"completedAt,omitzero"`
+	UID         string    `json:"uid,omitempty"`
+	Recurrence  string    `json:"recurrence,omitempty"`
+}
+
+// Add adds the task to m.
+func (n NewTask) Add(m Manager) (*Task, error) {
+	priority := Medium
+	if n.Priority != nil {
+		priority = *n.Priority
+	}
+	due := DueDate(startOfDay(m.Now()))
+	if n.DueDate != nil {
+		due = *n.DueDate
+	}
+	opts := []TaskOption{
+		WithDescription(n.Description),
+		WithNotes(n.Notes...),
+		WithTags(n.Tags...),
+		WithUID(n.UID),
+		WithRecurrence(n.Recurrence),
+		WithCreatedAt(n.CreatedAt),
+	}
+	if n.Status == Completed {
+		opts = append(opts, WithCompletion(n.CompletedAt))
+	}
+	return m.AddTask(n.Title, priority, func(time.Time) DueDate { return due }, n.Category, opts...)
+}
+
+// normalize trims the title and lowercases the category, as add does.
+func (n *NewTask) normalize() error {
+	n.Title = strings.TrimSpace(n.Title)
+	if n.Title == "" {
+		return badRequest("title cannot be empty")
+	}
+	n.Category = strings.ToLower(strings.TrimSpace(n.Category))
+	return nil
+}
+
+// APIError is the body of every error response.
+type APIError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// ServerTime is the body of GET /now. It keeps the server's time zone, so
+// clients can tell which day it is for the server.
+type ServerTime struct {
+	Now time.Time `json:"now"`
+}
+
+// TaskETag returns the entity tag of a task, which changes whenever any of
+// its fields do.
+func TaskETag(t *Task) string {
+	b, _ := json.Marshal(t)
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+type server struct {
+	mu sync.Mutex
+	m  Manager
+}
+
+// NewHandler serves m as a JSON REST API. Managers are not safe for
+// concurrent use, so requests are handled one at a time.
+func NewHandler(m Manager) http.Handler {
+	s := &server{m: m}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", s.listTasks)
+	mux.HandleFunc("POST /tasks", s.addTask)
+	mux.HandleFunc("GET /tasks/search", s.searchTasks)
+	mux.HandleFunc("GET /tasks/query", s.queryTasks)
+	mux.HandleFunc("GET /tasks/{id}", s.getTask)
+	mux.HandleFunc("PATCH /tasks/{id}", s.updateTask)
+	mux.HandleFunc("POST /tasks/{id}/complete", s.completeTask)
+	mux.HandleFunc("DELETE /tasks/{id}", s.deleteTask)
+	mux.HandleFunc("GET /views", s.listViews)
+	mux.HandleFunc("GET /views/{name}", s.getView)
+	mux.HandleFunc("PUT /views/{name}", s.saveView)
+	mux.HandleFunc("DELETE /views/{name}", s.deleteView)
+	mux.HandleFunc("GET /now", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, ServerTime{Now: s.m.Now()})
+	})
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPIDocument)
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// errBadRequest marks errors in the request itself.
+var errBadRequest = errors.New("bad request")
+
+func badRequest(format string, a ...any) error {
+	return fmt.Errorf("%w: %s", errBadRequest, fmt.Sprintf(format, a...))
+}
+
+// errorCode returns the HTTP status and error code reported for err.
+func errorCode(err error) (int, string) {
+	var mediaErr *mediaTypeError
+	switch {
+	case errors.As(err, &mediaErr):
+		return http.StatusUnsupportedMediaType, "unsupported_media_type"
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest, "bad_request"
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound, "not_found"
+	case errors.Is(err, ErrAlreadyCompleted), errors.Is(err, ErrInvalidTransition):
+		return http.StatusConflict, "conflict"
+	case errors.Is(err, ErrChanged):
+		return http.StatusPreconditionFailed, "precondition_failed"
+	case errors.Is(err, ErrStorage):
+		return http.StatusInternalServerError, "storage"
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusBadGateway, "unavailable"
+	case errors.Is(err, ErrRejected):
+		return http.StatusUnprocessableEntity, "rejected"
+	}
+	return http.StatusInternalServerError, "error"
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status, code := errorCode(err)
+	writeJSON(w, status, APIError{Error: err.Error(), Code: code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeTask(w http.ResponseWriter, status int, t *Task) {
+	w.Header().Set("ETag", TaskETag(t))
+	writeJSON(w, status, t)
+}
+
+func writeTasks(w http.ResponseWriter, t []Task, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if t == nil {
+		t = []Task{}
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+// decodeBody reads a JSON body, rejecting unknown fields so that typos are
+// not silently ignored.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if mediaType, _, err := mime.ParseMediaType(ct); err != nil || mediaType != "application/json" {
+			w.Header().Set("Accept", "application/json")
+			return &mediaTypeError{ct}
+		}
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest("invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+type mediaTypeError struct {
+	contentType string
+}
+
+func (e *mediaTypeError) Error() string {
+	return fmt.Sprintf("unsupported content type '%s': use application/json", e.contentType)
+}
+
+// taskFilters reads the status, priority, category and overdue query
+// parameters shared by listing and searching.
+func taskFilters(r *http.Request) (opts SearchOptions, err error) {
+	q := r.URL.Query()
+	if opts.Status, err = parseStatusParam(q.Get("status")); err != nil {
+		return opts, err
+	}
+	if opts.Priority, err = parsePriorityParam(q.Get("priority")); err != nil {
+		return opts, err
+	}
+	opts.Category = q.Get("category")
+	if v := q.Get("overdue"); v != "" {
+		if opts.OverdueOnly, err = strconv.ParseBool(v); err != nil {
+			return opts, badRequest("invalid overdue '%s': must be true or false", v)
+		}
+	}
+	return opts, nil
+}
+
+// parseStatusParam reads a status filter, where empty means any status.
+func parseStatusParam(v string) (*Status, error) {
+	if v == "" {
+		return nil, nil
+	}
+	status, ok := stringToStatus[strings.ToLower(v)]
+	if !ok {
+		return nil, badRequest("invalid status '%s': must be pending or completed", v)
+	}
+	return &status, nil
+}
+
+// parsePriorityParam reads a priority filter, where empty means any
+// priority.
+func parsePriorityParam(v string) (*Priority, error) {
+	if v == "" {
+		return nil, nil
+	}
+	priority, ok := stringToPriority[strings.ToUpper(v)]
+	if !ok {
+		return nil, badRequest("invalid priority '%s': must be low, medium or high", v)
+	}
+	return &priority, nil
+}
+
+// parseSearchMode reads a search mode and checks the query against it.
+func parseSearchMode(mode string, query string) (SearchMode, error) {
+	switch mode {
+	case "", "fuzzy":
+		return SearchFuzzy, nil
+	case "exact":
+		return SearchExact, nil
+	case "regex":
+		if _, err := regexp.Compile(query); err != nil {
+			return SearchFuzzy, badRequest("invalid regular expression: %v", err)
+		}
+		return SearchRegex, nil
+	}
+	return SearchFuzzy, badRequest("invalid mode '%s': must be fuzzy, exact or regex", mode)
+}
+
+// taskFor looks up the task in the path and checks it against If-Match.
+func (s *server) taskFor(r *http.Request) (*Task, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return nil, badRequest("invalid task id '%s'", r.PathValue("id"))
+	}
+	t, err := s.m.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if match := r.Header.Get("If-Match"); match != "" {
+		etag := TaskETag(t)
+		for _, candidate := range strings.Split(match, ",") {
+			if candidate = strings.TrimSpace(candidate); candidate == "*" || candidate == etag {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("task %d: %w", id, ErrChanged)
+	}
+	return t, nil
+}
+
+func (s *server) listTasks(w http.ResponseWriter, r *http.Request) {
+	opts, err := taskFilters(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	t, err := s.m.ListTasks(opts.Status, opts.Priority, opts.Category, opts.OverdueOnly)
+	writeTasks(w, t, err)
+}
+
+func (s *server) searchTasks(w http.ResponseWriter, r *http.Request) {
+	opts, err := taskFilters(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		writeError(w, badRequest("missing search query 'q'"))
+		return
+	}
+	if opts.Mode, err = parseSearchMode(q.Get("mode"), query); err != nil {
+		writeError(w, err)
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		if opts.Limit, err = strconv.Atoi(v); err != nil || opts.Limit < 0 {
+			writeError(w, badRequest("invalid limit '%s'", v))
+			return
+		}
+	}
+	t, err := s.m.SearchTasks(query, opts)
+	writeTasks(w, t, err)
+}
+
+func (s *server) queryTasks(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r.URL.Query().Get("q"))
+	if err != nil {
+		writeError(w, badRequest("%v", err))
+		return
+	}
+	t, err := s.m.QueryTasks(filter)
+	writeTasks(w, t, err)
+}
+
+func (s *server) getTask(w http.ResponseWriter, r *http.Request) {
+	t, err := s.taskFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, t)
+}
+
+func (s *server) addTask(w http.ResponseWriter, r *http.Request) {
+	var n NewTask
+	if err := decodeBody(w, r, &n); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := n.normalize(); err != nil {
+		writeError(w, err)
+		return
+	}
+	t, err := n.Add(s.m)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%d", t.Id))
+	writeTask(w, http.StatusCreated, t)
+}
+
+func (s *server) updateTask(w http.ResponseWriter, r *http.Request) {
+	var patch TaskPatch
+	if err := decodeBody(w, r, &patch); err != nil {
+		writeError(w, err)
+		return
+	}
+	if patch.Title != nil && strings.TrimSpace(*patch.Title) == "" {
+		writeError(w, badRequest("title cannot be empty"))
+		return
+	}
+	current, err := s.taskFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	t, err := s.m.UpdateTask(current.Id, patch)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, t)
+}
+
+func (s *server) completeTask(w http.ResponseWriter, r *http.Request) {
+	current, err := s.taskFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.m.CompleteTask(current.Id); err != nil {
+		writeError(w, err)
+		return
+	}
+	t, err := s.m.GetTask(current.Id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, t)
+}
+
+func (s *server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	current, err := s.taskFor(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.m.DeleteTask(current.Id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) listViews(w http.ResponseWriter, r *http.Request) {
+	v, err := s.m.ListViews()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if v == nil {
+		v = []View{}
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *server) getView(w http.ResponseWriter, r *http.Request) {
+	v, err := s.m.GetView(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *server) saveView(w http.ResponseWriter, r *http.Request) {
+	var v View
+	if err := decodeBody(w, r, &v); err != nil {
+		writeError(w, err)
+		return
+	}
+	name := r.PathValue("name")
+	if v.Name == "" {
+		v.Name = name
+	}
+	if v.Name != name {
+		writeError(w, badRequest("view name '%s' does not match the URL '%s'", v.Name, name))
+		return
+	}
+	if err := checkView(v); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.m.SaveView(v); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *server) deleteView(w http.ResponseWriter, r *http.Request) {
+	if err := s.m.DeleteView(r.PathValue("name")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkView rejects a view whose query would fail every time it is run.
+func checkView(v View) error {
+	if strings.TrimSpace(v.Name) == "" {
+		return badRequest("view name cannot be empty")
+	}
+	if _, err := ParseFilter(v.Query); err != nil {
+		return badRequest("%v", err)
+	}
+	return nil
+}
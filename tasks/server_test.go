@@ -0,0 +1,224 @@
+package tasks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	now := time.Date(2024, 4, 10, 9, 0, 0, 0, time.UTC)
+	day := func(d int) DueDate { return DueDate(time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC)) }
+	m, _ := newManagerInternal("", func() time.Time { return now }, []Task{
+		{Id: 1, Title: "Call dentist", Priority: High, DueDate: day(12), Category: "health", Status: Pending},
+		{Id: 2, Title: "Pay rent", Priority: Medium, DueDate: day(8), Category: "home", Status: Pending},
+		{Id: 3, Title: "Buy milk", Priority: Low, DueDate: day(9), Status: Completed},
+	})
+	srv := httptest.NewServer(NewHandler(m))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func doRequest(t *testing.T, srv *httptest.Server, method string, path string, body string, header map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestServerTableDriven(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		header   map[string]string
+		status   int
+		wantIds  []int
+		wantId   int
+		wantCode string
+	}{
+		{name: "list", method: "GET", path: "/tasks", status: 200, wantIds: []int{1, 2, 3}},
+		{name: "list filters", method: "GET", path: "/tasks?status=pending&priority=medium", status: 200, wantIds: []int{2}},
+		{name: "list overdue", method: "GET", path: "/tasks?overdue=true", status: 200, wantIds: []int{2}},
+		{name: "list category", method: "GET", path: "/tasks?category=health", status: 200, wantIds: []int{1}},
+		{name: "list bad filter", method: "GET", path: "/tasks?priority=urgent", status: 400, wantCode: "bad_request"},
+		{name: "search", method: "GET", path: "/tasks/search?q=dentist", status: 200, wantIds: []int{1}},
+		{name: "search no match", method: "GET", path: "/tasks/search?q=zebra&mode=exact", status: 200, wantIds: []int{}},
+		{name: "search without q", method: "GET", path: "/tasks/search", status: 400, wantCode: "bad_request"},
+		{name: "search bad regex", method: "GET", path: "/tasks/search?q=(&mode=regex", status: 400, wantCode: "bad_request"},
+		{name: "query", method: "GET", path: "/tasks/query?q=status:pending+priority>=medium", status: 200, wantIds: []int{1, 2}},
+		{name: "query everything", method: "GET", path: "/tasks/query", status: 200, wantIds: []int{1, 2, 3}},
+		{name: "query bad", method: "GET", path: "/tasks/query?q=size:big", status: 400, wantCode: "bad_request"},
+		{name: "get missing", method: "GET", path: "/tasks/9", status: 404, wantCode: "not_found"},
+		{name: "get bad id", method: "GET", path: "/tasks/abc", status: 400, wantCode: "bad_request"},
+		{name: "add", method: "POST", path: "/tasks", body: `{"title": "Water plants", "priority": "LOW", "tags": ["garden"]}`, status: 201, wantId: 4},
+		{name: "add empty title", method: "POST", path: "/tasks", body: `{"title": " "}`, status: 400, wantCode: "bad_request"},
+		{name: "add unknown field", method: "POST", path: "/tasks", body: `{"title": "x", "due": "2024-04-12"}`, status: 400, wantCode: "bad_request"},
+		{name: "add not json", method: "POST", path: "/tasks", body: `title=x`, header: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, status: 415, wantCode: "unsupported_media_type"},
+		{name: "patch", method: "PATCH", path: "/tasks/2", body: `{"dueDate": "2024-04-20", "tags": ["bills"]}`, status: 200, wantId: 2},
+		{name: "patch missing", method: "PATCH", path: "/tasks/9", body: `{"title": "x"}`, status: 404, wantCode: "not_found"},
+		{name: "patch stale", method: "PATCH", path: "/tasks/2", body: `{"title": "x"}`, header: map[string]string{"If-Match": `"0000"`}, status: 412, wantCode: "precondition_failed"},
+		{name: "complete", method: "POST", path: "/tasks/1/complete", status: 200, wantId: 1},
+		{name: "complete twice", method: "POST", path: "/tasks/3/complete", status: 409, wantCode: "conflict"},
+		{name: "delete", method: "DELETE", path: "/tasks/2", header: map[string]string{"If-Match": "*"}, status: 204},
+		{name: "delete missing", method: "DELETE", path: "/tasks/9", status: 404, wantCode: "not_found"},
+		{name: "wrong method", method: "PUT", path: "/tasks/1", status: 405},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			resp := doRequest(t, srv, tt.method, tt.path, tt.body, tt.header)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			switch {
+			case tt.wantCode != "":
+				var apiErr APIError
+				if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Code != tt.wantCode || apiErr.Error == "" {
+					t.Fatalf("expected error code %q, got %+v (%v)", tt.wantCode, apiErr, err)
+				}
+			case tt.wantId != 0:
+				var task Task
+				if err := json.NewDecoder(resp.Body).Decode(&task); err != nil || task.Id != tt.wantId {
+					t.Fatalf("expected task %d, got %+v (%v)", tt.wantId, task, err)
+				}
+				if resp.Header.Get("ETag") != TaskETag(&task) {
+					t.Errorf("expected ETag %s, got %s", TaskETag(&task), resp.Header.Get("ETag"))
+				}
+			case tt.wantIds != nil:
+				var got []Task
+				if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+					t.Fatalf("unexpected err: %v", err)
+				}
+				ids := []int{}
+				for _, task := range got {
+					ids = append(ids, task.Id)
+				}
+				if !reflect.DeepEqual(ids, tt.wantIds) {
+					t.Fatalf("expected ids %v, got %v", tt.wantIds, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestServerAddAndUpdate(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := doRequest(t, srv, "POST", "/tasks", `{"title": "Water plants", "category": "Home", "notes": ["twice"]}`, nil)
+	var added Task
+	json.NewDecoder(resp.Body).Decode(&added)
+	if resp.Header.Get("Location") != "/tasks/4" || added.Priority != Medium || added.Category != "home" ||
+		time.Time(added.DueDate).Format("2006-01-02") != "2024-04-10" || len(added.Notes) != 1 {
+		t.Fatalf("unexpected added task %+v (location %q)", added, resp.Header.Get("Location"))
+	}
+
+	// a change based on the current ETag succeeds and changes the ETag
+	etag := resp.Header.Get("ETag")
+	resp = doRequest(t, srv, "PATCH", "/tasks/4", `{"title": "Water the plants", "status": "completed"}`, map[string]string{"If-Match": etag})
+	var updated Task
+	json.NewDecoder(resp.Body).Decode(&updated)
+	if resp.StatusCode != 200 || updated.Title != "Water the plants" || updated.Status != Completed || updated.CompletedAt.IsZero() {
+		t.Fatalf("unexpected update %d %+v", resp.StatusCode, updated)
+	}
+	if resp.Header.Get("ETag") == etag {
+		t.Fatalf("expected the ETag to change")
+	}
+
+	// a second writer holding the old ETag is refused
+	resp = doRequest(t, srv, "DELETE", "/tasks/4", "", map[string]string{"If-Match": etag})
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412, got %d", resp.StatusCode)
+	}
+	resp = doRequest(t, srv, "GET", "/tasks/4", "", nil)
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected the task to survive, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerViews(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := doRequest(t, srv, "PUT", "/views/urgent", `{"query": "priority:high", "sort": "due"}`, nil)
+	var saved View
+	json.NewDecoder(resp.Body).Decode(&saved)
+	if resp.StatusCode != 200 || !reflect.DeepEqual(saved, View{Name: "urgent", Query: "priority:high", Sort: "due"}) {
+		t.Fatalf("unexpected save %d %+v", resp.StatusCode, saved)
+	}
+	for _, tt := range []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{path: "/views/other", body: `{"name": "urgent", "query": ""}`, status: 400},
+		{path: "/views/other", body: `{"query": "size:big"}`, status: 400},
+	} {
+		if resp := doRequest(t, srv, "PUT", tt.path, tt.body, nil); resp.StatusCode != tt.status {
+			t.Errorf("PUT %s %s: expected %d, got %d", tt.path, tt.body, tt.status, resp.StatusCode)
+		}
+	}
+
+	resp = doRequest(t, srv, "GET", "/views", "", nil)
+	var views []View
+	if err := json.NewDecoder(resp.Body).Decode(&views); err != nil || len(views) != 1 || views[0].Name != "urgent" {
+		t.Fatalf("unexpected views %+v (%v)", views, err)
+	}
+	if resp := doRequest(t, srv, "DELETE", "/views/urgent", "", nil); resp.StatusCode != 204 {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, srv, "GET", "/views/urgent", "", nil); resp.StatusCode != 404 {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerOpenAPI(t *testing.T) {
+	srv := newTestServer(t)
+	resp := doRequest(t, srv, "GET", "/openapi.json", "", nil)
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// every route served is documented
+	for path, methods := range map[string][]string{
+		"/tasks":               {"get", "post"},
+		"/tasks/search":        {"get"},
+		"/tasks/{id}":          {"get", "patch", "delete"},
+		"/tasks/{id}/complete": {"post"},
+		"/tasks/query":         {"get"},
+		"/views":               {"get"},
+		"/views/{name}":        {"get", "put", "delete"},
+		"/now":                 {"get"},
+	} {
+		for _, method := range methods {
+			if _, ok := doc.Paths[path][method]; !ok {
+				t.Errorf("expected %s %s to be documented", method, path)
+			}
+		}
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("expected an OpenAPI 3 document, got %q", doc.OpenAPI)
+	}
+}
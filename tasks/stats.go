@@ -0,0 +1,244 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type StatsOptions struct {
+	Now       time.Time
+	WeekStart time.Weekday
+	Weeks     int
+	Oldest    int
+}
+
+type GroupStats struct {
+	Name      string `json:"name"`
+	Pending   int    `json:"pending"`
+	Completed int    `json:"completed"`
+}
+
+type WeekStats struct {
+	Start     DueDate `json:"start"`
+	Created   int     `json:"created"`
+	Completed int     `json:"completed"`
+	// CompletionRate is the share of tasks existing by the end of the week
+	// that were completed by then.
+	CompletionRate float64 `json:"completionRate"`
+}
+
+type OpenTask struct {
+	Id        int       `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+	AgeDays   int       `json:"ageDays"`
+}
+
+type Stats struct {
+	Total          int          `json:"total"`
+	Pending        int          `json:"pending"`
+	Completed      int          `json:"completed"`
+	Overdue        int          `json:"overdue"`
+	CompletionRate float64      `json:"completionRate"`
+	ByPriority     []GroupStats `json:"byPriority"`
+	ByCategory     []GroupStats `json:"byCategory"`
+	// AverageCompletionHours only counts tasks with both timestamps.
+	AverageCompletionHours float64     `json:"averageCompletionHours"`
+	TimedCompletions       int         `json:"timedCompletions"`
+	OldestOpen             []OpenTask  `json:"oldestOpen"`
+	Weekly                 []WeekStats `json:"weekly"`
+}
+
+// ComputeStats summarizes tasks as of opts.Now. Tasks saved before creation
+// and completion times were recorded count as created before the first week
+// and, when completed, as completed before it too.
+func ComputeStats(t []Task, opts StatsOptions) Stats {
+	s := Stats{Total: len(t), ByPriority: []GroupStats{}, ByCategory: []GroupStats{}, OldestOpen: []OpenTask{}, Weekly: []WeekStats{}}
+	priorities := []Priority{High, Medium, Low}
+	for _, p := range priorities {
+		s.ByPriority = append(s.ByPriority, GroupStats{Name: p.String()})
+	}
+	byCategory := map[string]*GroupStats{}
+	var total time.Duration
+	for _, task := range t {
+		category := strings.ToLower(strings.TrimSpace(task.Category))
+		if byCategory[category] == nil {
+			byCategory[category] = &GroupStats{Name: category}
+		}
+		groups := []*GroupStats{byCategory[category]}
+		if i := slices.Index(priorities, task.Priority); i >= 0 {
+			groups = append(groups, &s.ByPriority[i])
+		}
+		for _, g := range groups {
+			if task.Status == Completed {
+				g.Completed++
+			} else {
+				g.Pending++
+			}
+		}
+		if task.Status == Completed {
+			s.Completed++
+			if !task.CreatedAt.IsZero() && !task.CompletedAt.IsZero() {
+				total += task.CompletedAt.Sub(task.CreatedAt)
+				s.TimedCompletions++
+			}
+			continue
+		}
+		s.Pending++
+		if IsOverdue(&task, opts.Now) {
+			s.Overdue++
+		}
+	}
+	if s.Total > 0 {
+		s.CompletionRate = float64(s.Completed) / float64(s.Total)
+	}
+	if s.TimedCompletions > 0 {
+		s.AverageCompletionHours = total.Hours() / float64(s.TimedCompletions)
+	}
+	for _, g := range byCategory {
+		s.ByCategory = append(s.ByCategory, *g)
+	}
+	slices.SortFunc(s.ByCategory, func(a, b GroupStats) int {
+		if n := (b.Pending + b.Completed) - (a.Pending + a.Completed); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	open := slices.DeleteFunc(slices.Clone(t), func(task Task) bool { return task.Status == Completed })
+	slices.SortStableFunc(open, func(a, b Task) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return a.Id - b.Id
+	})
+	for _, task := range open[:min(len(open), opts.Oldest)] {
+		age := 0
+		if !task.CreatedAt.IsZero() {
+			age = int(startOfDay(opts.Now).Sub(startOfDay(task.CreatedAt)).Hours() / 24)
+		}
+		s.OldestOpen = append(s.OldestOpen, OpenTask{Id: task.Id, Title: task.Title, CreatedAt: task.CreatedAt, AgeDays: age})
+	}
+
+	first := StartOfWeek(opts.Now, opts.WeekStart).AddDate(0, 0, -7*(opts.Weeks-1))
+	for i := range opts.Weeks {
+		start := first.AddDate(0, 0, 7*i)
+		end := start.AddDate(0, 0, 7)
+		week := WeekStats{Start: DueDate(start)}
+		existing, done := 0, 0
+		for _, task := range t {
+			if inWeek(task.CreatedAt, start, end) {
+				week.Created++
+			}
+			if task.Status == Completed && inWeek(task.CompletedAt, start, end) {
+				week.Completed++
+			}
+			if task.CreatedAt.Before(end) {
+				existing++
+				if task.Status == Completed && task.CompletedAt.Before(end) {
+					done++
+				}
+			}
+		}
+		if existing > 0 {
+			week.CompletionRate = float64(done) / float64(existing)
+		}
+		s.Weekly = append(s.Weekly, week)
+	}
+	return s
+}
+
+func inWeek(t time.Time, start time.Time, end time.Time) bool {
+	return !t.IsZero() && !t.Before(start) && t.Before(end)
+}
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws one bar per value, scaled to the largest value.
+func Sparkline(values []int) string {
+	top := slices.Max(append([]int{0}, values...))
+	sb := strings.Builder{}
+	for _, v := range values {
+		if top == 0 {
+			sb.WriteRune(sparkBars[0])
+			continue
+		}
+		sb.WriteRune(sparkBars[int(math.Round(float64(v)/float64(top)*float64(len(sparkBars)-1)))])
+	}
+	return sb.String()
+}
+
+func RenderStats(s Stats) string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "Tasks:      %d (%d pending, %d completed)\n", s.Total, s.Pending, s.Completed)
+	fmt.Fprintf(&sb, "Overdue:    %d\n", s.Overdue)
+	fmt.Fprintf(&sb, "Completion: %.0f%%\n", s.CompletionRate*100)
+	if s.TimedCompletions > 0 {
+		fmt.Fprintf(&sb, "Avg. time to complete: %s (%d task(s))\n", formatHours(s.AverageCompletionHours), s.TimedCompletions)
+	}
+
+	for _, group := range []struct {
+		title string
+		stats []GroupStats
+	}{{"Priority", s.ByPriority}, {"Category", s.ByCategory}} {
+		sb.WriteString("\n")
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "%s\tPending\tCompleted\n", group.title)
+		for _, g := range group.stats {
+			name := g.Name
+			if name == "" {
+				name = " - "
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%d\n", name, g.Pending, g.Completed)
+		}
+		_ = w.Flush()
+	}
+
+	if len(s.OldestOpen) > 0 {
+		sb.WriteString("\nOldest open tasks\n")
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		for _, task := range s.OldestOpen {
+			age := " - "
+			if !task.CreatedAt.IsZero() {
+				age = fmt.Sprintf("%dd", task.AgeDays)
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", task.Id, truncate(task.Title, 50), age)
+		}
+		_ = w.Flush()
+	}
+
+	if len(s.Weekly) > 0 {
+		created := make([]int, 0, len(s.Weekly))
+		completed := make([]int, 0, len(s.Weekly))
+		for _, week := range s.Weekly {
+			created = append(created, week.Created)
+			completed = append(completed, week.Completed)
+		}
+		last := s.Weekly[len(s.Weekly)-1]
+		fmt.Fprintf(&sb, "\nWeekly throughput since %s\n", time.Time(s.Weekly[0].Start).Format("2006-01-02"))
+		fmt.Fprintf(&sb, "Completed  %s  (%d this week)\n", Sparkline(completed), last.Completed)
+		fmt.Fprintf(&sb, "Created    %s  (%d this week)\n", Sparkline(created), last.Created)
+		fmt.Fprintf(&sb, "Completion rate %.0f%% → %.0f%%\n", s.Weekly[0].CompletionRate*100, last.CompletionRate*100)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func formatHours(h float64) string {
+	if h < 48 {
+		return fmt.Sprintf("%.1fh", h)
+	}
+	return fmt.Sprintf("%.1fd", h/24)
+}
+
+func RenderStatsJSON(s Stats) (string, error) {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,109 @@
+package tasks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+	ago := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	all := []Task{
+		{Id: 1, Title: "Legacy", Priority: Low, DueDate: DueDate(ago(30)), Category: "Home", Status: Pending},
+		{Id: 2, Title: "Call dentist", Priority: High, DueDate: DueDate(ago(1)), Category: "health", Status: Pending, CreatedAt: ago(20)},
+		{Id: 3, Title: "Buy milk", Priority: Medium, DueDate: DueDate(now), Status: Pending, CreatedAt: ago(2)},
+		{Id: 4, Title: "Pay rent", Priority: High, DueDate: DueDate(ago(9)), Category: "home", Status: Completed, CreatedAt: ago(10), CompletedAt: ago(9)},
+		{Id: 5, Title: "File taxes", Priority: High, DueDate: DueDate(now), Category: "home", Status: Completed, CreatedAt: ago(3), CompletedAt: ago(0)},
+		{Id: 6, Title: "Old chore", Priority: Medium, DueDate: DueDate(ago(40)), Status: Completed},
+	}
+
+	s := ComputeStats(all, StatsOptions{Now: now, WeekStart: time.Monday, Weeks: 3, Oldest: 2})
+
+	if s.Total != 6 || s.Pending != 3 || s.Completed != 3 || s.Overdue != 2 || s.CompletionRate != 0.5 {
+		t.Fatalf("unexpected counts: %+v", s)
+	}
+	if s.TimedCompletions != 2 || s.AverageCompletionHours != 48 {
+		t.Fatalf("expected an average of 48h over 2 tasks, got %vh over %d", s.AverageCompletionHours, s.TimedCompletions)
+	}
+	wantPriority := []GroupStats{{"HIGH", 1, 2}, {"MEDIUM", 1, 1}, {"LOW", 1, 0}}
+	for i, want := range wantPriority {
+		if s.ByPriority[i] != want {
+			t.Fatalf("expected priority stats %v, got %v", wantPriority, s.ByPriority)
+		}
+	}
+	wantCategory := []GroupStats{{"home", 1, 2}, {"", 1, 1}, {"health", 1, 0}}
+	for i, want := range wantCategory {
+		if len(s.ByCategory) != len(wantCategory) || s.ByCategory[i] != want {
+			t.Fatalf("expected category stats %v, got %v", wantCategory, s.ByCategory)
+		}
+	}
+	wantOldest := []OpenTask{{1, "Legacy", time.Time{}, 0}, {2, "Call dentist", ago(20), 20}}
+	for i, want := range wantOldest {
+		if len(s.OldestOpen) != len(wantOldest) || s.OldestOpen[i] != want {
+			t.Fatalf("expected oldest open tasks %v, got %v", wantOldest, s.OldestOpen)
+		}
+	}
+
+	wantWeeks := []struct {
+		start              time.Time
+		created, completed int
+		rate               float64
+	}{
+		{time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC), 1, 0, 1.0 / 4},
+		{time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), 1, 1, 2.0 / 5},
+		{time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC), 1, 1, 3.0 / 6},
+	}
+	if len(s.Weekly) != len(wantWeeks) {
+		t.Fatalf("expected %d weeks, got %v", len(wantWeeks), s.Weekly)
+	}
+	for i, want := range wantWeeks {
+		got := s.Weekly[i]
+		if !time.Time(got.Start).Equal(want.start) || got.Created != want.created || got.Completed != want.completed || got.CompletionRate != want.rate {
+			t.Fatalf("week %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	text := RenderStats(s)
+	for _, want := range []string{"Tasks:      6 (3 pending, 3 completed)", "Overdue:    2", "Avg. time to complete: 2.0d (2 task(s))", "Completed  ▁██  (1 this week)", "1  Legacy         - \n", "2  Call dentist  20d"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected report to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	out, err := RenderStatsJSON(s)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	var decoded Stats
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil || decoded.Total != 6 || len(decoded.Weekly) != 3 {
+		t.Fatalf("expected JSON to round-trip, got %+v (%v)", decoded, err)
+	}
+}
+
+func TestSparklineTableDriven(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{[]int{}, ""},
+		{[]int{0, 0}, "▁▁"},
+		{[]int{0, 7}, "▁█"},
+		{[]int{1, 2, 4, 8}, "▂▃▅█"},
+	}
+
+	for _, tt := range tests {
+		if got := Sparkline(tt.values); got != tt.want {
+			t.Fatalf("Sparkline(%v): expected %q, got %q", tt.values, tt.want, got)
+		}
+	}
+}
+
+func TestTaskTimestampsJSON(t *testing.T) {
+	b, err := json.Marshal(Task{Id: 1, Title: "Old"})
+	if err != nil || strings.Contains(string(b), "createdAt") || strings.Contains(string(b), "completedAt") {
+		t.Fatalf("expected zero timestamps to be omitted, got %s (%v)", b, err)
+	}
+}
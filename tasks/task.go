@@ -1,8 +1,10 @@
 package tasks
 
 import (
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -112,10 +114,124 @@ func (d *DueDate) UnmarshalJSON(b []byte) error {
 }
 
 type Task struct {
-	Id       int      `json:"id"`
-	Title    string   `json:"title"`
-	Priority Priority `json:"priority"`
-	DueDate  DueDate  `json:"dueDate"`
-	Category string   `json:"category"`
-	Status   Status   `json:"status"`
+	Id          int       `json:"id"`
+	Title       string    `json:"title"`
+	Priority    Priority  `json:"priority"`
+	DueDate     DueDate   `json:"dueDate"`
+	Category    string    `json:"category"`
+	Status      Status    `json:"status"`
+	Description string    `json:"description,omitempty"`
+	Notes       []string  `json:"notes,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"createdAt,omitzero"`
+	CompletedAt time.Time `json:"completedAt,omitzero"`
+	// UID identifies an imported task in the file it came from.
+	UID string `json:"uid,omitempty"`
+	// Recurrence is an iCalendar RRULE value such as "FREQ=WEEKLY". It is
+	// kept for import and export; tasks do not repeat on their own.
+	Recurrence string `json:"recurrence,omitempty"`
+}
+
+// TaskUID returns t.UID, or for tasks that were not imported a UUID derived
+// from the id and creation time, so repeated exports agree.
+func TaskUID(t *Task) string {
+	if t.UID != "" {
+		return t.UID
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("golang-todo-cli:%d:%d", t.Id, t.CreatedAt.Unix())))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+type TaskOption func(t *Task)
+
+func WithDescription(description string) TaskOption {
+	return func(t *Task) { t.Description = description }
+}
+
+func WithNotes(notes ...string) TaskOption {
+	return func(t *Task) { t.Notes = append(t.Notes, notes...) }
+}
+
+func WithTags(tags ...string) TaskOption {
+	return func(t *Task) { t.Tags = append(t.Tags, tags...) }
+}
+
+func WithUID(uid string) TaskOption {
+	return func(t *Task) { t.UID = uid }
+}
+
+func WithRecurrence(rule string) TaskOption {
+	return func(t *Task) { t.Recurrence = rule }
+}
+
+// WithCreatedAt keeps the creation time of an imported task; a zero time
+// keeps the time it was added.
+func WithCreatedAt(at time.Time) TaskOption {
+	return func(t *Task) {
+		if !at.IsZero() {
+			t.CreatedAt = at
+		}
+	}
+}
+
+// WithCompletion adds the task as completed at the given time, which may be
+// zero when it is not known.
+func WithCompletion(at time.Time) TaskOption {
+	return func(t *Task) {
+		t.Status = Completed
+		t.CompletedAt = at
+	}
+}
+
+type TaskPatch struct {
+	Title       *string   `json:"title,omitempty"`
+	Priority    *Priority `json:"priority,omitempty"`
+	DueDate     *DueDate  `json:"dueDate,omitempty"`
+	Category    *string   `json:"category,omitempty"`
+	Status      *Status   `json:"status,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Notes       *[]string `json:"notes,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+	Recurrence  *string   `json:"recurrence,omitempty"`
+	// CompletedAt overrides the time recorded when Status changes.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+func (p TaskPatch) IsEmpty() bool {
+	return p == TaskPatch{}
+}
+
+func (p TaskPatch) apply(t *Task) {
+	if p.Title != nil {
+		t.Title = *p.Title
+	}
+	if p.Priority != nil {
+		t.Priority = *p.Priority
+	}
+	if p.DueDate != nil {
+		t.DueDate = *p.DueDate
+	}
+	if p.Category != nil {
+		t.Category = *p.Category
+	}
+	if p.Status != nil {
+		t.Status = *p.Status
+	}
+	if p.Description != nil {
+		t.Description = *p.Description
+	}
+	if p.Notes != nil {
+		t.Notes = slices.Clone(*p.Notes)
+	}
+	if p.Tags != nil {
+		t.Tags = slices.Clone(*p.Tags)
+	}
+	if p.Recurrence != nil {
+		t.Recurrence = *p.Recurrence
+	}
+	if p.CompletedAt != nil {
+		t.CompletedAt = *p.CompletedAt
+	}
 }
@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -97,12 +98,15 @@ func TestDueDateJSONRoundTrip(t *testing.T) {
 
 func TestTaskJSONRoundTrip(t *testing.T) {
 	task := Task{
-		Id:       1,
-		Title:    "Make vet appointment",
-		Priority: High,
-		DueDate:  DueDate(time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)),
-		Category: "pet stuff",
-		Status:   Pending,
+		Id:          1,
+		Title:       "Make vet appointment",
+		Priority:    High,
+		DueDate:     DueDate(time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)),
+		Category:    "pet stuff",
+		Status:      Pending,
+		Description: "Annual checkup",
+		Notes:       []string{"Bring vaccination records"},
+		Tags:        []string{"pets", "health"},
 	}
 
 	marshaled, err := json.Marshal(task)
@@ -116,7 +120,7 @@ func TestTaskJSONRoundTrip(t *testing.T) {
 		t.Errorf("failed to unmarshal task: %v", err)
 	}
 
-	if task != unmarshaled {
+	if !reflect.DeepEqual(task, unmarshaled) {
 		t.Errorf("task %v != unmarshaled %v", task, unmarshaled)
 	}
 }
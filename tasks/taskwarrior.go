@@ -0,0 +1,222 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const taskwarriorTimeLayout = "20060102T150405Z"
+
+var (
+	taskwarriorPriority   = map[Priority]string{High: "H", Medium: "M", Low: "L"}
+	taskwarriorPriorities = map[string]Priority{"H": High, "M": Medium, "L": Low}
+)
+
+type taskwarriorAnnotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+// taskwarriorTask holds the fields of a 'task export' record that map onto
+// a Task. Taskwarrior writes times as UTC in its own compact layout.
+type taskwarriorTask struct {
+	UUID        string                  `json:"uuid"`
+	Description string                  `json:"description"`
+	Status      string                  `json:"status"`
+	Entry       string                  `json:"entry,omitempty"`
+	End         string                  `json:"end,omitempty"`
+	Due         string                  `json:"due,omitempty"`
+	Project     string                  `json:"project,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Priority    string                  `json:"priority,omitempty"`
+	Annotations []taskwarriorAnnotation `json:"annotations,omitempty"`
+	Recur       string                  `json:"recur,omitempty"`
+}
+
+func formatTaskwarriorTime(t time.Time) string {
+	return t.UTC().Format(taskwarriorTimeLayout)
+}
+
+// ExportTaskwarrior writes tasks in the JSON array format of 'task export',
+// which 'task import' reads. Due dates become local midnight and notes
+// become annotations.
+func ExportTaskwarrior(w io.Writer, t []Task, now time.Time) ([]Issue, error) {
+	dropped := map[string]int{}
+	records := make([]taskwarriorTask, 0, len(t))
+	for _, task := range t {
+		entry := task.CreatedAt
+		if entry.IsZero() {
+			entry = now
+		}
+		due := time.Time(task.DueDate)
+		record := taskwarriorTask{
+			UUID:        TaskUID(&task),
+			Description: task.Title,
+			Status:      "pending",
+			Entry:       formatTaskwarriorTime(entry),
+			Due:         formatTaskwarriorTime(time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, now.Location())),
+			Project:     task.Category,
+			Tags:        task.Tags,
+			Priority:    taskwarriorPriority[task.Priority],
+		}
+		if task.Status == Completed {
+			record.Status = "completed"
+			end := task.CompletedAt
+			if end.IsZero() {
+				end = now
+			}
+			record.End = formatTaskwarriorTime(end)
+		}
+		for _, note := range task.Notes {
+			record.Annotations = append(record.Annotations, taskwarriorAnnotation{Entry: record.Entry, Description: note})
+		}
+		if task.Description != "" {
+			dropped["description"]++
+		}
+		if task.Recurrence != "" {
+			dropped["recurrence"]++
+		}
+		records = append(records, record)
+	}
+	var issues []Issue
+	for _, field := range []string{"description", "recurrence"} {
+		if n := dropped[field]; n > 0 {
+			issues = append(issues, Issue{Message: fmt.Sprintf("%s dropped from %d task(s)", field, n)})
+		}
+	}
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return issues, err
+}
+
+// ImportTaskwarrior reads 'task export' output: a JSON array, or one object
+// per line as older versions write it. Deleted tasks and recurring
+// templates are skipped; tasks without a due date are due on now.
+func ImportTaskwarrior(r io.Reader, now time.Time) ([]Task, []Issue, error) {
+	var records []taskwarriorTask
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+			var batch []taskwarriorTask
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				return nil, nil, err
+			}
+			records = append(records, batch...)
+			continue
+		}
+		var record taskwarriorTask
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, nil, err
+		}
+		records = append(records, record)
+	}
+
+	var result []Task
+	var issues []Issue
+	for i, record := range records {
+		name := fmt.Sprintf("task %d", i+1)
+		if record.UUID != "" {
+			name = "task " + record.UUID
+		}
+		warn := func(format string, a ...any) {
+			issues = append(issues, Issue{Message: name + ": " + fmt.Sprintf(format, a...)})
+		}
+		skip := func(reason string) {
+			issues = append(issues, Issue{Message: name + ": " + reason, Skipped: true})
+		}
+		title := strings.TrimSpace(record.Description)
+		switch {
+		case title == "":
+			skip("no description")
+			continue
+		case record.Status == "deleted":
+			skip("deleted")
+			continue
+		case record.Status == "recurring":
+			skip("recurring template (its pending instances are imported)")
+			continue
+		}
+
+		task := Task{
+			Title:    title,
+			Priority: Medium,
+			DueDate:  DueDate(startOfDay(now)),
+			Category: strings.ToLower(record.Project),
+			Status:   Pending,
+			Tags:     record.Tags,
+			UID:      strings.ToLower(record.UUID),
+		}
+		switch record.Status {
+		case "pending", "waiting", "":
+		case "completed":
+			task.Status = Completed
+		default:
+			warn("unknown status '%s' imported as pending", record.Status)
+		}
+		if record.Priority != "" {
+			if p, ok := taskwarriorPriorities[strings.ToUpper(record.Priority)]; ok {
+				task.Priority = p
+			} else {
+				warn("unknown priority '%s' imported as MEDIUM", record.Priority)
+			}
+		}
+		times := []struct {
+			name  string
+			value string
+			set   func(time.Time)
+		}{
+			{"due", record.Due, func(at time.Time) { task.DueDate = DueDate(startOfDay(at.In(now.Location()))) }},
+			{"entry", record.Entry, func(at time.Time) { task.CreatedAt = at }},
+			{"end", record.End, func(at time.Time) {
+				if task.Status == Completed {
+					task.CompletedAt = at
+				}
+			}},
+		}
+		for _, field := range times {
+			if field.value == "" {
+				continue
+			}
+			at, err := parseTaskwarriorTime(field.value)
+			if err != nil {
+				warn("invalid %s '%s' ignored", field.name, field.value)
+				continue
+			}
+			field.set(at)
+		}
+		for _, annotation := range record.Annotations {
+			if note := strings.TrimSpace(annotation.Description); note != "" {
+				task.Notes = append(task.Notes, note)
+			}
+		}
+		if record.Recur != "" {
+			warn("recurrence '%s' dropped", record.Recur)
+		}
+		result = append(result, task)
+	}
+	return result, issues, nil
+}
+
+// parseTaskwarriorTime reads the compact layout of 'task export' and the
+// ISO 8601 layout some tools write instead.
+func parseTaskwarriorTime(s string) (time.Time, error) {
+	for _, layout := range []string{taskwarriorTimeLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s'", s)
+}
@@ -0,0 +1,89 @@
+package tasks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTaskwarriorRoundTrip(t *testing.T) {
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC) }
+	want := []Task{
+		{Title: "Call dentist", Priority: High, DueDate: DueDate(day(12)), Category: "health", Status: Pending,
+			Tags: []string{"phone"}, Notes: []string{"Morning only"}, CreatedAt: day(1), UID: "0b6c4c62-8e2f-4d5a-9a53-6c1f3c0f1a01"},
+		{Title: "File taxes", Priority: Low, DueDate: DueDate(day(9)), Status: Completed, CreatedAt: day(2), CompletedAt: day(8),
+			UID: "0b6c4c62-8e2f-4d5a-9a53-6c1f3c0f1a02"},
+	}
+
+	sb := strings.Builder{}
+	issues, err := ExportTaskwarrior(&sb, want, now)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("unexpected err: %v %v", err, issues)
+	}
+	for _, field := range []string{`"priority": "H"`, `"project": "health"`, `"due": "20240412T000000Z"`, `"end": "20240408T000000Z"`, `"status": "completed"`} {
+		if !strings.Contains(sb.String(), field) {
+			t.Errorf("expected export to contain %s, got\n%s", field, sb.String())
+		}
+	}
+
+	got, issues, err := ImportTaskwarrior(strings.NewReader(sb.String()), now)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("unexpected err: %v %v", err, issues)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected\n%+v\ngot\n%+v", want, got)
+	}
+}
+
+func TestImportTaskwarriorTableDriven(t *testing.T) {
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+	today := DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name       string
+		in         string
+		want       []Task
+		wantIssues []string
+	}{
+		{
+			name: "one object per line",
+			in: `{"uuid":"A1","description":"Water plants","status":"waiting","project":"Home.Garden","priority":"L","due":"20240411T220000Z"}
+{"uuid":"a2","description":"Old","status":"deleted"}`,
+			want: []Task{{Title: "Water plants", Priority: Low, DueDate: DueDate(time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC)),
+				Category: "home.garden", Status: Pending, UID: "a1"}},
+			wantIssues: []string{"task a2: deleted"},
+		},
+		{
+			name: "warnings",
+			in:   `[{"uuid":"b1","description":"Standup","status":"pending","priority":"X","recur":"weekly","entry":"soon"}, {"uuid":"b2","status":"recurring","description":"Standup"}, {"description":" "}]`,
+			want: []Task{{Title: "Standup", Priority: Medium, DueDate: today, Status: Pending, UID: "b1"}},
+			wantIssues: []string{
+				"task b1: unknown priority 'X' imported as MEDIUM",
+				"task b1: invalid entry 'soon' ignored",
+				"task b1: recurrence 'weekly' dropped",
+				"task b2: recurring template (its pending instances are imported)",
+				"task 3: no description",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, issues, err := ImportTaskwarrior(strings.NewReader(tt.in), now)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected\n%+v\ngot\n%+v", tt.want, got)
+			}
+			gotIssues := []string{}
+			for _, issue := range issues {
+				gotIssues = append(gotIssues, issue.String())
+			}
+			if strings.Join(gotIssues, "\n") != strings.Join(tt.wantIssues, "\n") {
+				t.Fatalf("expected issues %q, got %q", tt.wantIssues, gotIssues)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package tasks
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	todoTxtPriorityRegex = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtPriority      = map[Priority]string{High: "A", Medium: "B", Low: "C"}
+)
+
+// ExportTodoTxt writes one todo.txt line per task. Fields todo.txt has no
+// place for are reported rather than written.
+func ExportTodoTxt(w io.Writer, t []Task, _ time.Time) ([]Issue, error) {
+	var issues []Issue
+	dropped := map[string]int{}
+	bw := bufio.NewWriter(w)
+	for _, task := range t {
+		var parts []string
+		if task.Status == Completed {
+			parts = append(parts, "x")
+			if !task.CompletedAt.IsZero() {
+				parts = append(parts, task.CompletedAt.Format("2006-01-02"))
+				if !task.CreatedAt.IsZero() {
+					parts = append(parts, task.CreatedAt.Format("2006-01-02"))
+				}
+			} else if !task.CreatedAt.IsZero() {
+				// a creation date needs a completion date before it
+				dropped["creation date"]++
+			}
+		} else {
+			parts = append(parts, "("+todoTxtPriority[task.Priority]+")")
+			if !task.CreatedAt.IsZero() {
+				parts = append(parts, task.CreatedAt.Format("2006-01-02"))
+			}
+		}
+		parts = append(parts, task.Title)
+		for _, word := range strings.Fields(task.Title) {
+			if _, ok := todoTxtWord(word); ok {
+				issues = append(issues, Issue{Message: fmt.Sprintf("task %d: title word '%s' will be read back as a todo.txt field", task.Id, word)})
+				break
+			}
+		}
+		if task.Category != "" {
+			context := strings.Join(strings.Fields(task.Category), "_")
+			if context != task.Category {
+				issues = append(issues, Issue{Message: fmt.Sprintf("task %d: category '%s' written as @%s", task.Id, task.Category, context)})
+			}
+			parts = append(parts, "@"+context)
+		}
+		for _, tag := range task.Tags {
+			project := strings.Join(strings.Fields(tag), "_")
+			if project != tag {
+				issues = append(issues, Issue{Message: fmt.Sprintf("task %d: tag '%s' written as +%s", task.Id, tag, project)})
+			}
+			parts = append(parts, "+"+project)
+		}
+		parts = append(parts, "due:"+time.Time(task.DueDate).Format("2006-01-02"))
+		if task.Status == Completed {
+			parts = append(parts, "pri:"+todoTxtPriority[task.Priority])
+		}
+		if task.Description != "" {
+			dropped["description"]++
+		}
+		if len(task.Notes) > 0 {
+			dropped["notes"]++
+		}
+		if task.Recurrence != "" {
+			dropped["recurrence"]++
+		}
+		bw.WriteString(strings.Join(parts, " ") + "\n")
+	}
+	for _, field := range []string{"description", "notes", "recurrence", "creation date"} {
+		if n := dropped[field]; n > 0 {
+			issues = append(issues, Issue{Message: fmt.Sprintf("%s dropped from %d task(s)", field, n)})
+		}
+	}
+	return issues, bw.Flush()
+}
+
+// todoTxtWord reports whether a word is a context, project, or a key:value
+// field that ImportTodoTxt reads.
+func todoTxtWord(word string) (string, bool) {
+	if len(word) > 1 && (word[0] == '@' || word[0] == '+') {
+		return word[:1], true
+	}
+	if key, value, ok := strings.Cut(word, ":"); ok && value != "" && (key == "due" || key == "pri") {
+		return key, true
+	}
+	return "", false
+}
+
+func parseTodoTxtDate(s string) (time.Time, bool) {
+	at, err := time.Parse("2006-01-02", s)
+	return at, err == nil
+}
+
+// ImportTodoTxt reads todo.txt lines. The first @context is the category and
+// +projects are tags; other key:value fields stay in the title.
+func ImportTodoTxt(r io.Reader, now time.Time) ([]Task, []Issue, error) {
+	var result []Task
+	var issues []Issue
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		warn := func(format string, a ...any) {
+			issues = append(issues, Issue{Line: n, Message: fmt.Sprintf(format, a...)})
+		}
+		task := Task{Priority: Medium, Status: Pending, DueDate: DueDate(startOfDay(now))}
+		letter := ""
+		if fields[0] == "x" {
+			task.Status = Completed
+			fields = fields[1:]
+		}
+		if len(fields) > 0 && todoTxtPriorityRegex.MatchString(fields[0]) {
+			letter, fields = fields[0][1:2], fields[1:]
+		}
+		if len(fields) > 0 {
+			if at, ok := parseTodoTxtDate(fields[0]); ok {
+				fields = fields[1:]
+				if task.Status == Completed {
+					task.CompletedAt = at
+					if len(fields) > 0 {
+						if created, ok := parseTodoTxtDate(fields[0]); ok {
+							task.CreatedAt, fields = created, fields[1:]
+						}
+					}
+				} else {
+					task.CreatedAt = at
+				}
+			}
+		}
+
+		var title []string
+		for _, word := range fields {
+			kind, ok := todoTxtWord(word)
+			switch {
+			case !ok:
+				title = append(title, word)
+			case kind == "@" && task.Category == "":
+				task.Category = strings.ToLower(word[1:])
+			case kind == "@":
+				warn("extra context %s dropped", word)
+			case kind == "+":
+				task.Tags = append(task.Tags, word[1:])
+			case kind == "due":
+				if at, ok := parseTodoTxtDate(word[4:]); ok {
+					task.DueDate = DueDate(at)
+				} else {
+					warn("invalid due date '%s', using today", word[4:])
+				}
+			case kind == "pri":
+				letter = strings.ToUpper(word[4:])
+			}
+		}
+		task.Title = strings.Join(title, " ")
+		if task.Title == "" {
+			issues = append(issues, Issue{Line: n, Message: "task has no title", Skipped: true})
+			continue
+		}
+		switch letter {
+		case "", "B":
+		case "A":
+			task.Priority = High
+		case "C":
+			task.Priority = Low
+		default:
+			task.Priority = Low
+			warn("priority (%s) imported as LOW", letter)
+		}
+		result = append(result, task)
+	}
+	return result, issues, sc.Err()
+}
@@ -0,0 +1,115 @@
+package tasks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 4, d, 0, 0, 0, 0, time.UTC) }
+	want := []Task{
+		{Title: "Call dentist", Priority: High, DueDate: DueDate(day(12)), Category: "health", Status: Pending, Tags: []string{"phone", "q2"}, CreatedAt: day(1)},
+		{Title: "Buy milk", Priority: Medium, DueDate: DueDate(day(10)), Status: Pending},
+		{Title: "File taxes", Priority: Low, DueDate: DueDate(day(9)), Category: "finance", Status: Completed, CreatedAt: day(2), CompletedAt: day(8)},
+		{Title: "Old chore", Priority: High, DueDate: DueDate(day(3)), Status: Completed},
+	}
+
+	sb := strings.Builder{}
+	issues, err := ExportTodoTxt(&sb, want, now)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("unexpected err: %v %v", err, issues)
+	}
+	wantText := "(A) 2024-04-01 Call dentist @health +phone +q2 due:2024-04-12\n" +
+		"(B) Buy milk due:2024-04-10\n" +
+		"x 2024-04-08 2024-04-02 File taxes @finance due:2024-04-09 pri:C\n" +
+		"x Old chore due:2024-04-03 pri:A\n"
+	if sb.String() != wantText {
+		t.Fatalf("expected:\n%s\ngot:\n%s", wantText, sb.String())
+	}
+
+	got, issues, err := ImportTodoTxt(strings.NewReader(sb.String()), now)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("unexpected err: %v %v", err, issues)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected\n%+v\ngot\n%+v", want, got)
+	}
+}
+
+func TestExportTodoTxtReportsDropped(t *testing.T) {
+	all := []Task{
+		{Id: 1, Title: "Reply to @bob", Category: "pet stuff", Tags: []string{"a b"}, Description: "Details", Notes: []string{"n"}},
+		{Id: 2, Title: "Plain", Status: Completed, CreatedAt: time.Now(), Recurrence: "FREQ=DAILY", Description: "More"},
+	}
+	issues, err := ExportTodoTxt(&strings.Builder{}, all, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	got := []string{}
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		"task 1: title word '@bob' will be read back as a todo.txt field",
+		"task 1: category 'pet stuff' written as @pet_stuff",
+		"task 1: tag 'a b' written as +a_b",
+		"description dropped from 2 task(s)",
+		"notes dropped from 1 task(s)",
+		"recurrence dropped from 1 task(s)",
+		"creation date dropped from 1 task(s)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestImportTodoTxtTableDriven(t *testing.T) {
+	now := time.Date(2024, 4, 10, 8, 0, 0, 0, time.UTC)
+	today := DueDate(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		line       string
+		want       []Task
+		wantIssues []string
+	}{
+		{
+			line:       "Water plants @Home @garden +chores t:2024-04-01 due:tomorrow",
+			want:       []Task{{Title: "Water plants t:2024-04-01", Priority: Medium, DueDate: today, Category: "home", Tags: []string{"chores"}, Status: Pending}},
+			wantIssues: []string{"line 1: extra context @garden dropped", "line 1: invalid due date 'tomorrow', using today"},
+		},
+		{
+			line:       "(E) Someday maybe",
+			want:       []Task{{Title: "Someday maybe", Priority: Low, DueDate: today, Status: Pending}},
+			wantIssues: []string{"line 1: priority (E) imported as LOW"},
+		},
+		{
+			line: "x (A) 2024-04-09 Done with priority",
+			want: []Task{{Title: "Done with priority", Priority: High, DueDate: today, Status: Completed, CompletedAt: time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			line:       "\n(A) @work +tag\n",
+			wantIssues: []string{"line 2: task has no title"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, issues, err := ImportTodoTxt(strings.NewReader(tt.line), now)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected\n%+v\ngot\n%+v", tt.want, got)
+			}
+			gotIssues := []string{}
+			for _, issue := range issues {
+				gotIssues = append(gotIssues, issue.String())
+			}
+			if strings.Join(gotIssues, "\n") != strings.Join(tt.wantIssues, "\n") {
+				t.Fatalf("expected issues %q, got %q", tt.wantIssues, gotIssues)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+type View struct {
+	Name    string   `json:"name"`
+	Query   string   `json:"query"`
+	Sort    string   `json:"sort,omitempty"`
+	Format  string   `json:"format,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+func (m *manager) SaveView(view View) error {
+	if strings.TrimSpace(view.Name) == "" {
+		return fmt.Errorf("view name cannot be empty")
+	}
+	i := slices.IndexFunc(m.views, func(v View) bool { return v.Name == view.Name })
+	if i >= 0 {
+		m.views[i] = view
+	} else {
+		m.views = append(m.views, view)
+	}
+	return m.saveViewsToFile()
+}
+
+func (m *manager) GetView(name string) (*View, error) {
+	for _, view := range m.views {
+		if view.Name == name {
+			return &view, nil
+		}
+	}
+	return nil, fmt.Errorf("view '%s' %w", name, ErrNotFound)
+}
+
+func (m *manager) ListViews() ([]View, error) {
+	views := slices.Clone(m.views)
+	slices.SortFunc(views, func(a, b View) int { return strings.Compare(a.Name, b.Name) })
+	return views, nil
+}
+
+func (m *manager) DeleteView(name string) error {
+	i := slices.IndexFunc(m.views, func(v View) bool { return v.Name == name })
+	if i < 0 {
+		return fmt.Errorf("view '%s' %w", name, ErrNotFound)
+	}
+	m.views = slices.Delete(m.views, i, i+1)
+	return m.saveViewsToFile()
+}
+
+func viewsFilenameFor(filename string) string {
+	if strings.TrimSpace(filename) == "" {
+		return ""
+	}
+	return strings.TrimSuffix(filename, ".json") + ".views.json"
+}
+
+func (m *manager) loadViewsFromFile() error {
+	if m.viewsFilename == "" {
+		return nil
+	}
+	if _, err := os.Stat(m.viewsFilename); os.IsNotExist(err) {
+		return nil
+	}
+	file, err := os.ReadFile(m.viewsFilename)
+	if err != nil {
+		return storageError("read", m.viewsFilename, err)
+	}
+	if err := json.Unmarshal(file, &m.views); err != nil {
+		return storageError("decode", m.viewsFilename, err)
+	}
+	return nil
+}
+
+func (m *manager) saveViewsToFile() error {
+	if m.batch != nil {
+		m.batch.changes++
+		return nil
+	}
+	if m.viewsFilename == "" {
+		return nil
+	}
+	file, err := json.Marshal(m.views)
+	if err != nil {
+		return storageError("encode", m.viewsFilename, err)
+	}
+	if err := os.WriteFile(m.viewsFilename, file, 0644); err != nil {
+		return storageError("write", m.viewsFilename, err)
+	}
+	return nil
+}